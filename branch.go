@@ -0,0 +1,24 @@
+package httphandler
+
+import "net/http"
+
+// Branch returns a RequestDecodeFunc that decodes the request with then if selector returns
+// true, and with otherwise if it returns false, so that a single handler can serve requests
+// authenticated in different ways, e.g.
+//
+//	httphandler.Branch(HasAPIKey, APIKeyAuth, SessionAuth)
+//
+// An error of selector is returned as-is and neither branch runs.
+func Branch[T any](selector RequestDecodeFunc[bool], then, otherwise RequestDecodeFunc[T]) RequestDecodeFunc[T] {
+	return func(r *http.Request) (T, error) {
+		ok, err := selector(r)
+		if err != nil {
+			var v T
+			return v, err
+		}
+		if ok {
+			return then(r)
+		}
+		return otherwise(r)
+	}
+}
@@ -0,0 +1,81 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestBranch(t *testing.T) {
+	t.Parallel()
+
+	hasAPIKey := func(r *http.Request) (bool, error) {
+		if r.Header.Get("Authorization") != "" && r.Header.Get("X-Api-Key") != "" {
+			return false, errors.New("ambiguous credentials")
+		}
+		return r.Header.Get("X-Api-Key") != "", nil
+	}
+	apiKey := func(r *http.Request) (string, error) {
+		return "key:" + r.Header.Get("X-Api-Key"), nil
+	}
+	session := func(r *http.Request) (string, error) {
+		v := r.Header.Get("Authorization")
+		if v == "" {
+			return "", errors.New("missing session")
+		}
+		return "session:" + v, nil
+	}
+
+	testCases := []struct {
+		desc         string
+		givenHeaders map[string]string
+		want         string
+		wantErr      bool
+	}{
+		{
+			desc:         "api key",
+			givenHeaders: map[string]string{"X-Api-Key": "k1"},
+			want:         "key:k1",
+		},
+		{
+			desc:         "session",
+			givenHeaders: map[string]string{"Authorization": "s1"},
+			want:         "session:s1",
+		},
+		{
+			desc:    "otherwise fails",
+			wantErr: true,
+		},
+		{
+			desc:         "selector fails",
+			givenHeaders: map[string]string{"X-Api-Key": "k1", "Authorization": "s1"},
+			wantErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.givenHeaders {
+				r.Header.Set(k, v)
+			}
+
+			// When:
+			got, err := httphandler.Branch(hasAPIKey, apiKey, session)(r)
+
+			// Then:
+			if (err != nil) != tc.wantErr {
+				t.Errorf("error: want %t, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("value: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
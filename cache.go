@@ -0,0 +1,228 @@
+package httphandler
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DecodeCache stores decoded values for CacheDecode. Implementations must be safe for
+// concurrent use; they may be backed by a shared store such as Redis as long as the values
+// they return have the type that was stored.
+type DecodeCache interface {
+	// Get returns the value stored under key, and false if there is none or it has expired.
+	Get(key string) (any, bool)
+	// Set stores value under key for ttl.
+	Set(key string, value any, ttl time.Duration)
+}
+
+// CacheDecode wraps decode so that its result is cached per key for ttl, across requests, e.g.
+// for a tenant lookup that would otherwise hit the database on every request.
+// key derives the cache key from the request; an empty key bypasses the cache. Errors are not
+// cached. Concurrent misses for the same key share one call of decode, made with the request
+// that missed first; if that request is cancelled or times out before decode returns, the
+// waiting requests make another call rather than failing with its context error.
+// If cache is nil, a new MemoryCache is used. Decoders that share a cache must use distinct keys.
+//
+// CacheDecode wraps one decoder rather than being a handler option such as a WithStageCache,
+// because usually only some stages of a pipeline can be cached, e.g. the tenant lookup, while
+// others, such as the request body, must run on every request; wrap the stage passed to
+// CombineWith instead.
+func CacheDecode[T any](decode RequestDecodeFunc[T], cache DecodeCache, ttl time.Duration, key func(r *http.Request) string) RequestDecodeFunc[T] {
+	if cache == nil {
+		cache = NewMemoryCache()
+	}
+
+	var mu sync.Mutex
+	calls := map[string]*decodeCall[T]{}
+
+	return func(r *http.Request) (T, error) {
+		k := key(r)
+		if k == "" {
+			return decode(r)
+		}
+
+		if cached, ok := cache.Get(k); ok {
+			if v, ok := cached.(T); ok {
+				return v, nil
+			}
+		}
+
+		mu.Lock()
+		for {
+			call, ok := calls[k]
+			if !ok {
+				break
+			}
+			mu.Unlock()
+			select {
+			case <-call.done:
+				if !isContextError(call.err) || r.Context().Err() != nil {
+					return call.value, call.err
+				}
+				// The context of the request that made the call was done, not the one of r.
+			case <-r.Context().Done():
+				var zero T
+				return zero, r.Context().Err()
+			}
+			mu.Lock()
+		}
+		call := &decodeCall[T]{done: make(chan struct{}), err: errCachedDecodePanicked}
+		calls[k] = call
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			delete(calls, k)
+			mu.Unlock()
+			close(call.done)
+		}()
+
+		call.value, call.err = decode(r)
+		if call.err == nil {
+			cache.Set(k, call.value, ttl)
+		}
+
+		return call.value, call.err
+	}
+}
+
+// errCachedDecodePanicked is returned to the requests waiting for a call of a cached decoder
+// that panicked.
+var errCachedDecodePanicked = errors.New("cached decoder panicked")
+
+// isContextError reports whether err is the error of a context that was cancelled or timed out.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// decodeCall is a call of a cached decoder that concurrent misses for its key wait for.
+type decodeCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Ensure MemoryCache implements DecodeCache.
+var _ DecodeCache = (*MemoryCache)(nil)
+
+const (
+	// defaultMemoryCacheEntries is the default maximum number of entries of a MemoryCache.
+	defaultMemoryCacheEntries = 10000
+	// memoryCacheSweepInterval is how often a MemoryCache removes all its expired entries.
+	memoryCacheSweepInterval = time.Minute
+)
+
+// MemoryCache is an in-memory DecodeCache. It holds at most 10000 entries by default, see
+// WithMaxEntries, evicting the least recently used one when full. Expired entries are removed
+// when they are read, and all of them at most once a minute when a value is stored. It uses
+// the package Clock.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	// lru holds the *memoryCacheEntry values, the most recently used first.
+	lru       *list.List
+	nextSweep time.Time
+}
+
+// memoryCacheEntry is a value of a MemoryCache with its key and expiry.
+type memoryCacheEntry struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		maxEntries: defaultMemoryCacheEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+// WithMaxEntries sets the maximum number of entries of the cache. A value of 0 or less
+// removes the limit.
+func (c *MemoryCache) WithMaxEntries(n int) *MemoryCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxEntries = n
+	c.evict()
+	return c
+}
+
+// Get returns the value stored under key, and false if there is none or it has expired.
+func (c *MemoryCache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryCacheEntry)
+	if !Now().Before(entry.expires) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set stores value under key for ttl.
+func (c *MemoryCache) Set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := Now()
+	if !now.Before(c.nextSweep) {
+		c.sweep(now)
+		c.nextSweep = now.Add(memoryCacheSweepInterval)
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*memoryCacheEntry)
+		entry.value, entry.expires = value, now.Add(ttl)
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&memoryCacheEntry{key: key, value: value, expires: now.Add(ttl)})
+	c.evict()
+}
+
+// Len returns the number of entries of the cache, including expired ones not removed yet.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// sweep removes the entries expired at now.
+func (c *MemoryCache) sweep(now time.Time) {
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if !now.Before(elem.Value.(*memoryCacheEntry).expires) {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
+
+// evict removes the least recently used entries beyond the maximum number of entries.
+func (c *MemoryCache) evict() {
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove removes the entry of elem.
+func (c *MemoryCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*memoryCacheEntry).key)
+}
@@ -0,0 +1,232 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestCacheDecode(t *testing.T) {
+	t.Parallel()
+
+	errLookup := errors.New("lookup failure")
+
+	testCases := []struct {
+		desc      string
+		givenKeys []string
+		givenErr  error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			desc:      "same key",
+			givenKeys: []string{"acme", "acme", "acme"},
+			wantCalls: 1,
+		},
+		{
+			desc:      "different keys",
+			givenKeys: []string{"acme", "globex", "acme"},
+			wantCalls: 2,
+		},
+		{
+			desc:      "empty key",
+			givenKeys: []string{"", ""},
+			wantCalls: 2,
+		},
+		{
+			desc:      "error",
+			givenKeys: []string{"acme", "acme"},
+			givenErr:  errLookup,
+			wantCalls: 2,
+			wantErr:   errLookup,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			calls := 0
+			decode := func(r *http.Request) (string, error) {
+				calls++
+				if tc.givenErr != nil {
+					return "", tc.givenErr
+				}
+				return "tenant-" + r.Header.Get("X-Tenant"), nil
+			}
+			given := httphandler.CacheDecode(decode, nil, time.Minute, func(r *http.Request) string {
+				return r.Header.Get("X-Tenant")
+			})
+
+			for _, key := range tc.givenKeys {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.Header.Set("X-Tenant", key)
+
+				// When:
+				got, err := given(r)
+
+				// Then:
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("error: want %v, got %v", tc.wantErr, err)
+				}
+				if err == nil && got != "tenant-"+key {
+					t.Errorf("value: want %s, got %s", "tenant-"+key, got)
+				}
+			}
+
+			if calls != tc.wantCalls {
+				t.Errorf("decode calls: want %d, got %d", tc.wantCalls, calls)
+			}
+		})
+	}
+}
+
+// TestMemoryCache is not parallel because it changes the package-level clock.
+func TestMemoryCache(t *testing.T) {
+	// Given:
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	httphandler.SetClock(fixedClock(now))
+	defer httphandler.SetClock(nil)
+
+	cache := httphandler.NewMemoryCache()
+	cache.Set("acme", 1, time.Minute)
+
+	// When:
+	got, ok := cache.Get("acme")
+
+	// Then:
+	if !ok || got != 1 {
+		t.Errorf("before expiry: want 1, got %v (%t)", got, ok)
+	}
+
+	// When:
+	httphandler.SetClock(fixedClock(now.Add(time.Minute)))
+	got, ok = cache.Get("acme")
+
+	// Then:
+	if ok {
+		t.Errorf("after expiry: want none, got %v", got)
+	}
+}
+
+func TestCacheDecode_ConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	// Given: a slow decoder
+	var calls atomic.Int32
+	release := make(chan struct{})
+	given := httphandler.CacheDecode(func(r *http.Request) (string, error) {
+		calls.Add(1)
+		<-release
+		return "tenant", nil
+	}, nil, time.Minute, func(r *http.Request) string {
+		return "acme"
+	})
+
+	// When: several requests miss at the same time
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := given(httptest.NewRequest(http.MethodGet, "/", nil)); err != nil || got != "tenant" {
+				t.Errorf("value: want tenant, got %q (%v)", got, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Then: the decoder is called once
+	if got := calls.Load(); got != 1 {
+		t.Errorf("decode calls: want 1, got %d", got)
+	}
+}
+
+// TestMemoryCache_Bounded is not parallel because it changes the package-level clock.
+func TestMemoryCache_Bounded(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	httphandler.SetClock(fixedClock(now))
+	defer httphandler.SetClock(nil)
+
+	// Given: a full cache whose first entry was used last
+	cache := httphandler.NewMemoryCache().WithMaxEntries(2)
+	cache.Set("acme", 1, time.Minute)
+	cache.Set("globex", 2, time.Minute)
+	cache.Get("acme")
+
+	// When:
+	cache.Set("initech", 3, time.Minute)
+
+	// Then: the least recently used entry is evicted
+	if _, ok := cache.Get("globex"); ok {
+		t.Error("globex: want evicted, got found")
+	}
+	if _, ok := cache.Get("acme"); !ok {
+		t.Error("acme: want found, got evicted")
+	}
+
+	// When: a value is stored after the others expired
+	httphandler.SetClock(fixedClock(now.Add(2 * time.Minute)))
+	cache.Set("umbrella", 4, time.Minute)
+
+	// Then: the expired entries are swept without being read
+	if got := cache.Len(); got != 1 {
+		t.Errorf("entries: want 1, got %d", got)
+	}
+}
+
+func TestCacheDecode_FirstRequestCancelled(t *testing.T) {
+	t.Parallel()
+
+	// Given: a decoder that waits for the context of its request
+	var calls atomic.Int32
+	started := make(chan struct{})
+	given := httphandler.CacheDecode(func(r *http.Request) (string, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-r.Context().Done()
+			return "", r.Context().Err()
+		}
+		return "tenant", nil
+	}, nil, time.Minute, func(r *http.Request) string {
+		return "acme"
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		given(httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	}()
+	<-started
+
+	// When: the first request is cancelled while a second one waits for its call
+	var got string
+	var err error
+	secondDone := make(chan struct{})
+	go func() {
+		defer close(secondDone)
+		got, err = given(httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-firstDone
+	<-secondDone
+
+	// Then: the second request decodes the value itself
+	if err != nil || got != "tenant" {
+		t.Errorf("value: want tenant, got %q (%v)", got, err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("decode calls: want 2, got %d", got)
+	}
+}
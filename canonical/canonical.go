@@ -0,0 +1,256 @@
+// Package canonical builds canonical request strings for signature schemes, so that signed-URL,
+// HMAC webhook and SigV4-style verifiers sign exactly the same bytes as their clients.
+//
+// The format is the canonical request of AWS Signature Version 4:
+//
+//	<method>\n
+//	<canonical path>\n
+//	<canonical query>\n
+//	<canonical headers, one "name:value\n" per header>\n
+//	<signed header names, separated by ';'>\n
+//	<hex SHA-256 of the body>
+package canonical
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// EmptyBodyHash is the hex SHA-256 of an empty body.
+const EmptyBodyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Options configures how a request is canonicalized.
+type Options struct {
+	// Headers are the names of the headers to include, case-insensitively. "host" is read from
+	// the Host of the request. Headers missing from the request are included with an empty value.
+	Headers []string
+	// NormalizePath removes "." and ".." segments and duplicate slashes from the path.
+	NormalizePath bool
+	// DoubleEncodePath escapes each path segment twice, as SigV4 does for services other than S3.
+	DoubleEncodePath bool
+	// PayloadHash, if not empty, is used instead of the hash of the body, e.g. "UNSIGNED-PAYLOAD".
+	PayloadHash string
+}
+
+// Request is a canonicalized request.
+type Request struct {
+	Method        string
+	Path          string
+	Query         string
+	Headers       string
+	SignedHeaders string
+	PayloadHash   string
+}
+
+// String returns the canonical request string.
+func (c Request) String() string {
+	return strings.Join([]string{
+		c.Method,
+		c.Path,
+		c.Query,
+		c.Headers,
+		c.SignedHeaders,
+		c.PayloadHash,
+	}, "\n")
+}
+
+// Build canonicalizes r with body, which must be the content of the request body, e.g. as read
+// by ReadBody. It fails if the query string is malformed, see ParseQuery.
+func Build(r *http.Request, body []byte, opts Options) (Request, error) {
+	query, err := ParseQuery(r.URL.RawQuery)
+	if err != nil {
+		return Request{}, err
+	}
+
+	headers, signed := Headers(r, opts.Headers)
+
+	payloadHash := opts.PayloadHash
+	if payloadHash == "" {
+		payloadHash = HashBody(body)
+	}
+
+	return Request{
+		Method:        strings.ToUpper(r.Method),
+		Path:          Path(r.URL.Path, opts.NormalizePath, opts.DoubleEncodePath),
+		Query:         Query(query),
+		Headers:       headers,
+		SignedHeaders: signed,
+		PayloadHash:   payloadHash,
+	}, nil
+}
+
+// Path returns the canonical form of the unescaped path p: each segment escaped with Escape,
+// and "/" for an empty path.
+func Path(p string, normalize, doubleEncode bool) string {
+	if p == "" {
+		p = "/"
+	}
+	if normalize {
+		cleaned := path.Clean(p)
+		if strings.HasSuffix(p, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		p = cleaned
+	}
+
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segment = Escape(segment)
+		if doubleEncode {
+			segment = Escape(segment)
+		}
+		segments[i] = segment
+	}
+	return strings.Join(segments, "/")
+}
+
+// ParseQuery parses a raw query string. Unlike url.ParseQuery, '+' is kept as is instead of
+// being read as a space, since signing clients escape spaces as %20 and may send '+' unescaped.
+func ParseQuery(rawQuery string) (url.Values, error) {
+	query := url.Values{}
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(param, "=")
+		name, err := url.PathUnescape(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err = url.PathUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		query[name] = append(query[name], value)
+	}
+	return query, nil
+}
+
+// Query returns the canonical form of the query: the escaped parameters sorted by name and
+// then by value, joined with '&'. Parameters without a value are written as "name=".
+func Query(query url.Values) string {
+	type param struct{ name, value string }
+	params := make([]param, 0, len(query))
+	for name, values := range query {
+		name = Escape(name)
+		for _, value := range values {
+			params = append(params, param{name: name, value: Escape(value)})
+		}
+	}
+	// Sort by name first: sorting "name=value" strings would put "a-=1" before "a=1".
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].name != params[j].name {
+			return params[i].name < params[j].name
+		}
+		return params[i].value < params[j].value
+	})
+
+	var b strings.Builder
+	for i, p := range params {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(p.name)
+		b.WriteByte('=')
+		b.WriteString(p.value)
+	}
+	return b.String()
+}
+
+// Headers returns the canonical headers and the signed header names of r for the given header
+// names. Names are lower-cased and sorted, values are trimmed with sequential spaces collapsed,
+// and multiple values of a header are joined with ','.
+func Headers(r *http.Request, names []string) (headers, signed string) {
+	lower := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		lower = append(lower, name)
+	}
+	sort.Strings(lower)
+
+	var b strings.Builder
+	for _, name := range lower {
+		var values []string
+		if name == "host" {
+			values = []string{host(r)}
+		} else {
+			values = append([]string(nil), r.Header.Values(name)...)
+		}
+		for i, value := range values {
+			values[i] = strings.Join(strings.Fields(value), " ")
+		}
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(values, ","))
+		b.WriteByte('\n')
+	}
+	return b.String(), strings.Join(lower, ";")
+}
+
+// host returns the host of r, which servers keep in r.Host and clients may keep in r.URL.Host.
+func host(r *http.Request) string {
+	if r.Host != "" {
+		return r.Host
+	}
+	return r.URL.Host
+}
+
+// Escape escapes s as in RFC 3986: every byte except the unreserved characters A-Z, a-z,
+// 0-9, '-', '.', '_' and '~' is written as %XX with upper-case hex digits. Unlike
+// url.QueryEscape, spaces are escaped as %20.
+func Escape(s string) string {
+	const upperhex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if unreserved(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(upperhex[c>>4])
+		b.WriteByte(upperhex[c&15])
+	}
+	return b.String()
+}
+
+// unreserved reports whether c is an unreserved character of RFC 3986.
+func unreserved(c byte) bool {
+	return 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// HashBody returns the hex SHA-256 of body.
+func HashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// ReadBody reads the body of r and replaces it so that it can be read again, e.g. by the
+// decoder of the handler after the signature is verified.
+func ReadBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
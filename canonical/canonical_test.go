@@ -0,0 +1,236 @@
+package canonical_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler/canonical"
+)
+
+// newRequest returns a request like the ones of the AWS SigV4 test suite.
+func newRequest(method, target string, header map[string][]string, body string) *http.Request {
+	r := httptest.NewRequest(method, "http://example.amazonaws.com"+target, strings.NewReader(body))
+	r.Header.Set("X-Amz-Date", "20150830T123600Z")
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	return r
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	sigv4 := canonical.Options{Headers: []string{"Host", "X-Amz-Date"}, NormalizePath: true}
+
+	testCases := []struct {
+		desc      string
+		given     *http.Request
+		givenOpts canonical.Options
+		want      string
+		wantErr   bool
+	}{
+		{
+			desc:      "get vanilla",
+			given:     newRequest(http.MethodGet, "/", nil, ""),
+			givenOpts: sigv4,
+			want: "GET\n/\n\n" +
+				"host:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+				"host;x-amz-date\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "query sorted by key",
+			given:     newRequest(http.MethodGet, "/?Param2=value2&Param1=value1", nil, ""),
+			givenOpts: sigv4,
+			want: "GET\n/\nParam1=value1&Param2=value2\n" +
+				"host:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+				"host;x-amz-date\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "query sorted by value | case sensitive",
+			given:     newRequest(http.MethodGet, "/?Param1=value2&Param1=Value1", nil, ""),
+			givenOpts: sigv4,
+			want: "GET\n/\nParam1=Value1&Param1=value2\n" +
+				"host:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+				"host;x-amz-date\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "utf-8 query key",
+			given:     newRequest(http.MethodGet, "/?%E1%88%B4=bar", nil, ""),
+			givenOpts: sigv4,
+			want: "GET\n/\n%E1%88%B4=bar\n" +
+				"host:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+				"host;x-amz-date\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "space in path",
+			given:     newRequest(http.MethodGet, "/example%20space/", nil, ""),
+			givenOpts: sigv4,
+			want: "GET\n/example%20space/\n\n" +
+				"host:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+				"host;x-amz-date\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "utf-8 path",
+			given:     newRequest(http.MethodGet, "/%E1%88%B4", nil, ""),
+			givenOpts: sigv4,
+			want: "GET\n/%E1%88%B4\n\n" +
+				"host:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+				"host;x-amz-date\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "header values trimmed and joined",
+			given:     newRequest(http.MethodPost, "/", map[string][]string{"My-Header1": {" value1"}, "My-Header2": {`"a   b   c"`}, "My-Header3": {"value2", "value2", "value1"}}, ""),
+			givenOpts: canonical.Options{Headers: []string{"host", "my-header1", "my-header2", "My-Header3", "x-amz-date"}},
+			want: "POST\n/\n\n" +
+				"host:example.amazonaws.com\nmy-header1:value1\nmy-header2:\"a b c\"\nmy-header3:value2,value2,value1\nx-amz-date:20150830T123600Z\n\n" +
+				"host;my-header1;my-header2;my-header3;x-amz-date\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "body hash",
+			given:     newRequest(http.MethodPost, "/", map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}}, "Param1=value1"),
+			givenOpts: canonical.Options{Headers: []string{"Content-Type", "Host", "X-Amz-Date"}},
+			want: "POST\n/\n\n" +
+				"content-type:application/x-www-form-urlencoded\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\n" +
+				"content-type;host;x-amz-date\n9095672bbd1f56dfc5b65f3e153adc8731a4a654192329106275f4c7b24d0b6e",
+		},
+		{
+			desc:      "unsigned payload | missing header | duplicate name",
+			given:     newRequest(http.MethodPut, "/", nil, "ignored"),
+			givenOpts: canonical.Options{Headers: []string{"host", "X-Missing", "HOST"}, PayloadHash: "UNSIGNED-PAYLOAD"},
+			want: "PUT\n/\n\n" +
+				"host:example.amazonaws.com\nx-missing:\n\n" +
+				"host;x-missing\nUNSIGNED-PAYLOAD",
+		},
+		{
+			desc:      "double encoded path",
+			given:     newRequest(http.MethodGet, "/a%20b/c", nil, ""),
+			givenOpts: canonical.Options{DoubleEncodePath: true},
+			want:      "GET\n/a%2520b/c\n\n\n\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "plus kept in query",
+			given:     newRequest(http.MethodGet, "/?q=a+b&r=a%20b", nil, ""),
+			givenOpts: canonical.Options{},
+			want:      "GET\n/\nq=a%2Bb&r=a%20b\n\n\n" + canonical.EmptyBodyHash,
+		},
+		{
+			desc:      "malformed query",
+			given:     newRequest(http.MethodGet, "/?q=%zz", nil, ""),
+			givenOpts: sigv4,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			body, err := canonical.ReadBody(tc.given)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+
+			// When:
+			got, err := canonical.Build(tc.given, body, tc.givenOpts)
+
+			// Then:
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error: want %t, got %v", tc.wantErr, err)
+			}
+			if err == nil && got.String() != tc.want {
+				t.Errorf("canonical request: want\n%s\ngot\n%s", tc.want, got.String())
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc           string
+		given          string
+		givenNormalize bool
+		want           string
+	}{
+		{desc: "empty", given: "", want: "/"},
+		{desc: "root", given: "/", want: "/"},
+		{desc: "reserved characters", given: "/a:b@c/d;e=f", want: "/a%3Ab%40c/d%3Be%3Df"},
+		{desc: "unreserved characters", given: "/-._~AZaz09", want: "/-._~AZaz09"},
+		{desc: "not normalized", given: "//example//./a/..", want: "//example//./a/.."},
+		{desc: "slashes", given: "//example//", givenNormalize: true, want: "/example/"},
+		{desc: "relative", given: "/example/..", givenNormalize: true, want: "/"},
+		{desc: "relative relative", given: "/example1/example2/../..", givenNormalize: true, want: "/"},
+		{desc: "slash dot slash", given: "/./", givenNormalize: true, want: "/"},
+		{desc: "slash pointless dot", given: "/./example", givenNormalize: true, want: "/example"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got := canonical.Path(tc.given, tc.givenNormalize, false)
+
+			// Then:
+			if got != tc.want {
+				t.Errorf("path: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc  string
+		given url.Values
+		want  string
+	}{
+		{desc: "empty", given: nil, want: ""},
+		{desc: "no value", given: url.Values{"Param1": {""}}, want: "Param1="},
+		{desc: "unreserved", given: url.Values{"-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz": {"-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"}}, want: "-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz=-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"},
+		{desc: "escaped", given: url.Values{"a b": {"c/d=e&f"}}, want: "a%20b=c%2Fd%3De%26f"},
+		{desc: "sorted by escaped key", given: url.Values{"b": {"1"}, "a_": {"2"}, "a": {"3"}}, want: "a=3&a_=2&b=1"},
+		{desc: "sorted by key before value", given: url.Values{"a-": {"1"}, "a": {"2", "1"}}, want: "a=1&a=2&a-=1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got := canonical.Query(tc.given)
+
+			// Then:
+			if got != tc.want {
+				t.Errorf("query: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestReadBody(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload"))
+
+	// When:
+	body, err := canonical.ReadBody(r)
+
+	// Then: the body can be read again
+	if err != nil {
+		t.Fatalf("error: want nil, got %v", err)
+	}
+	again, _ := io.ReadAll(r.Body)
+	if string(body) != "payload" || string(again) != "payload" {
+		t.Errorf("body: want %q twice, got %q and %q", "payload", body, again)
+	}
+}
@@ -0,0 +1,250 @@
+package httphandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// Catalog collects the example Responders of routes, set with WithExample, and serves them so
+// that frontend teams can browse live example payloads without reading Go code.
+// It is meant for development: mount it only on development servers, e.g.
+//
+//	if dev {
+//		mux.Handle("GET /_examples", catalog)
+//	}
+type Catalog struct {
+	mu     sync.RWMutex
+	routes []catalogRoute
+}
+
+// catalogRoute holds the examples of a route in the order they were added, and its pipeline
+// and documentation if its handler was created with WithCatalog.
+type catalogRoute struct {
+	route    string
+	examples []catalogExample
+	pipeline *RoutePipeline
+	doc      RouteDoc
+}
+
+// catalogExample is a named example Responder.
+type catalogExample struct {
+	name string
+	res  Responder
+}
+
+// NewCatalog creates an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{}
+}
+
+// Add adds an example Responder to route, e.g. for a route whose handler does not exist yet.
+func (c *Catalog) Add(route, name string, res Responder) *Catalog {
+	c.add(route, []catalogExample{{name: name, res: res}}, nil, RouteDoc{})
+	return c
+}
+
+// add adds the examples, the pipeline, if not nil, and the documentation, if not empty, of a
+// route. Examples of a route that is added again are appended, and its pipeline and
+// documentation are replaced.
+func (c *Catalog) add(route string, examples []catalogExample, pipeline *RoutePipeline, doc RouteDoc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.routes {
+		if c.routes[i].route == route {
+			c.routes[i].examples = append(c.routes[i].examples, examples...)
+			if pipeline != nil {
+				c.routes[i].pipeline = pipeline
+			}
+			if !doc.empty() {
+				c.routes[i].doc = doc
+			}
+			return
+		}
+	}
+	c.routes = append(c.routes, catalogRoute{route: route, examples: examples, pipeline: pipeline, doc: doc})
+}
+
+// lookup returns the example of a route with the given name.
+func (c *Catalog) lookup(route, name string) (Responder, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, cr := range c.routes {
+		if cr.route != route {
+			continue
+		}
+		for _, ex := range cr.examples {
+			if ex.name == name {
+				return ex.res, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// CatalogEntry describes the documentation and the examples of a route in the catalog index.
+type CatalogEntry struct {
+	Route string `json:"route"`
+	RouteDoc
+	Examples []CatalogExample `json:"examples"`
+}
+
+// RouteDoc is the documentation of a route, set with WithSummary, WithDescription, WithTag,
+// WithAccept, WithSchema, WithRateLimit and WithScopes.
+type RouteDoc struct {
+	Summary     string   `json:"summary,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	// Accept are the media types accepted in request bodies.
+	Accept []string `json:"accept,omitempty"`
+	// Schema describes the request body, e.g. as a JSON Schema document.
+	Schema any `json:"schema,omitempty"`
+	// RateLimit is the rate-limit policy of the route, e.g. "100;w=60".
+	RateLimit string `json:"rateLimit,omitempty"`
+	// Scopes are the scopes the route requires, see RequireScopes.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// empty reports whether d has no documentation.
+func (d RouteDoc) empty() bool {
+	return d.Summary == "" && d.Description == "" && len(d.Tags) == 0 && len(d.Accept) == 0 &&
+		d.Schema == nil && d.RateLimit == "" && len(d.Scopes) == 0
+}
+
+// CatalogExample describes an example in the catalog index.
+type CatalogExample struct {
+	Name string `json:"name"`
+	// Href is the URL, relative to the catalog, that renders the example.
+	Href string `json:"href"`
+}
+
+// Entries returns the routes with examples or documentation, with their examples, in the
+// order they were added.
+func (c *Catalog) Entries() []CatalogEntry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entries := make([]CatalogEntry, 0, len(c.routes))
+	for _, cr := range c.routes {
+		if len(cr.examples) == 0 && cr.doc.empty() {
+			continue
+		}
+		entry := CatalogEntry{
+			Route:    cr.route,
+			RouteDoc: cr.doc,
+			Examples: make([]CatalogExample, 0, len(cr.examples)),
+		}
+		for _, ex := range cr.examples {
+			entry.Examples = append(entry.Examples, CatalogExample{
+				Name: ex.name,
+				Href: "?" + url.Values{"route": {cr.route}, "example": {ex.name}}.Encode(),
+			})
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// ServeHTTP renders the example named by the "example" query parameter of the route named by
+// the "route" query parameter, or 404 Not Found if there is none. Without these parameters,
+// it renders the index of all routes and examples as JSON.
+func (c *Catalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !query.Has("route") && !query.Has("example") {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(c.Entries()); err != nil {
+			WriteInternalServerError(w, nil, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		buf.WriteTo(w)
+		return
+	}
+
+	res, ok := c.lookup(query.Get("route"), query.Get("example"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	res.Respond(w, r)
+}
+
+// WithExample attaches an example Responder to the handler, e.g. a typical success and a
+// typical error. Examples are only collected if the handler also has WithCatalog, and are
+// never sent by the handler itself.
+func WithExample(name string, res Responder) HandlerOption {
+	return func(o *handlerOptions) {
+		o.examples = append(o.examples, catalogExample{name: name, res: res})
+	}
+}
+
+// WithSummary sets the one-line summary of the route in its catalog, see WithCatalog.
+func WithSummary(summary string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.doc.Summary = summary
+	}
+}
+
+// WithDescription sets the description of the route in its catalog, see WithCatalog, so that
+// the documentation of a route lives next to its definition.
+func WithDescription(description string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.doc.Description = description
+	}
+}
+
+// WithTag adds tags to the route in its catalog, see WithCatalog, e.g. to group routes by
+// resource.
+func WithTag(tags ...string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.doc.Tags = append(o.doc.Tags, tags...)
+	}
+}
+
+// WithAccept adds media types accepted in request bodies to the route in its catalog, see
+// WithCatalog and Catalog.MountOptions.
+func WithAccept(mediaTypes ...string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.doc.Accept = append(o.doc.Accept, mediaTypes...)
+	}
+}
+
+// WithSchema sets the schema of the request body of the route in its catalog, e.g. a JSON
+// Schema document, see WithCatalog and Catalog.MountOptions.
+func WithSchema(schema any) HandlerOption {
+	return func(o *handlerOptions) {
+		o.doc.Schema = schema
+	}
+}
+
+// WithRateLimit sets the rate-limit policy of the route in its catalog, in the format of the
+// RateLimit-Policy header, e.g. "100;w=60" for 100 requests per 60 seconds, see WithCatalog and
+// Catalog.MountOptions. It only documents the policy; the limit is enforced elsewhere.
+func WithRateLimit(policy string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.doc.RateLimit = policy
+	}
+}
+
+// WithScopes adds scopes that the route requires to the route in its catalog, see WithCatalog
+// and Catalog.MountOptions, usually the scopes checked by its RequireScopes decoder.
+func WithScopes(scopes ...string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.doc.Scopes = append(o.doc.Scopes, scopes...)
+	}
+}
+
+// WithCatalog adds the examples, the pipeline and the documentation of the handler to catalog
+// under route, e.g. "GET /users/{id}", when the handler is created.
+func WithCatalog(catalog *Catalog, route string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.catalog = catalog
+		o.route = route
+	}
+}
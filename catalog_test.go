@@ -0,0 +1,130 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func newTestCatalog() *httphandler.Catalog {
+	catalog := httphandler.NewCatalog()
+
+	httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return nil
+	},
+		httphandler.WithCatalog(catalog, "GET /users/{id}"),
+		httphandler.WithExample("found", jsonresp.Success(&map[string]string{"id": "1"})),
+		httphandler.WithExample("not found", jsonresp.Error(nil, "Not found", http.StatusNotFound)),
+	)
+	httphandler.HandleWithInput(func(r *http.Request, input map[string]string) httphandler.Responder {
+		return nil
+	},
+		httphandler.WithCatalog(catalog, "POST /users"),
+		httphandler.WithSummary("Create a user"),
+		httphandler.WithTag("users", "admin"),
+		httphandler.WithExample("created", jsonresp.Success(&map[string]string{"id": "2"}).WithStatus(http.StatusCreated)),
+	)
+	// A handler with documentation but no examples is listed.
+	httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return nil
+	},
+		httphandler.WithCatalog(catalog, "DELETE /users/{id}"),
+		httphandler.WithDescription("Deletes the user and revokes their sessions."),
+	)
+	// A handler without examples or documentation is not listed.
+	httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return nil
+	}, httphandler.WithCatalog(catalog, "GET /health"))
+
+	return catalog
+}
+
+func TestCatalog_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		given    string
+		wantCode int
+		wantBody string
+	}{
+		{
+			desc:     "index",
+			given:    "/_examples",
+			wantCode: http.StatusOK,
+			wantBody: `[{"route":"GET /users/{id}","examples":[` +
+				`{"name":"found","href":"?example=found&route=GET+%2Fusers%2F%7Bid%7D"},` +
+				`{"name":"not found","href":"?example=not+found&route=GET+%2Fusers%2F%7Bid%7D"}]},` +
+				`{"route":"POST /users","summary":"Create a user","tags":["users","admin"],` +
+				`"examples":[{"name":"created","href":"?example=created&route=POST+%2Fusers"}]},` +
+				`{"route":"DELETE /users/{id}","description":"Deletes the user and revokes their sessions.","examples":[]}]`,
+		},
+		{
+			desc:     "example",
+			given:    "/_examples?example=not+found&route=GET+%2Fusers%2F%7Bid%7D",
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"Not found"}`,
+		},
+		{
+			desc:     "example | custom status",
+			given:    "/_examples?example=created&route=POST+%2Fusers",
+			wantCode: http.StatusCreated,
+			wantBody: `{"id":"2"}`,
+		},
+		{
+			desc:     "unknown example",
+			given:    "/_examples?example=gone&route=POST+%2Fusers",
+			wantCode: http.StatusNotFound,
+			wantBody: "404 page not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			catalog := newTestCatalog()
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tc.given, nil)
+
+			// When:
+			catalog.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tc.wantBody {
+				t.Errorf("body: want %s, got %s", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestWithExample_NotSent(t *testing.T) {
+	t.Parallel()
+
+	// Given: a handler with an example
+	real, example := "real", "example"
+	h := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return jsonresp.Success(&real)
+	},
+		httphandler.WithCatalog(httphandler.NewCatalog(), "GET /"),
+		httphandler.WithExample("example", jsonresp.Success(&example)),
+	)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// When:
+	h(w, r)
+
+	// Then: the handler sends its own response
+	if got := strings.TrimSpace(w.Body.String()); got != `"real"` {
+		t.Errorf("body: want %s, got %s", `"real"`, got)
+	}
+}
@@ -0,0 +1,22 @@
+package cborresp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+var ErrDecode = errors.New("fail to decode cbor")
+
+// Body decodes a CBOR request body into a T, using its cbor tags and falling back to
+// its json tags. Use it with httphandler.HandleWithDecoder.
+func Body[T any](r *http.Request) (T, error) {
+	var v T
+	if err := cbor.NewDecoder(r.Body).Decode(&v); err != nil {
+		return v, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	return v, nil
+}
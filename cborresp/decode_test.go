@@ -0,0 +1,73 @@
+package cborresp_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler/cborresp"
+	"github.com/fxamacker/cbor/v2"
+)
+
+func TestBody(t *testing.T) {
+	t.Parallel()
+
+	type order struct {
+		ID    string   `json:"id"`
+		Items []string `cbor:"items"`
+	}
+
+	valid, err := cbor.Marshal(map[string]any{"id": "o-1", "items": []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc      string
+		givenBody []byte
+		want      order
+		wantErr   error
+	}{
+		{
+			desc:      "valid",
+			givenBody: valid,
+			want:      order{ID: "o-1", Items: []string{"a", "b"}},
+		},
+		{
+			desc:      "invalid",
+			givenBody: []byte{0xc1},
+			wantErr:   cborresp.ErrDecode,
+		},
+		{
+			desc:      "empty",
+			givenBody: nil,
+			wantErr:   cborresp.ErrDecode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewReader(tc.givenBody))
+
+			// When:
+			got, err := cborresp.Body[order](r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error: want %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			if got.ID != tc.want.ID || len(got.Items) != len(tc.want.Items) {
+				t.Errorf("input: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cborresp
+
+import (
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// Ensure errorResponder implements Responder.
+var _ httphandler.Responder = (*errorResponder)(nil)
+
+// Error creates a standardized error response with the specified error message and HTTP status code.
+// The body is a map with the message under "error", like the jsonresp error responses.
+// The 'err' parameter can be used for internal logging.
+func Error(err error, message string, code int) *errorResponder {
+	return &errorResponder{
+		statusCode: code,
+		errMessage: message,
+		err:        err,
+	}
+}
+
+// InternalServerError creates a standardized internal server error response.
+// The 'err' parameter can be used for internal logging.
+func InternalServerError(err error) *errorResponder {
+	return &errorResponder{
+		statusCode: http.StatusInternalServerError,
+		errMessage: "Internal Server Error",
+		err:        err,
+	}
+}
+
+// errorResponder handles error CBOR HTTP responses.
+type errorResponder struct {
+	logger     httphandler.Logger
+	header     http.Header
+	override   http.Header
+	statusCode int
+	cookies    []*http.Cookie
+	errMessage string
+	err        error
+}
+
+// Respond sends the CBOR error response with custom headers, cookies, and status code.
+func (res *errorResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+	httphandler.RecordError(r, res.err)
+
+	// Write the error CBOR response.
+	writeCBOR(w, map[string]string{"error": res.errMessage}, res.statusCode, res.logger)
+	httphandler.LogRequestError(res.logger, res.err)
+}
+
+// WithLogger sets the logger for the responder.
+func (res *errorResponder) WithLogger(logger httphandler.Logger) *errorResponder {
+	res.logger = logger
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *errorResponder) WithHeader(key, value string) *errorResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *errorResponder) SetHeader(key, value string) *errorResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *errorResponder) WithCookie(cookie *http.Cookie) *errorResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
@@ -0,0 +1,98 @@
+package cborresp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/cborresp"
+	"github.com/fxamacker/cbor/v2"
+)
+
+func TestError_Respond(t *testing.T) {
+	t.Parallel()
+
+	cookie := &http.Cookie{
+		Name:  "test-cookie-1",
+		Value: "cookie-value-1",
+	}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantCookies []*http.Cookie
+		wantMessage string
+	}{
+		{
+			desc:     "basic",
+			given:    cborresp.Error(errors.New("invalid id"), "Invalid ID provided", http.StatusBadRequest),
+			wantCode: http.StatusBadRequest,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/cbor",
+			},
+			wantMessage: "Invalid ID provided",
+		},
+		{
+			desc: "with everything",
+			given: cborresp.Error(errors.New("post not found"), "Post not found", http.StatusNotFound).
+				WithHeader("X-Test-1", "test value 1").
+				WithCookie(cookie),
+			wantCode: http.StatusNotFound,
+			wantHeaders: map[string]string{
+				"X-Test-1": "test value 1",
+			},
+			wantCookies: []*http.Cookie{cookie},
+			wantMessage: "Post not found",
+		},
+		{
+			desc:        "internal server error",
+			given:       cborresp.InternalServerError(errors.New("database failure")),
+			wantCode:    http.StatusInternalServerError,
+			wantMessage: "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test-error", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			gotCode := w.Code
+			if gotCode != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, gotCode)
+			}
+
+			gotHeaders := w.Header()
+			for key, wantValue := range tc.wantHeaders {
+				gotValue := gotHeaders.Get(key)
+				if gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			gotCookies := w.Result().Cookies()
+			if len(gotCookies) != len(tc.wantCookies) {
+				t.Errorf("cookie count: want %d, got %d", len(tc.wantCookies), len(gotCookies))
+			}
+
+			var gotBody map[string]string
+			if err := cbor.Unmarshal(w.Body.Bytes(), &gotBody); err != nil {
+				t.Fatalf("body: %v", err)
+			}
+			if gotBody["error"] != tc.wantMessage {
+				t.Errorf("body error: want %s, got %s", tc.wantMessage, gotBody["error"])
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/alvinchoong/go-httphandler/cborresp
+
+go 1.22
+
+require (
+	github.com/alvinchoong/go-httphandler v0.0.0
+	github.com/fxamacker/cbor/v2 v2.9.0
+)
+
+require github.com/x448/float16 v0.8.4 // indirect
+
+replace github.com/alvinchoong/go-httphandler => ../
@@ -0,0 +1,93 @@
+// Package cborresp provides CBOR (RFC 8949) responders and a request body decoder, for
+// constrained devices that send application/cbor. It is a separate module so that the
+// CBOR dependency stays optional.
+package cborresp
+
+import (
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// ContentType is the media type of CBOR documents.
+const ContentType = "application/cbor"
+
+// Ensure successResponder implements Responder.
+var _ httphandler.Responder = (*successResponder[any])(nil)
+
+// Success creates a new successResponder with the provided data and a default status code of 200 OK.
+// The data is encoded with its cbor tags, falling back to its json tags.
+func Success[T any](data *T) *successResponder[T] {
+	return &successResponder[T]{
+		statusCode: http.StatusOK,
+		data:       data,
+	}
+}
+
+// successResponder handles successful CBOR HTTP responses.
+type successResponder[T any] struct {
+	logger     httphandler.Logger
+	header     http.Header
+	override   http.Header
+	statusCode int
+	cookies    []*http.Cookie
+	data       *T
+}
+
+// Respond sends the CBOR response with custom headers, cookies and status code.
+func (res *successResponder[T]) Respond(w http.ResponseWriter, _ *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// Write the CBOR response.
+	if b := writeCBOR(w, res.data, res.statusCode, res.logger); b != nil {
+		httphandler.LogResponse(res.logger, res.statusCode, "response_bytes", len(b))
+	}
+}
+
+// WithLogger sets the logger for the responder.
+func (res *successResponder[T]) WithLogger(logger httphandler.Logger) *successResponder[T] {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *successResponder[T]) WithStatus(status int) *successResponder[T] {
+	res.statusCode = status
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *successResponder[T]) WithHeader(key, value string) *successResponder[T] {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *successResponder[T]) SetHeader(key, value string) *successResponder[T] {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *successResponder[T]) WithCookie(cookie *http.Cookie) *successResponder[T] {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
+
+// writeCBOR encodes the data as CBOR and writes it to the ResponseWriter with the
+// specified status code. If encoding fails, it responds with a 500 Internal Server Error.
+func writeCBOR(w http.ResponseWriter, v any, status int, logger httphandler.Logger) []byte {
+	return responder.Encode(w, status, ContentType, v, cbor.Marshal, logger)
+}
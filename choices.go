@@ -0,0 +1,130 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Ensure multipleChoicesResponder implements Responder.
+var _ Responder = (*multipleChoicesResponder)(nil)
+
+// Alternative is a representation of a resource offered by MultipleChoices.
+type Alternative struct {
+	// URL is the URL of the representation.
+	URL string `json:"href"`
+	// Type is the media type of the representation, if known.
+	Type string `json:"type,omitempty"`
+	// Title is a human-readable label of the representation.
+	Title string `json:"title,omitempty"`
+}
+
+// MultipleChoices creates a 300 Multiple Choices response that lists alternatives for the
+// client to choose from. Each alternative is sent in a Link header with rel="alternate", and
+// the list is rendered as a JSON body: {"alternatives":[{"href":...}]}.
+func MultipleChoices(alternatives ...Alternative) *multipleChoicesResponder {
+	return &multipleChoicesResponder{
+		alternatives: alternatives,
+	}
+}
+
+// multipleChoicesResponder handles 300 Multiple Choices responses.
+type multipleChoicesResponder struct {
+	logger       Logger
+	header       http.Header
+	override     http.Header
+	cookies      []*http.Cookie
+	alternatives []Alternative
+	preferred    string
+}
+
+// Respond sends the alternatives with custom headers and cookies.
+func (res *multipleChoicesResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = deferHeaders(w, res.header, res.override, res.cookies)
+
+	body := struct {
+		Alternatives []Alternative `json:"alternatives"`
+	}{Alternatives: res.alternatives}
+	if body.Alternatives == nil {
+		body.Alternatives = []Alternative{}
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		WriteInternalServerError(w, res.logger, err, "data", body)
+		return
+	}
+
+	for _, alt := range res.alternatives {
+		w.Header().Add("Link", alt.link())
+	}
+	if res.preferred != "" {
+		w.Header().Set("Location", res.preferred)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusMultipleChoices)
+	if _, err := w.Write(b); err != nil {
+		LogRequestError(res.logger, err, "status_code", http.StatusMultipleChoices)
+		return
+	}
+
+	LogResponse(res.logger, http.StatusMultipleChoices, "response_body", b)
+}
+
+// link returns the value of the Link header of the alternative.
+func (alt Alternative) link() string {
+	var b strings.Builder
+	b.WriteString("<" + alt.URL + `>; rel="alternate"`)
+	if alt.Type != "" {
+		b.WriteString("; type=" + quoteParam(alt.Type))
+	}
+	if alt.Title != "" {
+		b.WriteString("; title=" + quoteParam(alt.Title))
+	}
+	return b.String()
+}
+
+// quoteParam returns s as a quoted-string header parameter value.
+func quoteParam(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// WithPreferred sets the URL of the preferred alternative, sent as Location so that clients
+// may redirect to it automatically.
+func (res *multipleChoicesResponder) WithPreferred(url string) *multipleChoicesResponder {
+	res.preferred = url
+	return res
+}
+
+// WithLogger sets the logger for the responder.
+func (res *multipleChoicesResponder) WithLogger(logger Logger) *multipleChoicesResponder {
+	res.logger = logger
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *multipleChoicesResponder) WithHeader(key, value string) *multipleChoicesResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *multipleChoicesResponder) SetHeader(key, value string) *multipleChoicesResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *multipleChoicesResponder) WithCookie(cookie *http.Cookie) *multipleChoicesResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
@@ -0,0 +1,142 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestMultipleChoices(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantHeaders map[string][]string
+		wantBody    string
+	}{
+		{
+			desc: "alternatives",
+			given: httphandler.MultipleChoices(
+				httphandler.Alternative{URL: "/report.pdf", Type: "application/pdf", Title: `The "full" report`},
+				httphandler.Alternative{URL: "/report.csv"},
+			),
+			wantHeaders: map[string][]string{
+				"Link": {
+					`</report.pdf>; rel="alternate"; type="application/pdf"; title="The \"full\" report"`,
+					`</report.csv>; rel="alternate"`,
+				},
+				"Content-Type": {"application/json"},
+			},
+			wantBody: `{"alternatives":[{"href":"/report.pdf","type":"application/pdf","title":"The \"full\" report"},{"href":"/report.csv"}]}`,
+		},
+		{
+			desc:  "no alternatives | preferred",
+			given: httphandler.MultipleChoices().WithPreferred("/report.pdf").WithHeader("X-Test-1", "test value 1"),
+			wantHeaders: map[string][]string{
+				"Location": {"/report.pdf"},
+				"X-Test-1": {"test value 1"},
+			},
+			wantBody: `{"alternatives":[]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/report", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != http.StatusMultipleChoices {
+				t.Errorf("status code: want %d, got %d", http.StatusMultipleChoices, w.Code)
+			}
+			for key, want := range tc.wantHeaders {
+				if got := w.Header().Values(key); !slices.Equal(got, want) {
+					t.Errorf("header %s: want %q, got %q", key, want, got)
+				}
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want %s, got %s", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	t.Parallel()
+
+	modTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantHeaders map[string]string
+	}{
+		{
+			desc:  "etag quoted",
+			given: httphandler.NotModified().WithETag("v2").WithLastModified(modTime),
+			wantHeaders: map[string]string{
+				"ETag":          `"v2"`,
+				"Last-Modified": "Wed, 01 May 2024 10:00:00 GMT",
+			},
+		},
+		{
+			desc:        "weak etag kept",
+			given:       httphandler.NotModified().WithETag(`W/"v2"`),
+			wantHeaders: map[string]string{"ETag": `W/"v2"`},
+		},
+		{
+			desc: "body headers removed",
+			given: httphandler.NotModified().
+				WithHeader("Cache-Control", "max-age=60").
+				WithHeader("Content-Type", "application/json").
+				SetHeader("Content-Length", "42"),
+			wantHeaders: map[string]string{
+				"Cache-Control":  "max-age=60",
+				"Content-Type":   "",
+				"Content-Length": "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given: a header left by a previous writer
+			w := httptest.NewRecorder()
+			w.Header().Set("Content-Type", "text/plain")
+			r := httptest.NewRequest(http.MethodGet, "/report", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != http.StatusNotModified {
+				t.Errorf("status code: want %d, got %d", http.StatusNotModified, w.Code)
+			}
+			for key, want := range tc.wantHeaders {
+				if got := w.Header().Get(key); got != want {
+					t.Errorf("header %s: want %q, got %q", key, want, got)
+				}
+			}
+			if got := w.Header().Get("Content-Type"); got != "" {
+				t.Errorf("header Content-Type: want none, got %q", got)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != "" {
+				t.Errorf("body: want empty, got %q", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package httphandler
+
+import (
+	"crypto/rand"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock backed by time.Now.
+type systemClock struct{}
+
+// Now returns the current local time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+var (
+	clock      atomic.Pointer[Clock]
+	randReader atomic.Pointer[io.Reader]
+)
+
+func init() {
+	SetClock(nil)
+	SetRand(nil)
+}
+
+// SetClock replaces the Clock used by this module, e.g. to make timestamps and expiry checks
+// deterministic in tests. Passing nil restores the system clock.
+func SetClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+	clock.Store(&c)
+}
+
+// Now returns the current time from the Clock set with SetClock.
+// Features of this module that depend on time use it instead of time.Now.
+func Now() time.Time {
+	return (*clock.Load()).Now()
+}
+
+// SetRand replaces the source of randomness used by this module, e.g. with a seeded reader
+// in tests. Passing nil restores crypto/rand.Reader.
+func SetRand(r io.Reader) {
+	if r == nil {
+		r = rand.Reader
+	}
+	randReader.Store(&r)
+}
+
+// Rand returns the source of randomness set with SetRand.
+func Rand() io.Reader {
+	return *randReader.Load()
+}
@@ -0,0 +1,43 @@
+package httphandler_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// fixedClock is a Clock that always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// TestSetClock is not parallel because it changes the package-level clock.
+func TestSetClock(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	httphandler.SetClock(fixedClock(now))
+	defer httphandler.SetClock(nil)
+
+	if got := httphandler.Now(); !got.Equal(now) {
+		t.Errorf("now: want %v, got %v", now, got)
+	}
+}
+
+// TestSetRand is not parallel because it changes the package-level source of randomness.
+func TestSetRand(t *testing.T) {
+	httphandler.SetRand(bytes.NewReader([]byte{1, 2, 3, 4}))
+	defer httphandler.SetRand(nil)
+
+	b := make([]byte, 4)
+	if _, err := io.ReadFull(httphandler.Rand(), b); err != nil {
+		t.Fatalf("error: want nil, got %v", err)
+	}
+
+	if !bytes.Equal(b, []byte{1, 2, 3, 4}) {
+		t.Errorf("rand: want %v, got %v", []byte{1, 2, 3, 4}, b)
+	}
+}
@@ -0,0 +1,353 @@
+package httphandler
+
+import "net/http"
+
+// Tuple2 holds the values decoded by Combine2.
+type Tuple2[T1, T2 any] struct {
+	V1 T1
+	V2 T2
+}
+
+// Combine2 returns a RequestDecodeFunc that runs two decoders in order and combines their values.
+// Decoding stops at the first error, which is returned as a *StageError.
+func Combine2[T1, T2 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2]) RequestDecodeFunc[Tuple2[T1, T2]] {
+	return CombineWith2(d1, d2, func(v1 T1, v2 T2) Tuple2[T1, T2] {
+		return Tuple2[T1, T2]{V1: v1, V2: v2}
+	})
+}
+
+// CombineWith2 is like Combine2, but builds the input with the constructor function instead of a Tuple2.
+func CombineWith2[T1, T2, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], build func(T1, T2) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		v1, err := runStage(r, 1, d1)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v2, err := runStage(r, 2, d2)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2), nil
+	}
+}
+
+// Tuple3 holds the values decoded by Combine3.
+type Tuple3[T1, T2, T3 any] struct {
+	V1 T1
+	V2 T2
+	V3 T3
+}
+
+// Combine3 returns a RequestDecodeFunc that runs three decoders in order and combines their values.
+// Decoding stops at the first error, which is returned as a *StageError.
+func Combine3[T1, T2, T3 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3]) RequestDecodeFunc[Tuple3[T1, T2, T3]] {
+	return CombineWith3(d1, d2, d3, func(v1 T1, v2 T2, v3 T3) Tuple3[T1, T2, T3] {
+		return Tuple3[T1, T2, T3]{V1: v1, V2: v2, V3: v3}
+	})
+}
+
+// CombineWith3 is like Combine3, but builds the input with the constructor function instead of a Tuple3.
+func CombineWith3[T1, T2, T3, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], build func(T1, T2, T3) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		v1, err := runStage(r, 1, d1)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v2, err := runStage(r, 2, d2)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v3, err := runStage(r, 3, d3)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2, v3), nil
+	}
+}
+
+// Tuple4 holds the values decoded by Combine4.
+type Tuple4[T1, T2, T3, T4 any] struct {
+	V1 T1
+	V2 T2
+	V3 T3
+	V4 T4
+}
+
+// Combine4 returns a RequestDecodeFunc that runs four decoders in order and combines their values.
+// Decoding stops at the first error, which is returned as a *StageError.
+func Combine4[T1, T2, T3, T4 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4]) RequestDecodeFunc[Tuple4[T1, T2, T3, T4]] {
+	return CombineWith4(d1, d2, d3, d4, func(v1 T1, v2 T2, v3 T3, v4 T4) Tuple4[T1, T2, T3, T4] {
+		return Tuple4[T1, T2, T3, T4]{V1: v1, V2: v2, V3: v3, V4: v4}
+	})
+}
+
+// CombineWith4 is like Combine4, but builds the input with the constructor function instead of a Tuple4.
+func CombineWith4[T1, T2, T3, T4, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], build func(T1, T2, T3, T4) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		v1, err := runStage(r, 1, d1)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v2, err := runStage(r, 2, d2)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v3, err := runStage(r, 3, d3)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v4, err := runStage(r, 4, d4)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2, v3, v4), nil
+	}
+}
+
+// Tuple5 holds the values decoded by Combine5.
+type Tuple5[T1, T2, T3, T4, T5 any] struct {
+	V1 T1
+	V2 T2
+	V3 T3
+	V4 T4
+	V5 T5
+}
+
+// Combine5 returns a RequestDecodeFunc that runs five decoders in order and combines their values.
+// Decoding stops at the first error, which is returned as a *StageError.
+func Combine5[T1, T2, T3, T4, T5 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], d5 RequestDecodeFunc[T5]) RequestDecodeFunc[Tuple5[T1, T2, T3, T4, T5]] {
+	return CombineWith5(d1, d2, d3, d4, d5, func(v1 T1, v2 T2, v3 T3, v4 T4, v5 T5) Tuple5[T1, T2, T3, T4, T5] {
+		return Tuple5[T1, T2, T3, T4, T5]{V1: v1, V2: v2, V3: v3, V4: v4, V5: v5}
+	})
+}
+
+// CombineWith5 is like Combine5, but builds the input with the constructor function instead of a Tuple5.
+func CombineWith5[T1, T2, T3, T4, T5, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], d5 RequestDecodeFunc[T5], build func(T1, T2, T3, T4, T5) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		v1, err := runStage(r, 1, d1)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v2, err := runStage(r, 2, d2)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v3, err := runStage(r, 3, d3)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v4, err := runStage(r, 4, d4)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v5, err := runStage(r, 5, d5)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2, v3, v4, v5), nil
+	}
+}
+
+// Tuple6 holds the values decoded by Combine6.
+type Tuple6[T1, T2, T3, T4, T5, T6 any] struct {
+	V1 T1
+	V2 T2
+	V3 T3
+	V4 T4
+	V5 T5
+	V6 T6
+}
+
+// Combine6 returns a RequestDecodeFunc that runs six decoders in order and combines their values.
+// Decoding stops at the first error, which is returned as a *StageError.
+func Combine6[T1, T2, T3, T4, T5, T6 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], d5 RequestDecodeFunc[T5], d6 RequestDecodeFunc[T6]) RequestDecodeFunc[Tuple6[T1, T2, T3, T4, T5, T6]] {
+	return CombineWith6(d1, d2, d3, d4, d5, d6, func(v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6) Tuple6[T1, T2, T3, T4, T5, T6] {
+		return Tuple6[T1, T2, T3, T4, T5, T6]{V1: v1, V2: v2, V3: v3, V4: v4, V5: v5, V6: v6}
+	})
+}
+
+// CombineWith6 is like Combine6, but builds the input with the constructor function instead of a Tuple6.
+func CombineWith6[T1, T2, T3, T4, T5, T6, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], d5 RequestDecodeFunc[T5], d6 RequestDecodeFunc[T6], build func(T1, T2, T3, T4, T5, T6) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		v1, err := runStage(r, 1, d1)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v2, err := runStage(r, 2, d2)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v3, err := runStage(r, 3, d3)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v4, err := runStage(r, 4, d4)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v5, err := runStage(r, 5, d5)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v6, err := runStage(r, 6, d6)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2, v3, v4, v5, v6), nil
+	}
+}
+
+// Tuple7 holds the values decoded by Combine7.
+type Tuple7[T1, T2, T3, T4, T5, T6, T7 any] struct {
+	V1 T1
+	V2 T2
+	V3 T3
+	V4 T4
+	V5 T5
+	V6 T6
+	V7 T7
+}
+
+// Combine7 returns a RequestDecodeFunc that runs seven decoders in order and combines their values.
+// Decoding stops at the first error, which is returned as a *StageError.
+func Combine7[T1, T2, T3, T4, T5, T6, T7 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], d5 RequestDecodeFunc[T5], d6 RequestDecodeFunc[T6], d7 RequestDecodeFunc[T7]) RequestDecodeFunc[Tuple7[T1, T2, T3, T4, T5, T6, T7]] {
+	return CombineWith7(d1, d2, d3, d4, d5, d6, d7, func(v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7) Tuple7[T1, T2, T3, T4, T5, T6, T7] {
+		return Tuple7[T1, T2, T3, T4, T5, T6, T7]{V1: v1, V2: v2, V3: v3, V4: v4, V5: v5, V6: v6, V7: v7}
+	})
+}
+
+// CombineWith7 is like Combine7, but builds the input with the constructor function instead of a Tuple7.
+func CombineWith7[T1, T2, T3, T4, T5, T6, T7, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], d5 RequestDecodeFunc[T5], d6 RequestDecodeFunc[T6], d7 RequestDecodeFunc[T7], build func(T1, T2, T3, T4, T5, T6, T7) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		v1, err := runStage(r, 1, d1)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v2, err := runStage(r, 2, d2)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v3, err := runStage(r, 3, d3)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v4, err := runStage(r, 4, d4)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v5, err := runStage(r, 5, d5)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v6, err := runStage(r, 6, d6)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v7, err := runStage(r, 7, d7)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2, v3, v4, v5, v6, v7), nil
+	}
+}
+
+// Tuple8 holds the values decoded by Combine8.
+type Tuple8[T1, T2, T3, T4, T5, T6, T7, T8 any] struct {
+	V1 T1
+	V2 T2
+	V3 T3
+	V4 T4
+	V5 T5
+	V6 T6
+	V7 T7
+	V8 T8
+}
+
+// Combine8 returns a RequestDecodeFunc that runs eight decoders in order and combines their values.
+// Decoding stops at the first error, which is returned as a *StageError.
+func Combine8[T1, T2, T3, T4, T5, T6, T7, T8 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], d5 RequestDecodeFunc[T5], d6 RequestDecodeFunc[T6], d7 RequestDecodeFunc[T7], d8 RequestDecodeFunc[T8]) RequestDecodeFunc[Tuple8[T1, T2, T3, T4, T5, T6, T7, T8]] {
+	return CombineWith8(d1, d2, d3, d4, d5, d6, d7, d8, func(v1 T1, v2 T2, v3 T3, v4 T4, v5 T5, v6 T6, v7 T7, v8 T8) Tuple8[T1, T2, T3, T4, T5, T6, T7, T8] {
+		return Tuple8[T1, T2, T3, T4, T5, T6, T7, T8]{V1: v1, V2: v2, V3: v3, V4: v4, V5: v5, V6: v6, V7: v7, V8: v8}
+	})
+}
+
+// CombineWith8 is like Combine8, but builds the input with the constructor function instead of a Tuple8.
+func CombineWith8[T1, T2, T3, T4, T5, T6, T7, T8, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], d4 RequestDecodeFunc[T4], d5 RequestDecodeFunc[T5], d6 RequestDecodeFunc[T6], d7 RequestDecodeFunc[T7], d8 RequestDecodeFunc[T8], build func(T1, T2, T3, T4, T5, T6, T7, T8) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		v1, err := runStage(r, 1, d1)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v2, err := runStage(r, 2, d2)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v3, err := runStage(r, 3, d3)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v4, err := runStage(r, 4, d4)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v5, err := runStage(r, 5, d5)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v6, err := runStage(r, 6, d6)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v7, err := runStage(r, 7, d7)
+		if err != nil {
+			var v R
+			return v, err
+		}
+		v8, err := runStage(r, 8, d8)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2, v3, v4, v5, v6, v7, v8), nil
+	}
+}
@@ -0,0 +1,126 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func headerDecode(key string) httphandler.RequestDecodeFunc[string] {
+	return func(r *http.Request) (string, error) {
+		v := r.Header.Get(key)
+		if v == "" {
+			return "", errors.New("missing header " + key)
+		}
+		return v, nil
+	}
+}
+
+func queryDecode(key string) httphandler.RequestDecodeFunc[string] {
+	return func(r *http.Request) (string, error) {
+		return r.URL.Query().Get(key), nil
+	}
+}
+
+func TestCombine3(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		given   *http.Request
+		want    httphandler.Tuple3[string, string, string]
+		wantErr bool
+	}{
+		{
+			desc: "all decoded",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/?sort=name&page=2", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			}(),
+			want: httphandler.Tuple3[string, string, string]{V1: "acme", V2: "name", V3: "2"},
+		},
+		{
+			desc:    "first decoder fails",
+			given:   httptest.NewRequest(http.MethodGet, "/?sort=name", nil),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			decode := httphandler.Combine3(headerDecode("X-Tenant"), queryDecode("sort"), queryDecode("page"))
+
+			// When:
+			got, err := decode(tc.given)
+
+			// Then:
+			if (err != nil) != tc.wantErr {
+				t.Errorf("error: want %t, got %v", tc.wantErr, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("value: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCombineWith3(t *testing.T) {
+	t.Parallel()
+
+	type ListInput struct {
+		Tenant string
+		Sort   string
+		Page   string
+	}
+
+	// Given:
+	r := httptest.NewRequest(http.MethodGet, "/?sort=name&page=2", nil)
+	r.Header.Set("X-Tenant", "acme")
+
+	decode := httphandler.CombineWith3(headerDecode("X-Tenant"), queryDecode("sort"), queryDecode("page"),
+		func(tenant, sort, page string) ListInput {
+			return ListInput{Tenant: tenant, Sort: sort, Page: page}
+		})
+
+	// When:
+	got, err := decode(r)
+
+	// Then:
+	if err != nil {
+		t.Errorf("error: want nil, got %v", err)
+	}
+
+	want := ListInput{Tenant: "acme", Sort: "name", Page: "2"}
+	if got != want {
+		t.Errorf("value: want %+v, got %+v", want, got)
+	}
+}
+
+func TestCombine8(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	r := httptest.NewRequest(http.MethodGet, "/?a=1&b=2&c=3&d=4&e=5&f=6&g=7&h=8", nil)
+	decode := httphandler.Combine8(queryDecode("a"), queryDecode("b"), queryDecode("c"), queryDecode("d"),
+		queryDecode("e"), queryDecode("f"), queryDecode("g"), queryDecode("h"))
+
+	// When:
+	got, err := decode(r)
+
+	// Then:
+	if err != nil {
+		t.Errorf("error: want nil, got %v", err)
+	}
+
+	if got.V1 != "1" || got.V8 != "8" {
+		t.Errorf("value: want V1=1 and V8=8, got %+v", got)
+	}
+}
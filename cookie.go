@@ -0,0 +1,61 @@
+package httphandler
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// CookiePolicy defines attributes applied to every cookie set through a responder's WithCookie.
+// Secure and HttpOnly can only be turned on by the policy. SameSite and Path are defaults
+// that are used only when the cookie does not set them, so individual cookies can override them.
+type CookiePolicy struct {
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+	Path     string
+	// EnforcePrefixes applies the rules of the __Secure- and __Host- cookie name prefixes,
+	// which browsers otherwise enforce by silently rejecting the cookie.
+	EnforcePrefixes bool
+}
+
+// cookiePolicy is the package-level policy; nil means cookies are set unchanged.
+var cookiePolicy atomic.Pointer[CookiePolicy]
+
+// SetCookiePolicy sets the policy applied to cookies by all responders.
+// Passing nil removes the policy. It is safe for concurrent use, but is usually called once at startup.
+func SetCookiePolicy(policy *CookiePolicy) {
+	cookiePolicy.Store(policy)
+}
+
+// ApplyCookiePolicy returns a copy of cookie with the current CookiePolicy applied.
+// It returns cookie itself if no policy is set. Custom responders should use it before http.SetCookie.
+func ApplyCookiePolicy(cookie *http.Cookie) *http.Cookie {
+	policy := cookiePolicy.Load()
+	if policy == nil {
+		return cookie
+	}
+
+	c := *cookie
+	c.Secure = c.Secure || policy.Secure
+	c.HttpOnly = c.HttpOnly || policy.HttpOnly
+	if c.SameSite == 0 {
+		c.SameSite = policy.SameSite
+	}
+	if c.Path == "" {
+		c.Path = policy.Path
+	}
+
+	if policy.EnforcePrefixes {
+		switch {
+		case strings.HasPrefix(c.Name, "__Host-"):
+			c.Secure = true
+			c.Path = "/"
+			c.Domain = ""
+		case strings.HasPrefix(c.Name, "__Secure-"):
+			c.Secure = true
+		}
+	}
+
+	return &c
+}
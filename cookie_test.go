@@ -0,0 +1,83 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// TestApplyCookiePolicy is not parallel because it changes the package-level policy.
+func TestApplyCookiePolicy(t *testing.T) {
+	httphandler.SetCookiePolicy(&httphandler.CookiePolicy{
+		Secure:          true,
+		HttpOnly:        true,
+		SameSite:        http.SameSiteLaxMode,
+		Path:            "/app",
+		EnforcePrefixes: true,
+	})
+	defer httphandler.SetCookiePolicy(nil)
+
+	testCases := []struct {
+		desc  string
+		given *http.Cookie
+		want  http.Cookie
+	}{
+		{
+			desc:  "defaults applied",
+			given: &http.Cookie{Name: "session", Value: "1"},
+			want: http.Cookie{
+				Name: "session", Value: "1", Path: "/app",
+				Secure: true, HttpOnly: true, SameSite: http.SameSiteLaxMode,
+			},
+		},
+		{
+			desc:  "explicit attributes kept",
+			given: &http.Cookie{Name: "theme", Value: "dark", Path: "/", SameSite: http.SameSiteStrictMode},
+			want: http.Cookie{
+				Name: "theme", Value: "dark", Path: "/",
+				Secure: true, HttpOnly: true, SameSite: http.SameSiteStrictMode,
+			},
+		},
+		{
+			desc:  "host prefix enforced",
+			given: &http.Cookie{Name: "__Host-id", Value: "1", Path: "/app", Domain: "example.com"},
+			want: http.Cookie{
+				Name: "__Host-id", Value: "1", Path: "/",
+				Secure: true, HttpOnly: true, SameSite: http.SameSiteLaxMode,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			original := *tc.given
+
+			// When:
+			got := httphandler.ApplyCookiePolicy(tc.given)
+
+			// Then:
+			if got.String() != tc.want.String() {
+				t.Errorf("cookie: want %s, got %s", tc.want.String(), got.String())
+			}
+
+			if tc.given.String() != original.String() {
+				t.Errorf("original cookie: want unchanged %s, got %s", original.String(), tc.given.String())
+			}
+		})
+	}
+
+	// Responders apply the policy too.
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	httphandler.Redirect("/login", http.StatusFound).
+		WithCookie(&http.Cookie{Name: "session", Value: "1"}).
+		Respond(w, r)
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].Secure || !cookies[0].HttpOnly {
+		t.Errorf("responder cookie: want Secure and HttpOnly, got %v", cookies)
+	}
+}
@@ -0,0 +1,404 @@
+package httphandler_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// The files in testdata/corpus are raw HTTP/1.1 requests modelled on malformed inputs seen at
+// the internet edge. Each test case documents how the request parser of net/http and then the
+// decoders of this package handle one of them, so that a change of behavior is deliberate.
+
+// corpusInput is the input decoded from the JSON and form bodies of the corpus.
+type corpusInput struct {
+	Name string `json:"name" form:"name"`
+}
+
+// corpusHandlers returns a handler per decoder under test. Each responds with the decoded value.
+func corpusHandlers() map[string]http.HandlerFunc {
+	echo := func(r *http.Request, v any) httphandler.Responder {
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%+v", v)
+		})
+	}
+
+	return map[string]http.HandlerFunc{
+		"json": httphandler.HandleWithInput(func(r *http.Request, v corpusInput) httphandler.Responder {
+			return echo(r, v.Name)
+		}),
+		"strict": httphandler.HandleWithDecoder(httphandler.JSONBodyStrict[corpusInput](
+			httphandler.JSONDisallowUnknownFields(),
+			httphandler.JSONDisallowTrailingData(),
+			httphandler.JSONRequireContentType(),
+			httphandler.JSONMaxBytes(1024),
+		), func(r *http.Request, v corpusInput) httphandler.Responder {
+			return echo(r, v.Name)
+		}),
+		"form": httphandler.HandleWithDecoder(httphandler.FormBody[corpusInput](), func(r *http.Request, v corpusInput) httphandler.Responder {
+			return echo(r, v.Name)
+		}),
+		"pagination": httphandler.HandleWithDecoder(httphandler.PaginationDecode(httphandler.PaginationConfig{}), func(r *http.Request, v httphandler.Pagination) httphandler.Responder {
+			return echo(r, v)
+		}),
+		"time": httphandler.HandleWithDecoder(httphandler.TimeQueryParam("since", time.RFC3339), func(r *http.Request, v time.Time) httphandler.Responder {
+			return echo(r, v)
+		}),
+		"header": httphandler.HandleWithDecoder(headerDecode("X-Tenant"), func(r *http.Request, v string) httphandler.Responder {
+			return echo(r, fmt.Sprintf("%q", v))
+		}),
+	}
+}
+
+// readCorpus parses the request in testdata/corpus/name.http.
+func readCorpus(tb testing.TB, name string) (*http.Request, error) {
+	tb.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "corpus", name+".http"))
+	if err != nil {
+		tb.Fatalf("read corpus: %v", err)
+	}
+	return http.ReadRequest(bufio.NewReader(bytes.NewReader(data)))
+}
+
+func TestDecodeCorpus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc         string
+		givenFile    string
+		givenDecoder string
+		wantParseErr bool
+		wantCode     int
+		wantBody     string
+	}{
+		// JSON bodies.
+		{
+			desc:         "valid json | baseline",
+			givenFile:    "json-valid",
+			givenDecoder: "strict",
+			wantCode:     http.StatusOK,
+			wantBody:     "ada",
+		},
+		{
+			desc:         "invalid utf-8 in a string | replaced with U+FFFD, not rejected",
+			givenFile:    "json-broken-utf8",
+			givenDecoder: "strict",
+			wantCode:     http.StatusOK,
+			wantBody:     "��ada",
+		},
+		{
+			desc:         "byte order mark | rejected",
+			givenFile:    "json-bom",
+			givenDecoder: "json",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "duplicate keys | last one wins, even when strict",
+			givenFile:    "json-duplicate-keys",
+			givenDecoder: "strict",
+			wantCode:     http.StatusOK,
+			wantBody:     "eve",
+		},
+		{
+			desc:         "second json value | ignored by the default decoder",
+			givenFile:    "json-trailing-value",
+			givenDecoder: "json",
+			wantCode:     http.StatusOK,
+			wantBody:     "ada",
+		},
+		{
+			desc:         "second json value | rejected when strict",
+			givenFile:    "json-trailing-value",
+			givenDecoder: "strict",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "truncated body | rejected",
+			givenFile:    "json-truncated",
+			givenDecoder: "json",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "20000 nested arrays | rejected by the depth limit of encoding/json",
+			givenFile:    "json-deep-nesting",
+			givenDecoder: "json",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "body over the limit | 413",
+			givenFile:    "json-oversized",
+			givenDecoder: "strict",
+			wantCode:     http.StatusRequestEntityTooLarge,
+		},
+		// Content types.
+		{
+			desc:         "charset utf-16 | parameters are not checked",
+			givenFile:    "json-content-type-utf16",
+			givenDecoder: "strict",
+			wantCode:     http.StatusOK,
+			wantBody:     "ada",
+		},
+		{
+			desc:         "upper case media type | accepted",
+			givenFile:    "json-content-type-uppercase",
+			givenDecoder: "strict",
+			wantCode:     http.StatusOK,
+			wantBody:     "ada",
+		},
+		{
+			desc:         "look-alike media type | 415",
+			givenFile:    "json-content-type-suffix",
+			givenDecoder: "strict",
+			wantCode:     http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:         "malformed parameter | 415",
+			givenFile:    "json-content-type-malformed",
+			givenDecoder: "strict",
+			wantCode:     http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:         "missing content type | 415 when required",
+			givenFile:    "json-content-type-missing",
+			givenDecoder: "strict",
+			wantCode:     http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:         "text/plain | decoded by the default decoder, which does not check it",
+			givenFile:    "json-content-type-text",
+			givenDecoder: "json",
+			wantCode:     http.StatusOK,
+			wantBody:     "ada",
+		},
+		// Headers.
+		{
+			desc:         "invalid utf-8 in a header | passed through as-is",
+			givenFile:    "header-broken-utf8",
+			givenDecoder: "header",
+			wantCode:     http.StatusOK,
+			wantBody:     `"acme\xff\xfe"`,
+		},
+		{
+			desc:         "empty header | same as missing",
+			givenFile:    "header-empty",
+			givenDecoder: "header",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "obsolete line folding | joined with a space",
+			givenFile:    "header-obs-fold",
+			givenDecoder: "header",
+			wantCode:     http.StatusOK,
+			wantBody:     `"acme corp"`,
+		},
+		{
+			desc:         "space before the colon | not read as the header",
+			givenFile:    "header-space-before-colon",
+			givenDecoder: "header",
+			wantCode:     http.StatusBadRequest,
+		},
+		// Query strings.
+		{
+			desc:         "64 KiB limit value | rejected as out of range",
+			givenFile:    "query-overlong",
+			givenDecoder: "pagination",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "invalid escape | the pair is dropped, the others are kept",
+			givenFile:    "query-invalid-escape",
+			givenDecoder: "pagination",
+			wantCode:     http.StatusOK,
+			wantBody:     "{Limit:10 Offset:0 Cursor: Sort:[]}",
+		},
+		{
+			desc:         "semicolon separator | the pair is dropped",
+			givenFile:    "query-semicolon",
+			givenDecoder: "pagination",
+			wantCode:     http.StatusOK,
+			wantBody:     "{Limit:20 Offset:0 Cursor: Sort:[]}",
+		},
+		{
+			desc:         "semicolon separator | rejected by the form decoder",
+			givenFile:    "query-semicolon",
+			givenDecoder: "form",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "repeated parameter | the first one wins",
+			givenFile:    "query-repeated",
+			givenDecoder: "pagination",
+			wantCode:     http.StatusOK,
+			wantBody:     "{Limit:10 Offset:0 Cursor: Sort:[]}",
+		},
+		{
+			desc:         "negative offset | rejected",
+			givenFile:    "query-negative-offset",
+			givenDecoder: "pagination",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "integer overflow | rejected",
+			givenFile:    "query-integer-overflow",
+			givenDecoder: "pagination",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "empty sort field | rejected",
+			givenFile:    "query-empty-sort-field",
+			givenDecoder: "pagination",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "february 30 | rejected",
+			givenFile:    "query-time-invalid",
+			givenDecoder: "time",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "trailing NUL | rejected",
+			givenFile:    "query-time-nul",
+			givenDecoder: "time",
+			wantCode:     http.StatusBadRequest,
+		},
+		// Form bodies.
+		{
+			desc:         "valid form | baseline",
+			givenFile:    "form-valid",
+			givenDecoder: "form",
+			wantCode:     http.StatusOK,
+			wantBody:     "ada",
+		},
+		{
+			desc:         "invalid escape in the body | rejected",
+			givenFile:    "form-invalid-escape",
+			givenDecoder: "form",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "multipart without closing boundary | rejected",
+			givenFile:    "form-multipart-unterminated",
+			givenDecoder: "form",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "multipart without boundary parameter | rejected",
+			givenFile:    "form-multipart-no-boundary",
+			givenDecoder: "form",
+			wantCode:     http.StatusBadRequest,
+		},
+		// Request smuggling.
+		{
+			desc:         "content-length and chunked | chunked wins, the smuggled request is not read as body",
+			givenFile:    "smuggle-cl-te",
+			givenDecoder: "strict",
+			wantCode:     http.StatusOK,
+			wantBody:     "ada",
+		},
+		{
+			desc:         "chunks with bare LF | the body fails to read",
+			givenFile:    "smuggle-bare-lf-chunk",
+			givenDecoder: "json",
+			wantCode:     http.StatusBadRequest,
+		},
+		{
+			desc:         "conflicting content-lengths | refused by net/http",
+			givenFile:    "smuggle-duplicate-cl",
+			wantParseErr: true,
+		},
+		{
+			desc:         "list in content-length | refused by net/http",
+			givenFile:    "smuggle-cl-comma",
+			wantParseErr: true,
+		},
+		{
+			desc:         "signed content-length | refused by net/http",
+			givenFile:    "smuggle-cl-plus",
+			wantParseErr: true,
+		},
+		{
+			desc:         "obfuscated transfer-encoding | refused by net/http",
+			givenFile:    "smuggle-te-obfuscated",
+			wantParseErr: true,
+		},
+		{
+			desc:         "gzip before chunked | refused by net/http",
+			givenFile:    "smuggle-te-gzip-chunked",
+			wantParseErr: true,
+		},
+		{
+			desc:         "NUL in a header | refused by net/http",
+			givenFile:    "smuggle-header-nul",
+			wantParseErr: true,
+		},
+	}
+
+	handlers := corpusHandlers()
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r, err := readCorpus(t, tc.givenFile)
+			if tc.wantParseErr {
+				if err == nil {
+					t.Fatal("parse error: want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse error: want nil, got %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			// When:
+			handlers[tc.givenDecoder](w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if tc.wantCode == http.StatusOK && w.Body.String() != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func BenchmarkDecodeCorpus(b *testing.B) {
+	files, err := filepath.Glob(filepath.Join("testdata", "corpus", "*.http"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var data [][]byte
+	for _, f := range files {
+		d, err := os.ReadFile(f)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(d))); err == nil {
+			data = append(data, d)
+		}
+	}
+
+	handlers := corpusHandlers()
+	for _, name := range []string{"json", "strict", "form", "pagination"} {
+		h := handlers[name]
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				r, _ := http.ReadRequest(bufio.NewReader(bytes.NewReader(data[i%len(data)])))
+				h(httptest.NewRecorder(), r)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package csvresp
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// ContentType is the media type of the responses written by this package.
+const ContentType = "text/csv; charset=utf-8"
+
+// bom is the UTF-8 byte order mark that makes Excel detect the encoding.
+const bom = "\xef\xbb\xbf"
+
+// defaultFlushEvery is the number of rows after which Stream flushes the response by default.
+const defaultFlushEvery = 100
+
+// Ensure csvResponder implements Responder.
+var _ httphandler.Responder = (*csvResponder)(nil)
+
+// Stream creates a responder that writes the header row followed by the rows produced by iter
+// as CSV, without buffering them in memory. Fields are quoted as needed by encoding/csv, and the
+// response is flushed every 100 rows by default, see WithFlushEvery.
+// iter calls yield for every row and stops if yield returns an error.
+// If iter fails before the first row, a 500 Internal Server Error is sent; later failures
+// end the stream early since the status code has already been sent.
+func Stream(header []string, iter func(yield func(row []string) error) error) *csvResponder {
+	return &csvResponder{
+		statusCode: http.StatusOK,
+		columns:    header,
+		iter:       iter,
+		flushEvery: defaultFlushEvery,
+	}
+}
+
+// Records creates a responder that writes the header row followed by one row per item,
+// built with fields.
+func Records[T any](items []T, header []string, fields func(item T) []string) *csvResponder {
+	return Stream(header, func(yield func(row []string) error) error {
+		for _, item := range items {
+			if err := yield(fields(item)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// csvResponder handles streaming CSV HTTP responses.
+type csvResponder struct {
+	logger       httphandler.Logger
+	header       http.Header
+	override     http.Header
+	statusCode   int
+	cookies      []*http.Cookie
+	columns      []string
+	iter         func(yield func(row []string) error) error
+	filename     string
+	bom          bool
+	charsets     []responder.Charset
+	flushEvery   int
+	writeTimeout time.Duration
+	onAbort      func(err error)
+}
+
+// Respond streams the CSV response with custom headers, cookies and status code.
+func (res *csvResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// Fail the writes to a client that reads too slowly.
+	if res.writeTimeout > 0 {
+		var stop func()
+		w, stop = responder.WriteTimeout(w, r, res.writeTimeout, res.onAbort)
+		defer stop()
+	}
+
+	charset := responder.NegotiateCharset(r, res.charsets...)
+	if len(res.charsets) > 1 {
+		w.Header().Add("Vary", "Accept-Charset")
+	}
+	w.Header().Set("Content-Type", responder.ContentType(ContentType, charset))
+	if res.filename != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, res.filename))
+	}
+
+	// Write the status code with the first row, so that an early failure can still be reported.
+	cw := csv.NewWriter(responder.CharsetWriter(w, charset))
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		w.WriteHeader(res.statusCode)
+		if res.bom && charset == responder.UTF8 {
+			if _, err := w.Write([]byte(bom)); err != nil {
+				return err
+			}
+		}
+		if len(res.columns) > 0 {
+			return cw.Write(res.columns)
+		}
+		return nil
+	}
+
+	rc := http.NewResponseController(w)
+	rows := 0
+	err := res.iter(func(row []string) error {
+		if err := start(); err != nil {
+			return err
+		}
+		rows++
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+		if rows%res.flushEvery == 0 {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			_ = rc.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = start()
+	}
+	if err != nil && !started {
+		httphandler.WriteInternalServerError(w, res.logger, err)
+		return
+	}
+
+	cw.Flush()
+	if err == nil {
+		err = cw.Error()
+	}
+	if err != nil {
+		httphandler.LogRequestError(res.logger, err, "status_code", res.statusCode, "rows", rows)
+		return
+	}
+
+	httphandler.LogResponse(res.logger, res.statusCode, "rows", rows)
+}
+
+// WithFilename sends the CSV as an attachment with the given file name.
+func (res *csvResponder) WithFilename(filename string) *csvResponder {
+	res.filename = filename
+	return res
+}
+
+// WithBOM prefixes the body with a UTF-8 byte order mark so that Excel reads it as UTF-8.
+// A UTF-16LE body always starts with its byte order mark.
+func (res *csvResponder) WithBOM() *csvResponder {
+	res.bom = true
+	return res
+}
+
+// WithCharset sets the charset of the CSV, UTF-8 by default. Use responder.UTF16LE for files
+// that Excel opens with non-ASCII characters intact. If several charsets are given, the one
+// preferred by the Accept-Charset header of the request is used.
+func (res *csvResponder) WithCharset(charsets ...responder.Charset) *csvResponder {
+	res.charsets = charsets
+	return res
+}
+
+// WithFlushEvery flushes the response after every n rows instead of after every 100 rows.
+func (res *csvResponder) WithFlushEvery(n int) *csvResponder {
+	res.flushEvery = max(n, 1)
+	return res
+}
+
+// WithWriteTimeout fails the response when a write or a flush takes longer than d, so that a
+// client that reads too slowly does not hold the connection. See WithOnAbort.
+func (res *csvResponder) WithWriteTimeout(d time.Duration) *csvResponder {
+	res.writeTimeout = d
+	return res
+}
+
+// WithOnAbort sets a function called with the error when the stream is aborted because a write
+// failed with WithWriteTimeout, e.g. to release resources held for the stream.
+func (res *csvResponder) WithOnAbort(fn func(err error)) *csvResponder {
+	res.onAbort = fn
+	return res
+}
+
+// WithLogger sets the logger for the responder.
+func (res *csvResponder) WithLogger(logger httphandler.Logger) *csvResponder {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *csvResponder) WithStatus(status int) *csvResponder {
+	res.statusCode = status
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *csvResponder) WithHeader(key, value string) *csvResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *csvResponder) SetHeader(key, value string) *csvResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *csvResponder) WithCookie(cookie *http.Cookie) *csvResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
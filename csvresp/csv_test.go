@@ -0,0 +1,192 @@
+package csvresp_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/csvresp"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+func TestCSV_Respond(t *testing.T) {
+	t.Parallel()
+
+	type User struct {
+		Name string
+		Note string
+	}
+
+	users := []User{
+		{Name: "alice", Note: "likes, commas"},
+		{Name: "bob", Note: `says "hi"`},
+	}
+	fields := func(u User) []string {
+		return []string{u.Name, u.Note}
+	}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			desc:     "records",
+			given:    csvresp.Records(users, []string{"name", "note"}, fields),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": csvresp.ContentType,
+			},
+			wantBody: "name,note\nalice,\"likes, commas\"\nbob,\"says \"\"hi\"\"\"\n",
+		},
+		{
+			desc:     "no rows | with everything",
+			given:    csvresp.Records(nil, []string{"name", "note"}, fields).WithFilename("users.csv").WithBOM().WithHeader("X-Test-1", "test value 1"),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":        csvresp.ContentType,
+				"Content-Disposition": `attachment; filename="users.csv"`,
+				"X-Test-1":            "test value 1",
+			},
+			wantBody: "\xef\xbb\xbfname,note\n",
+		},
+		{
+			desc:     "utf-16le",
+			given:    csvresp.Records([]User{{Name: "zoë", Note: "€"}}, []string{"name", "note"}, fields).WithBOM().WithCharset(responder.UTF16LE),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "text/csv; charset=utf-16le",
+			},
+			wantBody: "\xff\xfen\x00a\x00m\x00e\x00,\x00n\x00o\x00t\x00e\x00\n\x00" +
+				"z\x00o\x00\xeb\x00,\x00\xac\x20\n\x00",
+		},
+		{
+			desc: "error before first row",
+			given: csvresp.Stream([]string{"name"}, func(yield func(row []string) error) error {
+				return errors.New("boom")
+			}),
+			wantCode: http.StatusInternalServerError,
+			wantBody: "Internal Server Error\n",
+		},
+		{
+			desc: "error after first row",
+			given: csvresp.Stream([]string{"name"}, func(yield func(row []string) error) error {
+				if err := yield([]string{"alice"}); err != nil {
+					return err
+				}
+				return errors.New("boom")
+			}),
+			wantCode: http.StatusOK,
+			wantBody: "name\nalice\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test-csv", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			for key, want := range tc.wantHeaders {
+				if got := w.Header().Get(key); got != want {
+					t.Errorf("header %s: want '%s', got '%s'", key, want, got)
+				}
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestStream_FlushEvery(t *testing.T) {
+	t.Parallel()
+
+	// Given: a stream that records what was sent after each row
+	w := httptest.NewRecorder()
+	var sent []string
+	res := csvresp.Stream([]string{"n"}, func(yield func(row []string) error) error {
+		for _, n := range []string{"1", "2", "3"} {
+			if err := yield([]string{n}); err != nil {
+				return err
+			}
+			sent = append(sent, w.Body.String())
+		}
+		return nil
+	}).WithFlushEvery(2)
+
+	// When:
+	res.Respond(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Then: the rows are sent and flushed every two rows
+	want := []string{"", "n\n1\n2\n", "n\n1\n2\n"}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent: want %q, got %q", want, sent)
+	}
+	if !w.Flushed {
+		t.Error("flushed: want true, got false")
+	}
+	if got := w.Body.String(); got != "n\n1\n2\n3\n" {
+		t.Errorf("body: want %q, got %q", "n\n1\n2\n3\n", got)
+	}
+}
+
+func TestStream_WriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Given: an endless stream to a client that never reads
+	aborted := make(chan error, 1)
+	srv := httptest.NewServer(httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return csvresp.Stream([]string{"n"}, func(yield func(row []string) error) error {
+			for {
+				if err := yield([]string{strings.Repeat("x", 1024)}); err != nil {
+					return err
+				}
+			}
+		}).
+			WithWriteTimeout(100 * time.Millisecond).
+			WithOnAbort(func(err error) { aborted <- err })
+	}))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	// When:
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")); err != nil {
+		t.Fatalf("write request: %v", err)
+	}
+
+	// Then: the stream is aborted instead of holding the connection
+	select {
+	case err := <-aborted:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("abort error: want %v, got %v", os.ErrDeadlineExceeded, err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("abort: want called, got not called within 10s")
+	}
+}
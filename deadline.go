@@ -0,0 +1,84 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// BudgetHeader is the header used to propagate the remaining request budget, in milliseconds.
+const BudgetHeader = "X-Request-Budget-Ms"
+
+var ErrInvalidBudget = errors.New("invalid request budget")
+
+// RemainingBudget returns the time left until the deadline of ctx.
+// It returns false if ctx has no deadline. The budget is never negative.
+// Context deadlines expire on the system clock, so it is used instead of the Clock set with
+// SetClock.
+func RemainingBudget(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+
+	return max(time.Until(deadline), 0), true
+}
+
+// OutgoingDeadlineHeader returns the value of BudgetHeader for a downstream call made with ctx.
+// It returns false if ctx has no deadline.
+func OutgoingDeadlineHeader(ctx context.Context) (string, bool) {
+	budget, ok := RemainingBudget(ctx)
+	if !ok {
+		return "", false
+	}
+
+	return strconv.FormatInt(budget.Milliseconds(), 10), true
+}
+
+// RequestDeadlineDecode is a RequestDecodeFunc that reads the deadline sent by an upstream
+// service in BudgetHeader. It returns the zero time if the header is absent, and
+// ErrInvalidBudget if it is not a number of milliseconds that fits in a time.Duration.
+// The deadline is computed from the system clock, like RemainingBudget, so that it can be
+// given to context.WithDeadline.
+func RequestDeadlineDecode(r *http.Request) (time.Time, error) {
+	value := r.Header.Get(BudgetHeader)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms < 0 || ms > math.MaxInt64/int64(time.Millisecond) {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidBudget, value)
+	}
+
+	return time.Now().Add(time.Duration(ms) * time.Millisecond), nil
+}
+
+// Ensure DeadlineTransport implements http.RoundTripper.
+var _ http.RoundTripper = (*DeadlineTransport)(nil)
+
+// DeadlineTransport is an http.RoundTripper that sets BudgetHeader on outgoing requests
+// from the deadline of their context, so downstream services can stop work nobody waits for.
+type DeadlineTransport struct {
+	// Base is the underlying RoundTripper. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip sets BudgetHeader unless it is already present and sends the request.
+func (t *DeadlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if value, ok := OutgoingDeadlineHeader(req.Context()); ok && req.Header.Get(BudgetHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(BudgetHeader, value)
+	}
+
+	return base.RoundTrip(req)
+}
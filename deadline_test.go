@@ -0,0 +1,173 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestOutgoingDeadlineHeader(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// When:
+	got, ok := httphandler.OutgoingDeadlineHeader(ctx)
+
+	// Then:
+	if !ok {
+		t.Fatalf("ok: want true, got false")
+	}
+
+	ms, err := strconv.Atoi(got)
+	if err != nil || ms <= 1000 || ms > 2000 {
+		t.Errorf("header: want (1000, 2000], got %q", got)
+	}
+
+	if _, ok := httphandler.OutgoingDeadlineHeader(context.Background()); ok {
+		t.Errorf("ok without deadline: want false, got true")
+	}
+}
+
+// TestRemainingBudget_SetClock is not parallel because it changes the package-level clock.
+func TestRemainingBudget_SetClock(t *testing.T) {
+	// Given: a clock far from the system clock, and a deadline in 2s on the system clock
+	httphandler.SetClock(fixedClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	defer httphandler.SetClock(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// When:
+	got, ok := httphandler.RemainingBudget(ctx)
+
+	// Then: the budget is measured on the clock the deadline expires on
+	if !ok {
+		t.Fatalf("ok: want true, got false")
+	}
+	if got <= time.Second || got > 2*time.Second {
+		t.Errorf("budget: want (1s, 2s], got %v", got)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(httphandler.BudgetHeader, "2000")
+	deadline, err := httphandler.RequestDeadlineDecode(r)
+	if err != nil {
+		t.Fatalf("error: want nil, got %v", err)
+	}
+	if until := time.Until(deadline); until <= time.Second || until > 2*time.Second {
+		t.Errorf("decoded deadline: want in (1s, 2s], got in %v", until)
+	}
+}
+
+func TestRequestDeadlineDecode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		given    string
+		wantZero bool
+		wantErr  error
+	}{
+		{
+			desc:     "absent",
+			given:    "",
+			wantZero: true,
+		},
+		{
+			desc:  "valid",
+			given: "1500",
+		},
+		{
+			desc:     "invalid",
+			given:    "soon",
+			wantZero: true,
+			wantErr:  httphandler.ErrInvalidBudget,
+		},
+		{
+			desc:     "negative",
+			given:    "-1",
+			wantZero: true,
+			wantErr:  httphandler.ErrInvalidBudget,
+		},
+		{
+			desc:     "overflows duration",
+			given:    "9223372036855",
+			wantZero: true,
+			wantErr:  httphandler.ErrInvalidBudget,
+		},
+		{
+			desc:  "largest duration",
+			given: "9223372036854",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.given != "" {
+				r.Header.Set(httphandler.BudgetHeader, tc.given)
+			}
+
+			// When:
+			got, err := httphandler.RequestDeadlineDecode(r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if got.IsZero() != tc.wantZero {
+				t.Errorf("deadline zero: want %t, got %t", tc.wantZero, got.IsZero())
+			}
+		})
+	}
+}
+
+func TestDeadlineTransport(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(httphandler.BudgetHeader)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &httphandler.DeadlineTransport{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// When:
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	// Then:
+	if got == "" {
+		t.Errorf("header %s: want set, got empty", httphandler.BudgetHeader)
+	}
+
+	if req.Header.Get(httphandler.BudgetHeader) != "" {
+		t.Errorf("original request: want unmodified")
+	}
+}
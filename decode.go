@@ -0,0 +1,141 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrContextValue = errors.New("context value not found")
+	ErrMissingParam = errors.New("missing parameter")
+	ErrInvalidParam = errors.New("invalid parameter")
+)
+
+// RequestDecodeFuncCtx decodes an HTTP request with its context, e.g. for decoders backed by a
+// database that should stop when the request is cancelled or its deadline passes.
+type RequestDecodeFuncCtx[T any] func(ctx context.Context, r *http.Request) (T, error)
+
+// DecodeCtx converts a RequestDecodeFuncCtx to a RequestDecodeFunc, so that it can be combined
+// with other decoders and passed to HandleWithDecoder. It receives the request context, and is
+// not called if the context is already done, in which case its error is returned.
+func DecodeCtx[T any](decode RequestDecodeFuncCtx[T]) RequestDecodeFunc[T] {
+	return func(r *http.Request) (T, error) {
+		ctx := r.Context()
+		if err := ctx.Err(); err != nil {
+			var v T
+			return v, err
+		}
+		return decode(ctx, r)
+	}
+}
+
+// LiftDecode converts a RequestDecodeFunc to a RequestDecodeFuncCtx. The decoder receives the
+// request with ctx as its context.
+func LiftDecode[T any](decode RequestDecodeFunc[T]) RequestDecodeFuncCtx[T] {
+	return func(ctx context.Context, r *http.Request) (T, error) {
+		if ctx != r.Context() {
+			r = r.WithContext(ctx)
+		}
+		return decode(r)
+	}
+}
+
+// WithDecodeFuncCtx is like WithDecodeFunc for a RequestDecodeFuncCtx, see DecodeCtx.
+//
+// Deprecated: Use HandleWithDecoder with DecodeCtx.
+func WithDecodeFuncCtx[T any](decode RequestDecodeFuncCtx[T]) HandlerOption {
+	return WithDecodeFunc(DecodeCtx(decode))
+}
+
+// FromContext returns a RequestDecodeFunc that reads the value stored under key in the request context.
+// This allows values placed by upstream middleware (e.g. an authenticated user) to be passed as typed input.
+func FromContext[T any](key any) RequestDecodeFunc[T] {
+	return func(r *http.Request) (T, error) {
+		v, ok := r.Context().Value(key).(T)
+		if !ok {
+			return v, fmt.Errorf("%w: %v", ErrContextValue, key)
+		}
+
+		return v, nil
+	}
+}
+
+// TimeQueryParam returns a RequestDecodeFunc that parses the named query parameter as a time.
+// If layout is empty, time.RFC3339 is used.
+func TimeQueryParam(name, layout string) RequestDecodeFunc[time.Time] {
+	return func(r *http.Request) (time.Time, error) {
+		return parseTimeParam(name, layout, r.URL.Query().Get(name))
+	}
+}
+
+// TimePathParam returns a RequestDecodeFunc that parses the named path wildcard as a time.
+// If layout is empty, time.RFC3339 is used.
+func TimePathParam(name, layout string) RequestDecodeFunc[time.Time] {
+	return func(r *http.Request) (time.Time, error) {
+		return parseTimeParam(name, layout, r.PathValue(name))
+	}
+}
+
+// DurationQueryParam returns a RequestDecodeFunc that parses the named query parameter
+// as a duration, e.g. "1h30m", using time.ParseDuration.
+func DurationQueryParam(name string) RequestDecodeFunc[time.Duration] {
+	return func(r *http.Request) (time.Duration, error) {
+		value := r.URL.Query().Get(name)
+		if value == "" {
+			return 0, fmt.Errorf("%w: %s", ErrMissingParam, name)
+		}
+
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: %w", ErrInvalidParam, name, err)
+		}
+
+		return d, nil
+	}
+}
+
+// Optional returns a RequestDecodeFunc that returns fallback instead of the error of decode,
+// e.g. for a stage that decodes the user if logged in, and an anonymous user otherwise.
+// Errors returned by Halt are still returned.
+func Optional[T any](decode RequestDecodeFunc[T], fallback T) RequestDecodeFunc[T] {
+	return OptionalWithLogger(decode, fallback, nil)
+}
+
+// OptionalWithLogger is like Optional, but logs the error of decode as a warning if logger is
+// not nil.
+func OptionalWithLogger[T any](decode RequestDecodeFunc[T], fallback T, logger Logger) RequestDecodeFunc[T] {
+	return func(r *http.Request) (T, error) {
+		v, err := decode(r)
+		if err == nil {
+			return v, nil
+		}
+		if _, ok := halted(err); ok {
+			return v, err
+		}
+
+		if logger != nil {
+			logger.Warn("Optional decoder failed, using fallback", "error", err)
+		}
+		return fallback, nil
+	}
+}
+
+// parseTimeParam parses the value of the named parameter with layout.
+func parseTimeParam(name, layout, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrMissingParam, name)
+	}
+	if layout == "" {
+		layout = time.RFC3339
+	}
+
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s: %w", ErrInvalidParam, name, err)
+	}
+
+	return t, nil
+}
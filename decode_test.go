@@ -0,0 +1,360 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+type ctxKey string
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	type User struct {
+		ID string
+	}
+
+	testCases := []struct {
+		desc    string
+		given   context.Context
+		want    User
+		wantErr error
+	}{
+		{
+			desc:  "value present",
+			given: context.WithValue(context.Background(), ctxKey("user"), User{ID: "u1"}),
+			want:  User{ID: "u1"},
+		},
+		{
+			desc:    "value missing",
+			given:   context.Background(),
+			wantErr: httphandler.ErrContextValue,
+		},
+		{
+			desc:    "value of wrong type",
+			given:   context.WithValue(context.Background(), ctxKey("user"), "u1"),
+			wantErr: httphandler.ErrContextValue,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tc.given)
+
+			// When:
+			got, err := httphandler.FromContext[User](ctxKey("user"))(r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("value: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTimeQueryParam(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc        string
+		givenURL    string
+		givenLayout string
+		want        time.Time
+		wantErr     error
+	}{
+		{
+			desc:     "rfc3339 default",
+			givenURL: "/?from=2024-01-02T03:04:05Z",
+			want:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			desc:        "custom layout",
+			givenURL:    "/?from=2024-01-02",
+			givenLayout: time.DateOnly,
+			want:        time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc:     "missing",
+			givenURL: "/",
+			wantErr:  httphandler.ErrMissingParam,
+		},
+		{
+			desc:     "invalid",
+			givenURL: "/?from=yesterday",
+			wantErr:  httphandler.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, tc.givenURL, nil)
+
+			// When:
+			got, err := httphandler.TimeQueryParam("from", tc.givenLayout)(r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if err != nil && !strings.Contains(err.Error(), "from") {
+				t.Errorf("error: want parameter name in %q", err)
+			}
+
+			if !got.Equal(tc.want) {
+				t.Errorf("value: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTimePathParam(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	r := httptest.NewRequest(http.MethodGet, "/reports/2024-01-02", nil)
+	r.SetPathValue("date", "2024-01-02")
+
+	// When:
+	got, err := httphandler.TimePathParam("date", time.DateOnly)(r)
+
+	// Then:
+	if err != nil {
+		t.Errorf("error: want nil, got %v", err)
+	}
+
+	want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("value: want %v, got %v", want, got)
+	}
+}
+
+func TestDurationQueryParam(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		givenURL string
+		want     time.Duration
+		wantErr  error
+	}{
+		{
+			desc:     "valid",
+			givenURL: "/?window=1h30m",
+			want:     90 * time.Minute,
+		},
+		{
+			desc:     "missing",
+			givenURL: "/",
+			wantErr:  httphandler.ErrMissingParam,
+		},
+		{
+			desc:     "invalid",
+			givenURL: "/?window=90",
+			wantErr:  httphandler.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, tc.givenURL, nil)
+
+			// When:
+			got, err := httphandler.DurationQueryParam("window")(r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("value: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOptional(t *testing.T) {
+	t.Parallel()
+
+	anonymous := "anonymous"
+	halt := func(r *http.Request) (string, error) {
+		return "", httphandler.Halt(httphandler.Redirect("/login", http.StatusFound))
+	}
+
+	testCases := []struct {
+		desc        string
+		givenDecode httphandler.RequestDecodeFunc[string]
+		givenHeader string
+		want        string
+		wantErr     bool
+		wantLog     bool
+	}{
+		{
+			desc:        "decoded",
+			givenDecode: func(r *http.Request) (string, error) { return r.Header.Get("X-User"), nil },
+			givenHeader: "alice",
+			want:        "alice",
+		},
+		{
+			desc: "failed | fallback",
+			givenDecode: func(r *http.Request) (string, error) {
+				return "", errors.New("no session")
+			},
+			want:    anonymous,
+			wantLog: true,
+		},
+		{
+			desc:        "halted | not replaced",
+			givenDecode: halt,
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var logs strings.Builder
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("X-User", tc.givenHeader)
+
+			// When:
+			got, err := httphandler.OptionalWithLogger(tc.givenDecode, anonymous, logger)(r)
+
+			// Then:
+			if (err != nil) != tc.wantErr {
+				t.Errorf("error: want %t, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("value: want %q, got %q", tc.want, got)
+			}
+			if gotLog := strings.Contains(logs.String(), "no session"); gotLog != tc.wantLog {
+				t.Errorf("logged: want %t, got %t: %s", tc.wantLog, gotLog, logs.String())
+			}
+		})
+	}
+}
+
+func TestDecodeCtx(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	// tenant reads the tenant from the context, as a database-backed decoder would use it.
+	tenant := func(ctx context.Context, r *http.Request) (string, error) {
+		v, _ := ctx.Value(ctxKey{}).(string)
+		return v + ":" + r.URL.Query().Get("id"), nil
+	}
+
+	testCases := []struct {
+		desc        string
+		givenCancel bool
+		want        string
+		wantErr     error
+	}{
+		{
+			desc: "request context",
+			want: "acme:1",
+		},
+		{
+			desc:        "cancelled | not called",
+			givenCancel: true,
+			wantErr:     context.Canceled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "acme"))
+			defer cancel()
+			if tc.givenCancel {
+				cancel()
+			}
+			r := httptest.NewRequest(http.MethodGet, "/?id=1", nil).WithContext(ctx)
+
+			// When:
+			got, err := httphandler.DecodeCtx(tenant)(r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("value: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLiftDecode(t *testing.T) {
+	t.Parallel()
+
+	// Given: a request-only decoder lifted to take a context
+	decode := httphandler.LiftDecode(httphandler.FromContext[string](ctxKey("user")))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := context.WithValue(context.Background(), ctxKey("user"), "alice")
+
+	// When:
+	got, err := decode(ctx, r)
+
+	// Then: the decoder sees ctx
+	if err != nil {
+		t.Errorf("error: want nil, got %v", err)
+	}
+	if got != "alice" {
+		t.Errorf("value: want %q, got %q", "alice", got)
+	}
+}
+
+func TestDecodeCtx_HandleWithDecoder(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	h := httphandler.HandleWithDecoder(httphandler.DecodeCtx(func(ctx context.Context, r *http.Request) (string, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return "", errors.New("no deadline")
+		}
+		return "deadline", nil
+	}), func(r *http.Request, input string) httphandler.Responder {
+		return &mockResponder{StatusCode: http.StatusOK, Body: input}
+	}, httphandler.WithTimeout(time.Second))
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Then: the decoder receives the request context
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Body.String(); got != "deadline" {
+		t.Errorf("body: want %q, got %q", "deadline", got)
+	}
+}
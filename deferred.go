@@ -0,0 +1,16 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler/internal/deferred"
+)
+
+// deferHeaders is the root package counterpart of responder.Defer, which it cannot import.
+func deferHeaders(w http.ResponseWriter, header, override http.Header, cookies []*http.Cookie) http.ResponseWriter {
+	policied := make([]*http.Cookie, len(cookies))
+	for i, cookie := range cookies {
+		policied[i] = ApplyCookiePolicy(cookie)
+	}
+	return deferred.Writer(w, header, override, policied)
+}
@@ -0,0 +1,49 @@
+package httphandler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/alvinchoong/go-httphandler/internal/buffered"
+)
+
+// DispatchInternal synthesizes a request, routes it through handler (usually an *http.ServeMux)
+// in-process and returns the captured response, without any network round trip.
+// This lets aggregation endpoints reuse existing handlers. The request inherits ctx, so its
+// deadline and values apply to the dispatched handler.
+func DispatchInternal(ctx context.Context, handler http.Handler, method, path string, body io.Reader) (*http.Response, error) {
+	r, err := http.NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch internal request: %w", err)
+	}
+	r.RequestURI = r.URL.RequestURI()
+
+	w := buffered.NewWriter()
+	handler.ServeHTTP(w, r)
+
+	return bufferedResponse(w, r), nil
+}
+
+// bufferedResponse builds the http.Response of the request from the response buffered in w.
+func bufferedResponse(w *buffered.Writer, r *http.Request) *http.Response {
+	status, header, body := w.Status(), w.SentHeader(), w.Body()
+	if header.Get("Content-Type") == "" && len(body) > 0 {
+		header.Set("Content-Type", http.DetectContentType(body))
+	}
+
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
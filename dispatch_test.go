@@ -0,0 +1,90 @@
+package httphandler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func TestDispatchInternal(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return jsonresp.Success(&map[string]string{"id": r.PathValue("id")}).
+			WithHeader("X-Test-1", "test value 1")
+	}))
+	mux.HandleFunc("POST /echo", httphandler.HandleWithInput(func(r *http.Request, input map[string]string) httphandler.Responder {
+		return jsonresp.Success(&input).WithStatus(http.StatusCreated)
+	}))
+
+	testCases := []struct {
+		desc        string
+		givenMethod string
+		givenPath   string
+		givenBody   io.Reader
+		wantCode    int
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			desc:        "get",
+			givenMethod: http.MethodGet,
+			givenPath:   "/users/42",
+			wantCode:    http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/json",
+				"X-Test-1":     "test value 1",
+			},
+			wantBody: `{"id":"42"}`,
+		},
+		{
+			desc:        "post with body",
+			givenMethod: http.MethodPost,
+			givenPath:   "/echo",
+			givenBody:   strings.NewReader(`{"name":"alice"}`),
+			wantCode:    http.StatusCreated,
+			wantBody:    `{"name":"alice"}`,
+		},
+		{
+			desc:        "not found",
+			givenMethod: http.MethodGet,
+			givenPath:   "/missing",
+			wantCode:    http.StatusNotFound,
+			wantBody:    "404 page not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			res, err := httphandler.DispatchInternal(context.Background(), mux, tc.givenMethod, tc.givenPath, tc.givenBody)
+			if err != nil {
+				t.Fatalf("error: want nil, got %v", err)
+			}
+
+			// Then:
+			if res.StatusCode != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, res.StatusCode)
+			}
+
+			for key, wantValue := range tc.wantHeaders {
+				if gotValue := res.Header.Get(key); gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			b, _ := io.ReadAll(res.Body)
+			if gotBody := strings.TrimSpace(string(b)); gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
@@ -1,13 +1,21 @@
 package downloadresp
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"mime"
 	"net/http"
+	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
 )
 
 // Ensure responder implements Responder.
@@ -15,12 +23,19 @@ var _ httphandler.Responder = (*fileResponder)(nil)
 
 // fileResponder handles file response that can be returned from an HTTP handler.
 type fileResponder struct {
-	logger      httphandler.Logger
-	header      http.Header
-	cookies     []*http.Cookie
-	reader      io.Reader
-	filename    string
-	disposition string
+	logger       httphandler.Logger
+	header       http.Header
+	override     http.Header
+	cookies      []*http.Cookie
+	reader       io.Reader
+	open         func() (fs.File, error)
+	filename     string
+	disposition  string
+	size         int64
+	modTime      time.Time
+	etag         string
+	writeTimeout time.Duration
+	onAbort      func(err error)
 }
 
 // Attachment returns a responder that can be used to send a file as an attachment.
@@ -29,6 +44,7 @@ func Attachment(reader io.Reader, filename string) *fileResponder {
 		reader:      reader,
 		filename:    filename,
 		disposition: "attachment",
+		size:        -1,
 	}
 }
 
@@ -38,37 +54,99 @@ func Inline(reader io.Reader, filename string) *fileResponder {
 		reader:      reader,
 		filename:    filename,
 		disposition: "inline",
+		size:        -1,
+	}
+}
+
+// FromFile returns a responder that sends the file at path inline. The file is opened when
+// responding and closed afterwards; its size and modification time are used as with WithSize
+// and WithModTime, so Range and conditional requests are supported.
+// A missing file results in 404 Not Found.
+func FromFile(name string) *fileResponder {
+	return &fileResponder{
+		open:        func() (fs.File, error) { return os.Open(name) },
+		filename:    filepath.Base(name),
+		disposition: "inline",
+		size:        -1,
+	}
+}
+
+// FromFS is like FromFile for the file at name in fsys, e.g. an embed.FS.
+func FromFS(fsys fs.FS, name string) *fileResponder {
+	return &fileResponder{
+		open:        func() (fs.File, error) { return fsys.Open(name) },
+		filename:    path.Base(name),
+		disposition: "inline",
+		size:        -1,
 	}
 }
 
 // Respond sends the response with custom headers.
-func (res *fileResponder) Respond(w http.ResponseWriter, _ *http.Request) {
-	// Set cookies.
-	for _, cookie := range res.cookies {
-		http.SetCookie(w, cookie)
+// If a size, modification time or ETag is set, conditional requests are answered with
+// 304 Not Modified, and if the reader is an io.ReadSeeker, Range requests with 206 Partial Content.
+func (res *fileResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// Fail the writes to a client that reads too slowly.
+	if res.writeTimeout > 0 {
+		var stop func()
+		w, stop = responder.WriteTimeout(w, r, res.writeTimeout, res.onAbort)
+		defer stop()
 	}
 
-	// Add custom headers.
-	for key, values := range res.header {
-		for _, value := range values {
-			w.Header().Add(key, value)
-		}
+	if res.open != nil {
+		res.respondFile(w, r)
+		return
 	}
+	res.respond(w, r)
+}
 
-	// If the Content-Type header is not set, set it to the appropriate MIME type based on the file extension.
-	if res.header.Get("Content-Type") == "" {
-		contentType := "application/octet-stream"
+// respond sends the content of the reader.
+func (res *fileResponder) respond(w http.ResponseWriter, r *http.Request) {
+	// Set the Content-Type header, or else the MIME type based on the file extension.
+	contentType := res.override.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
 		if s := mime.TypeByExtension(filepath.Ext(res.filename)); s != "" {
 			contentType = s
 		}
-		w.Header().Set("Content-Type", contentType)
 	}
+	w.Header().Set("Content-Type", contentType)
 
 	// Set the Content-Disposition header
 	w.Header().Set(
 		"Content-Disposition",
 		fmt.Sprintf(`%s; filename="%s"`, res.disposition, res.filename))
 
+	if res.etag != "" {
+		w.Header().Set("ETag", res.etag)
+	}
+
+	// Let net/http handle conditional and Range requests when the content can be seeked.
+	hasMetadata := res.size >= 0 || !res.modTime.IsZero() || res.etag != ""
+	if rs, ok := res.reader.(io.ReadSeeker); ok && hasMetadata {
+		// Log the status code that net/http chose, e.g. 206 Partial Content or 304 Not Modified.
+		rec := httphandler.NewResponseRecorder(w)
+		http.ServeContent(rec, r, res.filename, res.modTime, rs)
+		httphandler.LogResponse(res.logger, rec.Status(), "filename", res.filename)
+		return
+	}
+
+	if !res.modTime.IsZero() {
+		w.Header().Set("Last-Modified", res.modTime.UTC().Format(http.TimeFormat))
+	}
+	if res.notModified(r) {
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Disposition")
+		w.WriteHeader(http.StatusNotModified)
+		httphandler.LogResponse(res.logger, http.StatusNotModified, "filename", res.filename)
+		return
+	}
+	if res.size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(res.size, 10))
+	}
+
 	if _, err := io.Copy(w, res.reader); err != nil {
 		httphandler.WriteInternalServerError(w, res.logger, err)
 		return
@@ -77,7 +155,128 @@ func (res *fileResponder) Respond(w http.ResponseWriter, _ *http.Request) {
 	httphandler.LogResponse(res.logger, http.StatusOK, "filename", res.filename)
 }
 
-// WithHeader adds a custom header to the response.
+// respondFile opens the file and responds with its content and metadata.
+func (res *fileResponder) respondFile(w http.ResponseWriter, r *http.Request) {
+	f, err := res.open()
+	if err != nil {
+		res.writeOpenError(w, err)
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		res.writeOpenError(w, err)
+		return
+	}
+	if info.IsDir() {
+		res.writeOpenError(w, fs.ErrNotExist)
+		return
+	}
+
+	file := *res
+	file.open = nil
+	file.reader = f
+	if file.size < 0 {
+		file.size = info.Size()
+	}
+	if file.modTime.IsZero() {
+		file.modTime = info.ModTime()
+	}
+	file.respond(w, r)
+}
+
+// writeOpenError responds with 404 Not Found or 403 Forbidden if the file cannot be opened,
+// and with 500 Internal Server Error for any other error.
+func (res *fileResponder) writeOpenError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		httphandler.LogRequestError(res.logger, err, "status_code", http.StatusNotFound, "filename", res.filename)
+	case errors.Is(err, fs.ErrPermission):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		httphandler.LogRequestError(res.logger, err, "status_code", http.StatusForbidden, "filename", res.filename)
+	default:
+		httphandler.WriteInternalServerError(w, res.logger, err, "filename", res.filename)
+	}
+}
+
+// WithAttachment sends the file as an attachment instead of inline.
+func (res *fileResponder) WithAttachment() *fileResponder {
+	res.disposition = "attachment"
+	return res
+}
+
+// notModified reports whether the client's cached copy is still current, based on
+// If-None-Match and, in its absence, If-Modified-Since.
+func (res *fileResponder) notModified(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if res.etag == "" {
+			return false
+		}
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(res.etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if res.modTime.IsZero() {
+		return false
+	}
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !res.modTime.Truncate(time.Second).After(ims)
+}
+
+// WithSize sets the size of the content in bytes, sent as Content-Length.
+func (res *fileResponder) WithSize(size int64) *fileResponder {
+	res.size = size
+	return res
+}
+
+// WithModTime sets the modification time of the content, sent as Last-Modified and compared
+// with If-Modified-Since.
+func (res *fileResponder) WithModTime(modTime time.Time) *fileResponder {
+	res.modTime = modTime
+	return res
+}
+
+// WithETag sets the entity tag of the content, sent as ETag and compared with If-None-Match.
+// It is quoted if it is not already, e.g. "v1" or W/"v1".
+func (res *fileResponder) WithETag(etag string) *fileResponder {
+	if !strings.HasPrefix(etag, `"`) && !strings.HasPrefix(etag, `W/"`) {
+		etag = strconv.Quote(etag)
+	}
+	res.etag = etag
+	return res
+}
+
+// WithWriteTimeout fails the response when a chunk of the content takes longer than d to
+// write, so that a client that reads too slowly does not hold the connection, e.g. on
+// download endpoints. See WithOnAbort.
+func (res *fileResponder) WithWriteTimeout(d time.Duration) *fileResponder {
+	res.writeTimeout = d
+	return res
+}
+
+// WithOnAbort sets a function called with the error when the download is aborted because a
+// write failed with WithWriteTimeout, e.g. to release resources held for the download.
+func (res *fileResponder) WithOnAbort(fn func(err error)) *fileResponder {
+	res.onAbort = fn
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
 func (res *fileResponder) WithHeader(key, value string) *fileResponder {
 	if res.header == nil {
 		res.header = http.Header{}
@@ -86,9 +285,20 @@ func (res *fileResponder) WithHeader(key, value string) *fileResponder {
 	return res
 }
 
-// WithContentType sets the Content-Type header.
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *fileResponder) SetHeader(key, value string) *fileResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithContentType sets the Content-Type header instead of the MIME type based on the file extension.
 func (res *fileResponder) WithContentType(contentType string) *fileResponder {
-	return res.WithHeader("Content-Type", contentType)
+	return res.SetHeader("Content-Type", contentType)
 }
 
 // WithLogger sets the logger for the responder.
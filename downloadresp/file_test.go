@@ -1,11 +1,18 @@
 package downloadresp_test
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/alvinchoong/go-httphandler"
 	"github.com/alvinchoong/go-httphandler/downloadresp"
@@ -180,3 +187,410 @@ func TestAttachment_Respond(t *testing.T) {
 		})
 	}
 }
+
+func TestAttachment_Conditional(t *testing.T) {
+	t.Parallel()
+
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// nonSeeker hides the io.Seeker implementation of the underlying reader.
+	nonSeeker := func(s string) io.Reader {
+		return io.MultiReader(strings.NewReader(s))
+	}
+
+	testCases := []struct {
+		desc         string
+		given        httphandler.Responder
+		givenHeaders map[string]string
+		wantCode     int
+		wantHeaders  map[string]string
+		wantBody     string
+	}{
+		{
+			desc:  "seeker | range",
+			given: downloadresp.Attachment(strings.NewReader("0123456789"), "test.txt").WithETag("v1"),
+			givenHeaders: map[string]string{
+				"Range": "bytes=2-4",
+			},
+			wantCode: http.StatusPartialContent,
+			wantHeaders: map[string]string{
+				"Content-Range":  "bytes 2-4/10",
+				"Content-Length": "3",
+				"ETag":           `"v1"`,
+			},
+			wantBody: "234",
+		},
+		{
+			desc:  "seeker | if-none-match",
+			given: downloadresp.Attachment(strings.NewReader("0123456789"), "test.txt").WithETag(`"v1"`),
+			givenHeaders: map[string]string{
+				"If-None-Match": `"v1"`,
+			},
+			wantCode: http.StatusNotModified,
+			wantBody: "",
+		},
+		{
+			desc:     "non-seeker | size",
+			given:    downloadresp.Attachment(nonSeeker("0123456789"), "test.txt").WithSize(10),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Length": "10",
+			},
+			wantBody: "0123456789",
+		},
+		{
+			desc:  "non-seeker | if-none-match weak",
+			given: downloadresp.Attachment(nonSeeker("0123456789"), "test.txt").WithETag(`W/"v1"`),
+			givenHeaders: map[string]string{
+				"If-None-Match": `"v0", "v1"`,
+			},
+			wantCode: http.StatusNotModified,
+			wantBody: "",
+		},
+		{
+			desc:  "non-seeker | if-none-match mismatch",
+			given: downloadresp.Attachment(nonSeeker("0123456789"), "test.txt").WithETag("v2").WithModTime(modTime),
+			givenHeaders: map[string]string{
+				"If-None-Match":     `"v1"`,
+				"If-Modified-Since": modTime.Format(http.TimeFormat),
+			},
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"ETag":          `"v2"`,
+				"Last-Modified": modTime.Format(http.TimeFormat),
+			},
+			wantBody: "0123456789",
+		},
+		{
+			desc:  "non-seeker | if-modified-since",
+			given: downloadresp.Attachment(nonSeeker("0123456789"), "test.txt").WithModTime(modTime),
+			givenHeaders: map[string]string{
+				"If-Modified-Since": modTime.Format(http.TimeFormat),
+			},
+			wantCode: http.StatusNotModified,
+			wantBody: "",
+		},
+		{
+			desc:  "non-seeker | modified since",
+			given: downloadresp.Attachment(nonSeeker("0123456789"), "test.txt").WithModTime(modTime),
+			givenHeaders: map[string]string{
+				"If-Modified-Since": modTime.Add(-time.Hour).Format(http.TimeFormat),
+			},
+			wantCode: http.StatusOK,
+			wantBody: "0123456789",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test-download", nil)
+			for key, value := range tc.givenHeaders {
+				r.Header.Set(key, value)
+			}
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			for key, want := range tc.wantHeaders {
+				if got := w.Header().Get(key); got != want {
+					t.Errorf("header %s: want '%s', got '%s'", key, want, got)
+				}
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+// statusLogger records the status codes of the responses it logs.
+type statusLogger struct {
+	statuses []any
+}
+
+func (l *statusLogger) Debug(msg string, args ...any) {}
+func (l *statusLogger) Warn(msg string, args ...any)  {}
+func (l *statusLogger) Error(msg string, args ...any) {}
+func (l *statusLogger) Info(msg string, args ...any) {
+	for i := 0; i+1 < len(args); i += 2 {
+		if args[i] == "status_code" {
+			l.statuses = append(l.statuses, args[i+1])
+		}
+	}
+}
+
+func TestAttachment_LogStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc         string
+		givenHeaders map[string]string
+		want         int
+	}{
+		{desc: "full content", want: http.StatusOK},
+		{desc: "range", givenHeaders: map[string]string{"Range": "bytes=2-4"}, want: http.StatusPartialContent},
+		{desc: "not modified", givenHeaders: map[string]string{"If-None-Match": `"v1"`}, want: http.StatusNotModified},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			logger := &statusLogger{}
+			res := downloadresp.Attachment(strings.NewReader("0123456789"), "test.txt").WithETag("v1").WithLogger(logger)
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.givenHeaders {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+
+			// When:
+			res.Respond(w, r)
+
+			// Then: the status code chosen by net/http is logged
+			if w.Code != tc.want {
+				t.Errorf("status code: want %d, got %d", tc.want, w.Code)
+			}
+			if len(logger.statuses) != 1 || logger.statuses[0] != tc.want {
+				t.Errorf("logged status codes: want [%d], got %v", tc.want, logger.statuses)
+			}
+		})
+	}
+}
+
+func TestFromFS_Respond(t *testing.T) {
+	t.Parallel()
+
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"static/report.txt": {Data: []byte("0123456789"), ModTime: modTime},
+	}
+
+	testCases := []struct {
+		desc         string
+		given        httphandler.Responder
+		givenHeaders map[string]string
+		wantCode     int
+		wantHeaders  map[string]string
+		wantBody     string
+	}{
+		{
+			desc:     "found",
+			given:    downloadresp.FromFS(fsys, "static/report.txt"),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":        "text/plain; charset=utf-8",
+				"Content-Disposition": `inline; filename="report.txt"`,
+				"Content-Length":      "10",
+				"Last-Modified":       modTime.Format(http.TimeFormat),
+			},
+			wantBody: "0123456789",
+		},
+		{
+			desc:  "range | with attachment",
+			given: downloadresp.FromFS(fsys, "static/report.txt").WithAttachment(),
+			givenHeaders: map[string]string{
+				"Range": "bytes=5-",
+			},
+			wantCode: http.StatusPartialContent,
+			wantHeaders: map[string]string{
+				"Content-Disposition": `attachment; filename="report.txt"`,
+				"Content-Range":       "bytes 5-9/10",
+			},
+			wantBody: "56789",
+		},
+		{
+			desc:  "not modified",
+			given: downloadresp.FromFS(fsys, "static/report.txt"),
+			givenHeaders: map[string]string{
+				"If-Modified-Since": modTime.Format(http.TimeFormat),
+			},
+			wantCode: http.StatusNotModified,
+			wantBody: "",
+		},
+		{
+			desc:     "missing",
+			given:    downloadresp.FromFS(fsys, "static/missing.txt"),
+			wantCode: http.StatusNotFound,
+			wantBody: "Not Found\n",
+		},
+		{
+			desc:     "directory",
+			given:    downloadresp.FromFS(fsys, "static"),
+			wantCode: http.StatusNotFound,
+			wantBody: "Not Found\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test-download", nil)
+			for key, value := range tc.givenHeaders {
+				r.Header.Set(key, value)
+			}
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			for key, want := range tc.wantHeaders {
+				if got := w.Header().Get(key); got != want {
+					t.Errorf("header %s: want '%s', got '%s'", key, want, got)
+				}
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestFromFile_Respond(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	name := filepath.Join(t.TempDir(), "data.json")
+	if err := os.WriteFile(name, []byte(`{"ok":true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/test-download", nil)
+
+	// When:
+	downloadresp.FromFile(name).Respond(w, r)
+
+	// Then:
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("content type: want '%s', got '%s'", "application/json", got)
+	}
+
+	if got := w.Body.String(); got != `{"ok":true}` {
+		t.Errorf("body: want '%s', got '%s'", `{"ok":true}`, got)
+	}
+}
+
+func TestContentType_SingleValue(t *testing.T) {
+	t.Parallel()
+
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		desc  string
+		given httphandler.Responder
+		want  string
+	}{
+		{
+			desc: "with header | detected type takes precedence",
+			given: downloadresp.Inline(strings.NewReader("OK"), "test.txt").
+				WithHeader("Content-Type", "text/html"),
+			want: "text/plain; charset=utf-8",
+		},
+		{
+			desc: "with content-type | replaces header",
+			given: downloadresp.Inline(strings.NewReader("OK"), "test.txt").
+				WithHeader("Content-Type", "text/html").
+				WithContentType("application/pdf"),
+			want: "application/pdf",
+		},
+		{
+			desc: "with content-type | seekable",
+			given: downloadresp.Inline(strings.NewReader("OK"), "test.txt").
+				WithModTime(modTime).
+				WithContentType("application/pdf"),
+			want: "application/pdf",
+		},
+		{
+			desc: "set header",
+			given: downloadresp.Attachment(strings.NewReader("OK"), "test.txt").
+				SetHeader("Content-Type", "application/pdf"),
+			want: "application/pdf",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			got := w.Result().Header.Values("Content-Type")
+			if len(got) != 1 || got[0] != tc.want {
+				t.Errorf("Content-Type: want [%s], got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+// endlessReader is an io.Reader that never ends.
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'x'
+	}
+	return len(p), nil
+}
+
+func TestAttachment_WriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Given: a download to a client that never reads
+	aborted := make(chan error, 1)
+	srv := httptest.NewServer(httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return downloadresp.Attachment(endlessReader{}, "big.bin").
+			WithWriteTimeout(100 * time.Millisecond).
+			WithOnAbort(func(err error) { aborted <- err })
+	}))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	// When:
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")); err != nil {
+		t.Fatalf("write request: %v", err)
+	}
+
+	// Then: the download is aborted instead of holding the connection
+	select {
+	case err := <-aborted:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("abort error: want %v, got %v", os.ErrDeadlineExceeded, err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("abort: want called, got not called within 10s")
+	}
+}
@@ -0,0 +1,103 @@
+package httphandler
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrorRegistry maps errors returned by handlers and decoders to Responders in one place,
+// instead of deciding status codes at every call site.
+// Mappings are checked in registration order and the first match wins.
+type ErrorRegistry struct {
+	mu       sync.RWMutex
+	mappings []errorMapping
+	fallback ErrorMapper
+}
+
+// errorMapping pairs an error matcher with the ErrorMapper used for matching errors.
+type errorMapping struct {
+	match  func(err error) bool
+	mapper ErrorMapper
+}
+
+// NewErrorRegistry creates an ErrorRegistry that uses fallback for errors that match no mapping.
+// If fallback is nil, unmatched errors are rendered as 500 Internal Server Error.
+func NewErrorRegistry(fallback ErrorMapper) *ErrorRegistry {
+	if fallback == nil {
+		fallback = defaultErrorMapper
+	}
+
+	return &ErrorRegistry{
+		fallback: fallback,
+	}
+}
+
+// Register maps errors matching target according to errors.Is.
+func (reg *ErrorRegistry) Register(target error, mapper ErrorMapper) *ErrorRegistry {
+	return reg.register(func(err error) bool {
+		return errors.Is(err, target)
+	}, mapper)
+}
+
+// RegisterAs maps errors that have an error of type E in their chain according to errors.As.
+func RegisterAs[E error](reg *ErrorRegistry, mapper func(r *http.Request, err E) Responder) *ErrorRegistry {
+	return reg.register(func(err error) bool {
+		var target E
+		return errors.As(err, &target)
+	}, func(r *http.Request, err error) Responder {
+		var target E
+		errors.As(err, &target)
+		return mapper(r, target)
+	})
+}
+
+// register appends a mapping.
+func (reg *ErrorRegistry) register(match func(err error) bool, mapper ErrorMapper) *ErrorRegistry {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.mappings = append(reg.mappings, errorMapping{
+		match:  match,
+		mapper: mapper,
+	})
+	return reg
+}
+
+// lookup returns the ErrorMapper of the first mapping that matches err.
+func (reg *ErrorRegistry) lookup(err error) (ErrorMapper, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	for _, m := range reg.mappings {
+		if m.match(err) {
+			return m.mapper, true
+		}
+	}
+	return nil, false
+}
+
+// Map converts err into a Responder. It satisfies ErrorMapper and DecodeErrorHandler.
+func (reg *ErrorRegistry) Map(r *http.Request, err error) Responder {
+	if mapper, ok := reg.lookup(err); ok {
+		return mapper(r, err)
+	}
+	return reg.fallback(r, err)
+}
+
+// WithErrorRegistry uses reg as the error mapper of the handler.
+// Decoding errors that match a mapping of reg are rendered by it too; others are still
+// rendered by the decode error handler set before this option (400 Bad Request by default).
+func WithErrorRegistry(reg *ErrorRegistry) HandlerOption {
+	return func(o *handlerOptions) {
+		o.errorMapper = reg.Map
+
+		decodeErrorHandler := o.decodeErrorHandler
+		o.decodeErrorHandler = func(r *http.Request, err error) Responder {
+			if mapper, ok := reg.lookup(err); ok {
+				return mapper(r, err)
+			}
+			return decodeErrorHandler(r, err)
+		}
+	}
+}
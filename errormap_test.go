@@ -0,0 +1,149 @@
+package httphandler_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+var errNotFound = errors.New("not found")
+
+type validationError struct {
+	Field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid " + e.Field
+}
+
+func newTestRegistry() *httphandler.ErrorRegistry {
+	reg := httphandler.NewErrorRegistry(nil).
+		Register(errNotFound, jsonresp.MapError("Not found", http.StatusNotFound))
+
+	return httphandler.RegisterAs(reg, func(r *http.Request, err *validationError) httphandler.Responder {
+		return jsonresp.Error(err, err.Error(), http.StatusUnprocessableEntity)
+	})
+}
+
+func TestErrorRegistry_Map(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		given    error
+		wantCode int
+		wantBody string
+	}{
+		{
+			desc:     "sentinel",
+			given:    errNotFound,
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"Not found"}`,
+		},
+		{
+			desc:     "wrapped sentinel",
+			given:    fmt.Errorf("get user: %w", errNotFound),
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"Not found"}`,
+		},
+		{
+			desc:     "error type",
+			given:    fmt.Errorf("create user: %w", &validationError{Field: "email"}),
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: `{"error":"invalid email"}`,
+		},
+		{
+			desc:     "unmatched | fallback",
+			given:    errors.New("database failure"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			newTestRegistry().Map(r, tc.given).Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
+
+func TestWithErrorRegistry(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		given    httphandler.RequestDecodeFunc[string]
+		wantCode int
+	}{
+		{
+			desc: "decode error | mapped",
+			given: func(r *http.Request) (string, error) {
+				return "", &validationError{Field: "name"}
+			},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			desc: "decode error | unmatched",
+			given: func(r *http.Request) (string, error) {
+				return "", errors.New("bad json")
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc: "handler error | mapped",
+			given: func(r *http.Request) (string, error) {
+				return "missing", nil
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+			given := httphandler.HandleWithDecoderE(
+				tc.given,
+				func(r *http.Request, input string) (httphandler.Responder, error) {
+					return nil, errNotFound
+				},
+				httphandler.WithErrorRegistry(newTestRegistry()),
+			)
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+		})
+	}
+}
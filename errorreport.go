@@ -0,0 +1,91 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrorEvent describes a 5xx response reported to an ErrorReporter.
+type ErrorEvent struct {
+	Request *http.Request
+	// Err is the error that caused the response. If it is not known, e.g. for a responder that
+	// wrote a 503 Service Unavailable, it is an error with the status text.
+	Err error
+	// Status is the status code of the response.
+	Status int
+	// User is the value set with SetErrorUser, if any.
+	User any
+	// Tags are the values set with SetErrorTag when the error was reported. The reporter owns
+	// the map.
+	Tags map[string]string
+}
+
+// ErrorReporter sends the errors of 5xx responses to an error tracker such as Sentry or Bugsnag.
+type ErrorReporter interface {
+	CaptureException(ctx context.Context, event ErrorEvent)
+}
+
+// ErrorReporterFunc is an adapter to allow the use of ordinary functions as ErrorReporters.
+type ErrorReporterFunc func(ctx context.Context, event ErrorEvent)
+
+// CaptureException calls f(ctx, event).
+func (f ErrorReporterFunc) CaptureException(ctx context.Context, event ErrorEvent) {
+	f(ctx, event)
+}
+
+// errorReporter holds the reporter set with SetErrorReporter.
+var errorReporter atomic.Pointer[ErrorReporter]
+
+// SetErrorReporter sets the reporter that the errors of 5xx responses written by handlers are
+// captured with, in the same cases as OnServerError: errors returned to HandleE, failures
+// passed to WriteInternalServerError and panics. Passing nil removes the reporter.
+func SetErrorReporter(reporter ErrorReporter) {
+	if reporter == nil {
+		errorReporter.Store(nil)
+		return
+	}
+	errorReporter.Store(&reporter)
+}
+
+// SetErrorUser sets the user reported with the errors of the request, e.g. by the decoder
+// that authenticates it. It does nothing if no reporter or hook is set.
+func SetErrorUser(r *http.Request, user any) {
+	if sw, ok := r.Context().Value(serverErrorKey{}).(*serverErrorWriter); ok {
+		sw.mu.Lock()
+		sw.user = user
+		sw.mu.Unlock()
+	}
+}
+
+// SetErrorTag sets a tag reported with the errors of the request, e.g. a tenant or feature.
+// It does nothing if no reporter or hook is set.
+func SetErrorTag(r *http.Request, key, value string) {
+	if sw, ok := r.Context().Value(serverErrorKey{}).(*serverErrorWriter); ok {
+		sw.mu.Lock()
+		if sw.tags == nil {
+			sw.tags = map[string]string{}
+		}
+		sw.tags[key] = value
+		sw.mu.Unlock()
+	}
+}
+
+// event returns the ErrorEvent for a response with the status code caused by err.
+func (w *serverErrorWriter) event(r *http.Request, err error, status int) ErrorEvent {
+	if err == nil {
+		err = errors.New(http.StatusText(status))
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return ErrorEvent{
+		Request: r,
+		Err:     err,
+		Status:  status,
+		User:    w.user,
+		Tags:    maps.Clone(w.tags),
+	}
+}
@@ -0,0 +1,147 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// TestSetErrorReporter is not parallel because it changes the package-level reporter.
+func TestSetErrorReporter(t *testing.T) {
+	errHandler := errors.New("handler failure")
+
+	decodeUser := func(r *http.Request) (string, error) {
+		httphandler.SetErrorUser(r, "alice")
+		return "alice", nil
+	}
+
+	testCases := []struct {
+		desc        string
+		given       http.HandlerFunc
+		wantCalled  bool
+		wantErrText string
+		wantStatus  int
+		wantUser    any
+		wantTags    map[string]string
+	}{
+		{
+			desc: "handler error | with user and tags",
+			given: httphandler.HandleWithDecoderE(decodeUser, func(r *http.Request, user string) (httphandler.Responder, error) {
+				httphandler.SetErrorTag(r, "tenant", "acme")
+				return nil, errHandler
+			}),
+			wantCalled:  true,
+			wantErrText: "handler failure",
+			wantStatus:  http.StatusInternalServerError,
+			wantUser:    "alice",
+			wantTags:    map[string]string{"tenant": "acme"},
+		},
+		{
+			desc: "5xx responder | without error",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				})
+			}),
+			wantCalled:  true,
+			wantErrText: "Service Unavailable",
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			desc: "client error",
+			given: httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+				return nil, errHandler
+			}, httphandler.WithErrorMapper(func(r *http.Request, err error) httphandler.Responder {
+				return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNotFound)
+				})
+			})),
+			wantCalled: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			var (
+				called bool
+				got    httphandler.ErrorEvent
+			)
+			httphandler.SetErrorReporter(httphandler.ErrorReporterFunc(func(ctx context.Context, event httphandler.ErrorEvent) {
+				called = true
+				got = event
+			}))
+			defer httphandler.SetErrorReporter(nil)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("{}"))
+
+			// When:
+			tc.given.ServeHTTP(w, r)
+
+			// Then:
+			if called != tc.wantCalled {
+				t.Fatalf("called: want %t, got %t", tc.wantCalled, called)
+			}
+			if !called {
+				return
+			}
+
+			if got.Err == nil || got.Err.Error() != tc.wantErrText {
+				t.Errorf("error: want %s, got %v", tc.wantErrText, got.Err)
+			}
+
+			if got.Status != tc.wantStatus {
+				t.Errorf("status: want %d, got %d", tc.wantStatus, got.Status)
+			}
+
+			if got.User != tc.wantUser {
+				t.Errorf("user: want %v, got %v", tc.wantUser, got.User)
+			}
+
+			if len(got.Tags) != len(tc.wantTags) {
+				t.Errorf("tag count: want %d, got %d", len(tc.wantTags), len(got.Tags))
+			}
+			for key, want := range tc.wantTags {
+				if got.Tags[key] != want {
+					t.Errorf("tag %s: want %s, got %s", key, want, got.Tags[key])
+				}
+			}
+
+			if got.Request == nil || got.Request.URL.Path != "/orders" {
+				t.Errorf("request: want /orders, got %v", got.Request)
+			}
+		})
+	}
+}
+
+// TestSetErrorReporter_Tags is not parallel because it changes the package-level reporter.
+func TestSetErrorReporter_Tags(t *testing.T) {
+	// Given: a reporter that keeps the event, and a tag set after the error was reported
+	var got httphandler.ErrorEvent
+	httphandler.SetErrorReporter(httphandler.ErrorReporterFunc(func(ctx context.Context, event httphandler.ErrorEvent) {
+		got = event
+	}))
+	defer httphandler.SetErrorReporter(nil)
+
+	var inner *http.Request
+	h := httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+		inner = r
+		httphandler.SetErrorTag(r, "tenant", "acme")
+		return nil, errors.New("handler failure")
+	})
+
+	// When:
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	httphandler.SetErrorTag(inner, "tenant", "other")
+
+	// Then: the reported tags are a snapshot
+	if got.Tags["tenant"] != "acme" {
+		t.Errorf("tag tenant: want %s, got %s", "acme", got.Tags["tenant"])
+	}
+}
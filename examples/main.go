@@ -1,16 +1,14 @@
 package main
 
 import (
-	"encoding/csv"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/alvinchoong/go-httphandler"
-	"github.com/alvinchoong/go-httphandler/downloadresp"
+	"github.com/alvinchoong/go-httphandler/csvresp"
 	"github.com/alvinchoong/go-httphandler/jsonresp"
 	"github.com/alvinchoong/go-httphandler/plainresp"
 )
@@ -127,31 +125,20 @@ func deleteUser(r *http.Request) httphandler.Responder {
 }
 
 func downloadUsers(r *http.Request) httphandler.Responder {
-	pr, pw := io.Pipe()
-	go func() {
-		defer pw.Close()
-
-		csvWriter := csv.NewWriter(pw)
-		defer csvWriter.Flush()
-
-		// Write header
-		csvWriter.Write([]string{"ID", "Name", "Age", "Created At"})
-
-		for _, user := range users {
-			if err := csvWriter.Write([]string{
-				user.ID,
-				user.Name,
-				strconv.Itoa(user.Age),
-				user.CreatedAt.Format(time.RFC3339),
-			}); err != nil {
-				pw.CloseWithError(err)
-				return
-			}
-		}
-	}()
+	userList := make([]User, 0, len(users))
+	for _, user := range users {
+		userList = append(userList, user)
+	}
 
-	return downloadresp.Attachment(pr, "users.csv").
-		WithContentType("text/csv")
+	header := []string{"ID", "Name", "Age", "Created At"}
+	return csvresp.Records(userList, header, func(user User) []string {
+		return []string{
+			user.ID,
+			user.Name,
+			strconv.Itoa(user.Age),
+			user.CreatedAt.Format(time.RFC3339),
+		}
+	}).WithFilename("users.csv")
 }
 
 func redirectExample(r *http.Request) httphandler.Responder {
@@ -0,0 +1,101 @@
+package httphandler
+
+import "net/http"
+
+// Extend2To3 returns a RequestDecodeFunc that runs base, e.g. a shared tenant and user
+// pipeline built with Combine2, and then d3, so that a route can add a decoder without
+// listing the decoders of base again. Decoding stops at the first error, which is returned as a *StageError.
+func Extend2To3[T1, T2, T3 any](base RequestDecodeFunc[Tuple2[T1, T2]], d3 RequestDecodeFunc[T3]) RequestDecodeFunc[Tuple3[T1, T2, T3]] {
+	return func(r *http.Request) (Tuple3[T1, T2, T3], error) {
+		t, err := base(r)
+		if err != nil {
+			return Tuple3[T1, T2, T3]{}, err
+		}
+		v3, err := runStage(r, 3, d3)
+		if err != nil {
+			return Tuple3[T1, T2, T3]{}, err
+		}
+
+		return Tuple3[T1, T2, T3]{V1: t.V1, V2: t.V2, V3: v3}, nil
+	}
+}
+
+// Extend3To4 is like Extend2To3 for a base of three decoders.
+func Extend3To4[T1, T2, T3, T4 any](base RequestDecodeFunc[Tuple3[T1, T2, T3]], d4 RequestDecodeFunc[T4]) RequestDecodeFunc[Tuple4[T1, T2, T3, T4]] {
+	return func(r *http.Request) (Tuple4[T1, T2, T3, T4], error) {
+		t, err := base(r)
+		if err != nil {
+			return Tuple4[T1, T2, T3, T4]{}, err
+		}
+		v4, err := runStage(r, 4, d4)
+		if err != nil {
+			return Tuple4[T1, T2, T3, T4]{}, err
+		}
+
+		return Tuple4[T1, T2, T3, T4]{V1: t.V1, V2: t.V2, V3: t.V3, V4: v4}, nil
+	}
+}
+
+// Extend4To5 is like Extend2To3 for a base of four decoders.
+func Extend4To5[T1, T2, T3, T4, T5 any](base RequestDecodeFunc[Tuple4[T1, T2, T3, T4]], d5 RequestDecodeFunc[T5]) RequestDecodeFunc[Tuple5[T1, T2, T3, T4, T5]] {
+	return func(r *http.Request) (Tuple5[T1, T2, T3, T4, T5], error) {
+		t, err := base(r)
+		if err != nil {
+			return Tuple5[T1, T2, T3, T4, T5]{}, err
+		}
+		v5, err := runStage(r, 5, d5)
+		if err != nil {
+			return Tuple5[T1, T2, T3, T4, T5]{}, err
+		}
+
+		return Tuple5[T1, T2, T3, T4, T5]{V1: t.V1, V2: t.V2, V3: t.V3, V4: t.V4, V5: v5}, nil
+	}
+}
+
+// Extend5To6 is like Extend2To3 for a base of five decoders.
+func Extend5To6[T1, T2, T3, T4, T5, T6 any](base RequestDecodeFunc[Tuple5[T1, T2, T3, T4, T5]], d6 RequestDecodeFunc[T6]) RequestDecodeFunc[Tuple6[T1, T2, T3, T4, T5, T6]] {
+	return func(r *http.Request) (Tuple6[T1, T2, T3, T4, T5, T6], error) {
+		t, err := base(r)
+		if err != nil {
+			return Tuple6[T1, T2, T3, T4, T5, T6]{}, err
+		}
+		v6, err := runStage(r, 6, d6)
+		if err != nil {
+			return Tuple6[T1, T2, T3, T4, T5, T6]{}, err
+		}
+
+		return Tuple6[T1, T2, T3, T4, T5, T6]{V1: t.V1, V2: t.V2, V3: t.V3, V4: t.V4, V5: t.V5, V6: v6}, nil
+	}
+}
+
+// Extend6To7 is like Extend2To3 for a base of six decoders.
+func Extend6To7[T1, T2, T3, T4, T5, T6, T7 any](base RequestDecodeFunc[Tuple6[T1, T2, T3, T4, T5, T6]], d7 RequestDecodeFunc[T7]) RequestDecodeFunc[Tuple7[T1, T2, T3, T4, T5, T6, T7]] {
+	return func(r *http.Request) (Tuple7[T1, T2, T3, T4, T5, T6, T7], error) {
+		t, err := base(r)
+		if err != nil {
+			return Tuple7[T1, T2, T3, T4, T5, T6, T7]{}, err
+		}
+		v7, err := runStage(r, 7, d7)
+		if err != nil {
+			return Tuple7[T1, T2, T3, T4, T5, T6, T7]{}, err
+		}
+
+		return Tuple7[T1, T2, T3, T4, T5, T6, T7]{V1: t.V1, V2: t.V2, V3: t.V3, V4: t.V4, V5: t.V5, V6: t.V6, V7: v7}, nil
+	}
+}
+
+// Extend7To8 is like Extend2To3 for a base of seven decoders.
+func Extend7To8[T1, T2, T3, T4, T5, T6, T7, T8 any](base RequestDecodeFunc[Tuple7[T1, T2, T3, T4, T5, T6, T7]], d8 RequestDecodeFunc[T8]) RequestDecodeFunc[Tuple8[T1, T2, T3, T4, T5, T6, T7, T8]] {
+	return func(r *http.Request) (Tuple8[T1, T2, T3, T4, T5, T6, T7, T8], error) {
+		t, err := base(r)
+		if err != nil {
+			return Tuple8[T1, T2, T3, T4, T5, T6, T7, T8]{}, err
+		}
+		v8, err := runStage(r, 8, d8)
+		if err != nil {
+			return Tuple8[T1, T2, T3, T4, T5, T6, T7, T8]{}, err
+		}
+
+		return Tuple8[T1, T2, T3, T4, T5, T6, T7, T8]{V1: t.V1, V2: t.V2, V3: t.V3, V4: t.V4, V5: t.V5, V6: t.V6, V7: t.V7, V8: v8}, nil
+	}
+}
@@ -0,0 +1,97 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestExtend2To3(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		given   *http.Request
+		want    httphandler.Tuple3[string, string, string]
+		wantErr bool
+	}{
+		{
+			desc: "all decoded",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/?sort=name&page=2", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			}(),
+			want: httphandler.Tuple3[string, string, string]{V1: "acme", V2: "name", V3: "2"},
+		},
+		{
+			desc:    "base fails",
+			given:   httptest.NewRequest(http.MethodGet, "/?sort=name&page=2", nil),
+			wantErr: true,
+		},
+		{
+			desc: "extension fails",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/?sort=name", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given: a base pipeline extended with a decoder
+			base := httphandler.Combine2(headerDecode("X-Tenant"), queryDecode("sort"))
+			decode := httphandler.Extend2To3(base, func(r *http.Request) (string, error) {
+				page := r.URL.Query().Get("page")
+				if page == "" {
+					return "", errors.New("missing page")
+				}
+				return page, nil
+			})
+
+			// When:
+			got, err := decode(tc.given)
+
+			// Then:
+			if (err != nil) != tc.wantErr {
+				t.Errorf("error: want %t, got %v", tc.wantErr, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("value: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestExtend7To8(t *testing.T) {
+	t.Parallel()
+
+	// Given: a base pipeline of seven decoders extended with an eighth
+	q := queryDecode
+	base := httphandler.Combine7(q("a"), q("b"), q("c"), q("d"), q("e"), q("f"), q("g"))
+	decode := httphandler.Extend7To8(base, q("h"))
+	r := httptest.NewRequest(http.MethodGet, "/?a=1&b=2&c=3&d=4&e=5&f=6&g=7&h=8", nil)
+
+	// When:
+	got, err := decode(r)
+
+	// Then:
+	if err != nil {
+		t.Fatalf("error: want nil, got %v", err)
+	}
+	want := httphandler.Tuple8[string, string, string, string, string, string, string, string]{
+		V1: "1", V2: "2", V3: "3", V4: "4", V5: "5", V6: "6", V7: "7", V8: "8",
+	}
+	if got != want {
+		t.Errorf("value: want %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,143 @@
+package httphandler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var ErrFormDecode = errors.New("fail to decode form")
+
+// maxFormMemory is the memory used to hold multipart forms before spilling files to disk.
+const maxFormMemory = 32 << 20
+
+// FormBody returns a RequestDecodeFunc that decodes an application/x-www-form-urlencoded or
+// multipart/form-data body into a struct of type T.
+// Fields are matched by their `form:"name"` tag, or by field name if there is no tag, and
+// fields tagged `form:"-"` are skipped. Strings, booleans, integers, floats, time.Time (RFC 3339)
+// and slices of these are supported.
+func FormBody[T any]() RequestDecodeFunc[T] {
+	return func(r *http.Request) (T, error) {
+		var v T
+
+		rv := reflect.ValueOf(&v).Elem()
+		if rv.Kind() != reflect.Struct {
+			return v, fmt.Errorf("%w: %T is not a struct", ErrFormDecode, v)
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		var err error
+		if mediaType == "multipart/form-data" {
+			err = r.ParseMultipartForm(maxFormMemory)
+		} else {
+			err = r.ParseForm()
+		}
+		if err != nil {
+			return v, fmt.Errorf("%w: %w", ErrFormDecode, err)
+		}
+
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			field := rt.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			name := field.Tag.Get("form")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			values, ok := r.PostForm[name]
+			if !ok || len(values) == 0 {
+				continue
+			}
+
+			if err := setFormField(rv.Field(i), values); err != nil {
+				return v, fmt.Errorf("%w: field %s: %w", ErrFormDecode, name, err)
+			}
+		}
+
+		return v, nil
+	}
+}
+
+// timeType is the reflect.Type of time.Time.
+var timeType = reflect.TypeOf(time.Time{})
+
+// setFormField sets a struct field from its form values.
+func setFormField(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setFormValue(slice.Index(i), value); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+
+	return setFormValue(fv, values[0])
+}
+
+// setFormValue converts a single form value to the type of fv and sets it.
+func setFormValue(fv reflect.Value, value string) error {
+	if fv.Type() == timeType {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		// A checked checkbox without a value attribute sends "on". An empty value leaves
+		// the field false.
+		if value == "on" {
+			fv.SetBool(true)
+			return nil
+		}
+		if value == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+
+	return nil
+}
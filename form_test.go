@@ -0,0 +1,129 @@
+package httphandler_test
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+type signupForm struct {
+	Name      string    `form:"name"`
+	Age       int       `form:"age"`
+	Height    float64   `form:"height"`
+	Subscribe bool      `form:"subscribe"`
+	Tags      []string  `form:"tag"`
+	Scores    []uint8   `form:"score"`
+	Birthday  time.Time `form:"birthday"`
+	Nickname  string
+	Secret    string `form:"-"`
+}
+
+func TestFormBody(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		given   func() *http.Request
+		want    signupForm
+		wantErr error
+	}{
+		{
+			desc: "urlencoded",
+			given: func() *http.Request {
+				body := "name=alice&age=30&height=1.65&subscribe=on&tag=a&tag=b&score=1&score=2" +
+					"&birthday=1994-05-06T00:00:00Z&Nickname=al&Secret=x"
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return r
+			},
+			want: signupForm{
+				Name:      "alice",
+				Age:       30,
+				Height:    1.65,
+				Subscribe: true,
+				Tags:      []string{"a", "b"},
+				Scores:    []uint8{1, 2},
+				Birthday:  time.Date(1994, 5, 6, 0, 0, 0, 0, time.UTC),
+				Nickname:  "al",
+			},
+		},
+		{
+			desc: "multipart",
+			given: func() *http.Request {
+				var buf bytes.Buffer
+				mw := multipart.NewWriter(&buf)
+				mw.WriteField("name", "bob")
+				mw.WriteField("age", "41")
+				mw.WriteField("subscribe", "false")
+				mw.Close()
+
+				r := httptest.NewRequest(http.MethodPost, "/", &buf)
+				r.Header.Set("Content-Type", mw.FormDataContentType())
+				return r
+			},
+			want: signupForm{Name: "bob", Age: 41},
+		},
+		{
+			desc: "empty boolean",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=carol&subscribe="))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return r
+			},
+			want: signupForm{Name: "carol"},
+		},
+		{
+			desc: "query parameters are ignored",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/?name=mallory", strings.NewReader("age=1"))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return r
+			},
+			want: signupForm{Age: 1},
+		},
+		{
+			desc: "invalid int",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("age=old"))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return r
+			},
+			wantErr: httphandler.ErrFormDecode,
+		},
+		{
+			desc: "overflow",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("score=300"))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return r
+			},
+			wantErr: httphandler.ErrFormDecode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got, err := httphandler.FormBody[signupForm]()(tc.given())
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if tc.wantErr == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("value: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
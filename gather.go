@@ -0,0 +1,90 @@
+package httphandler
+
+import (
+	"context"
+	"sync"
+)
+
+// Gather runs funcs concurrently and waits for all of them to return.
+// Each func receives a context derived from ctx (usually the request context), so the request
+// deadline applies to every func. The first error cancels the context of the others and is returned.
+// A panic in any func is re-raised in the calling goroutine once all funcs have returned,
+// so that it reaches Recover or WithPanicHandler like a panic in the handler itself, with the
+// stack of the func.
+func Gather(ctx context.Context, funcs ...func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		once      sync.Once
+		firstErr  error
+		recovered *handlerPanic
+		panicOnce sync.Once
+	)
+	for _, fn := range funcs {
+		wg.Add(1)
+		go func(fn func(ctx context.Context) error) {
+			defer wg.Done()
+			defer func() {
+				if v := recover(); v != nil {
+					panicOnce.Do(func() { recovered = newHandlerPanic(v) })
+					cancel()
+				}
+			}()
+
+			if err := fn(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(fn)
+	}
+	wg.Wait()
+
+	if recovered != nil {
+		recovered.raise()
+	}
+
+	return firstErr
+}
+
+// GatherAll runs the named funcs concurrently and waits for all of them to return.
+// Unlike Gather, a failing func does not cancel the others, which makes it suitable for
+// aggregating independent backends where partial results are acceptable.
+// The returned map contains the error of each func that failed, keyed by name.
+// Panics are re-raised in the calling goroutine as in Gather.
+func GatherAll(ctx context.Context, funcs map[string]func(ctx context.Context) error) map[string]error {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		errs      = map[string]error{}
+		recovered *handlerPanic
+		panicOnce sync.Once
+	)
+	for name, fn := range funcs {
+		wg.Add(1)
+		go func(name string, fn func(ctx context.Context) error) {
+			defer wg.Done()
+			defer func() {
+				if v := recover(); v != nil {
+					panicOnce.Do(func() { recovered = newHandlerPanic(v) })
+				}
+			}()
+
+			if err := fn(ctx); err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, fn)
+	}
+	wg.Wait()
+
+	if recovered != nil {
+		recovered.raise()
+	}
+
+	return errs
+}
@@ -0,0 +1,147 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestGather(t *testing.T) {
+	t.Parallel()
+
+	errBackend := errors.New("backend failed")
+
+	testCases := []struct {
+		desc    string
+		given   []func(ctx context.Context) error
+		wantErr error
+	}{
+		{
+			desc: "all succeed",
+			given: []func(ctx context.Context) error{
+				func(ctx context.Context) error { return nil },
+				func(ctx context.Context) error { return nil },
+			},
+			wantErr: nil,
+		},
+		{
+			desc: "one fails | others cancelled",
+			given: []func(ctx context.Context) error{
+				func(ctx context.Context) error { return errBackend },
+				func(ctx context.Context) error {
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(time.Second):
+						return errors.New("context was not cancelled")
+					}
+				},
+			},
+			wantErr: errBackend,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			err := httphandler.Gather(context.Background(), tc.given...)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGather_Panic(t *testing.T) {
+	t.Parallel()
+
+	boom := func(ctx context.Context) error { panic("boom") }
+	nop := func(ctx context.Context) error { return nil }
+
+	testCases := []struct {
+		desc   string
+		gather func(ctx context.Context)
+	}{
+		{
+			desc: "Gather",
+			gather: func(ctx context.Context) {
+				httphandler.Gather(ctx, boom, nop)
+			},
+		},
+		{
+			desc: "GatherAll",
+			gather: func(ctx context.Context) {
+				httphandler.GatherAll(ctx, map[string]func(ctx context.Context) error{"boom": boom, "nop": nop})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var gotStack []byte
+			h := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				tc.gather(r.Context())
+				return nil
+			},
+				httphandler.WithPanicHandler(func(ctx context.Context, recovered any, stack []byte) httphandler.Responder {
+					gotStack = stack
+					return &mockResponder{StatusCode: http.StatusInternalServerError, Body: fmt.Sprint(recovered)}
+				}),
+			)
+			w := httptest.NewRecorder()
+
+			// When:
+			h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			// Then: the panic is recovered by the panic handler
+			if got := w.Body.String(); got != "boom" {
+				t.Errorf("body: want %q, got %q", "boom", got)
+			}
+			// The stack is the one of the goroutine of the func, where the panic happened.
+			if !strings.Contains(string(gotStack), "TestGather_Panic.func1") {
+				t.Errorf("stack: want the func frame, got\n%s", gotStack)
+			}
+		})
+	}
+}
+
+func TestGatherAll(t *testing.T) {
+	t.Parallel()
+
+	errBilling := errors.New("billing unavailable")
+
+	// When:
+	got := httphandler.GatherAll(context.Background(), map[string]func(ctx context.Context) error{
+		"profile": func(ctx context.Context) error { return nil },
+		"billing": func(ctx context.Context) error { return errBilling },
+		"orders": func(ctx context.Context) error {
+			if ctx.Err() != nil {
+				return errors.New("context should not be cancelled")
+			}
+			return nil
+		},
+	})
+
+	// Then:
+	if len(got) != 1 {
+		t.Errorf("error count: want 1, got %d (%v)", len(got), got)
+	}
+
+	if !errors.Is(got["billing"], errBilling) {
+		t.Errorf("billing error: want %v, got %v", errBilling, got["billing"])
+	}
+}
@@ -0,0 +1,48 @@
+package httphandler
+
+import (
+	"errors"
+	"net/http"
+)
+
+// HaltError is returned by Halt. It carries the Responder to send instead of the decoded input.
+type HaltError struct {
+	Responder Responder
+}
+
+// Error implements the error interface.
+func (e *HaltError) Error() string {
+	return "httphandler: decoding halted"
+}
+
+// Halt returns an error that makes the handler respond immediately with res when a decoder
+// returns it, bypassing the decode error handler, e.g. to redirect to the login page or to send
+// 304 Not Modified. It may be wrapped, e.g. by Combine or Parallel. As for handlers, a nil res
+// sends 204 No Content.
+func Halt(res Responder) error {
+	return &HaltError{Responder: res}
+}
+
+// halted returns the Responder of the HaltError in err, if any.
+func halted(err error) (Responder, bool) {
+	var haltErr *HaltError
+	if !errors.As(err, &haltErr) {
+		return nil, false
+	}
+	if haltErr.Responder == nil {
+		return ResponderFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}), true
+	}
+	return haltErr.Responder, true
+}
+
+// decodeErrorResponder returns the Responder of a halted decoder, or the Responder of the decode
+// error handler for err, which is recorded as the cause of the response.
+func decodeErrorResponder(r *http.Request, err error, handler DecodeErrorHandler) Responder {
+	if res, ok := halted(err); ok {
+		return res
+	}
+	recordRequestError(r, err)
+	return handler(r, err)
+}
@@ -0,0 +1,112 @@
+package httphandler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestHalt(t *testing.T) {
+	t.Parallel()
+
+	session := func(r *http.Request) (string, error) {
+		if r.Header.Get("Cookie") == "" {
+			return "", httphandler.Halt(httphandler.Redirect("/login", http.StatusFound))
+		}
+		return "alice", nil
+	}
+	notModified := func(r *http.Request) (string, error) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			return "", fmt.Errorf("etag: %w", httphandler.Halt(httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotModified)
+			})))
+		}
+		return "v1", nil
+	}
+	empty := func(r *http.Request) (string, error) {
+		return "", httphandler.Halt(nil)
+	}
+
+	testCases := []struct {
+		desc         string
+		givenDecode  httphandler.RequestDecodeFunc[string]
+		givenHeaders map[string]string
+		wantCode     int
+		wantLocation string
+	}{
+		{
+			desc:         "redirect to login",
+			givenDecode:  session,
+			wantCode:     http.StatusFound,
+			wantLocation: "/login",
+		},
+		{
+			desc:         "not halted",
+			givenDecode:  session,
+			givenHeaders: map[string]string{"Cookie": "session=1"},
+			wantCode:     http.StatusOK,
+		},
+		{
+			desc:         "wrapped | not modified",
+			givenDecode:  notModified,
+			givenHeaders: map[string]string{"If-None-Match": `"v1"`},
+			wantCode:     http.StatusNotModified,
+		},
+		{
+			desc: "stage of a combined decoder",
+			givenDecode: httphandler.CombineWith2(notModified, session, func(etag, user string) string {
+				return etag + user
+			}),
+			givenHeaders: map[string]string{"If-None-Match": `"v1"`},
+			wantCode:     http.StatusNotModified,
+		},
+		{
+			desc:        "nil responder | 204",
+			givenDecode: empty,
+			wantCode:    http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var decodeErrors int
+			h := httphandler.HandleWithDecoder(tc.givenDecode, func(r *http.Request, input string) httphandler.Responder {
+				return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				})
+			},
+				httphandler.WithDecodeErrorHandler(func(r *http.Request, err error) httphandler.Responder {
+					decodeErrors++
+					return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusBadRequest)
+					})
+				}),
+			)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tc.givenHeaders {
+				r.Header.Set(k, v)
+			}
+
+			// When:
+			h(w, r)
+
+			// Then: the decode error handler is bypassed
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := w.Header().Get("Location"); got != tc.wantLocation {
+				t.Errorf("location: want %q, got %q", tc.wantLocation, got)
+			}
+			if decodeErrors != 0 {
+				t.Errorf("decode error handler: want 0 calls, got %d", decodeErrors)
+			}
+		})
+	}
+}
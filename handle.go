@@ -1,10 +1,15 @@
 package httphandler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"reflect"
+	"sync/atomic"
+	"time"
 )
 
 // Responder defines how to respond to HTTP requests.
@@ -16,72 +21,505 @@ type Responder interface {
 type RequestHandler func(r *http.Request) Responder
 
 // Handle converts a RequestHandler to an http.HandlerFunc.
-func Handle(handler RequestHandler) http.HandlerFunc {
+// Options that concern decoding are ignored.
+func Handle(handler RequestHandler, opts ...HandlerOption) http.HandlerFunc {
+	return newHandlerOptions(opts...).wrap(handle(handler))
+}
+
+// handle converts a RequestHandler to an http.HandlerFunc without applying any options.
+func handle(handler RequestHandler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		t := timingFrom(r)
+
+		start := t.start()
 		res := handler(r)
+		t.observeHandle(start)
+		t.writeHeader(w)
+
 		if res == nil {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
+		start = t.start()
 		res.Respond(w, r)
+		t.observeEncode(start)
 	}
 }
 
+// RequestHandlerCtx handles an HTTP request with its context and returns a Responder.
+type RequestHandlerCtx func(ctx context.Context, r *http.Request) Responder
+
+// HandleCtx converts a RequestHandlerCtx to an http.HandlerFunc.
+// The handler receives the request context explicitly. It accepts the same options as Handle.
+func HandleCtx(handler RequestHandlerCtx, opts ...HandlerOption) http.HandlerFunc {
+	return Handle(func(r *http.Request) Responder {
+		return handler(r.Context(), r)
+	}, opts...)
+}
+
+// ResponderFunc is an adapter to allow the use of ordinary functions as Responders.
+type ResponderFunc func(w http.ResponseWriter, r *http.Request)
+
+// Respond calls f(w, r).
+func (f ResponderFunc) Respond(w http.ResponseWriter, r *http.Request) {
+	f(w, r)
+}
+
 // RequestDecodeFunc defines how to decode an HTTP request.
 type RequestDecodeFunc[T any] func(r *http.Request) (T, error)
 
 // RequestHandlerWithInput handles an HTTP request with decoded input and returns a Responder.
 type RequestHandlerWithInput[T any] func(r *http.Request, input T) Responder
 
-// HandleWithInput converts a RequestHandlerWithInput to an http.HandlerFunc.
+// DecodeErrorHandler converts an error returned by a RequestDecodeFunc into a Responder.
+type DecodeErrorHandler func(r *http.Request, err error) Responder
+
+// HandlerOption configures a handler created by Handle, HandleWithInput and their variants.
+// Options do not depend on the input type of the handler; the decoder of the input is passed
+// to HandleWithDecoder instead.
+type HandlerOption func(*handlerOptions)
+
+// Bundle combines opts into a single option, applied in order, e.g. for presets of options
+// shared by many handlers.
+func Bundle(opts ...HandlerOption) HandlerOption {
+	return func(o *handlerOptions) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
+
+// defaultHandlerOptions holds the options set with SetDefaultHandlerOptions.
+var defaultHandlerOptions atomic.Pointer[[]HandlerOption]
+
+// SetDefaultHandlerOptions sets options applied to every handler created afterwards.
+// They are applied before the options passed to the handler, so the latter take precedence.
+// Handlers that were already created are not affected. Calling it again replaces the defaults.
+func SetDefaultHandlerOptions(opts ...HandlerOption) {
+	defaultHandlerOptions.Store(&opts)
+}
+
+// handlerOptions holds the settings that can be changed with a HandlerOption.
+type handlerOptions struct {
+	decodeFunc          any
+	decodeErrorHandler  DecodeErrorHandler
+	errorMapper         ErrorMapper
+	panicHandler        PanicHandler
+	precheck            func(r *http.Request) Responder
+	rolloutGate         RolloutGate
+	hiddenResponder     Responder
+	maxBodyBytes        int64
+	meter               Meter
+	responseObserver    ResponseObserver
+	accessLogger        Logger
+	accessLogFields     []any
+	sloTarget           time.Duration
+	sloViolationHandler SLOViolationHandler
+	serverTiming        bool
+	stageObserver       StageObserver
+	stageTracer         StageTracer
+	stageNames          []string
+	contextEnricher     ContextEnricher
+	headerFilter        *HeaderFilter
+	timeout             time.Duration
+	timeoutResponder    Responder
+	catalog             *Catalog
+	route               string
+	examples            []catalogExample
+	doc                 RouteDoc
+	input               reflect.Type
+}
+
+// newHandlerOptions returns the default options with the package defaults and then opts applied.
+func newHandlerOptions(opts ...HandlerOption) handlerOptions {
+	o := handlerOptions{
+		decodeErrorHandler: defaultDecodeErrorHandler,
+		errorMapper:        defaultErrorMapper,
+	}
+	if defaults := defaultHandlerOptions.Load(); defaults != nil {
+		for _, opt := range *defaults {
+			opt(&o)
+		}
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// wrap applies the options that are common to all handlers: the body limit, stage observation,
+// the timeout, the rollout gate of handlers without input, the precheck, panic recovery, server
+// error reporting, timing, response observation, metering, the access log and the header
+// filter. It also adds the examples, the pipeline and the documentation of the handler to its
+// catalog.
+func (o handlerOptions) wrap(h http.HandlerFunc) http.HandlerFunc {
+	if o.catalog != nil {
+		o.catalog.add(o.route, o.examples, o.pipeline(), o.doc)
+	}
+	if o.maxBodyBytes > 0 {
+		next := h
+		h = func(w http.ResponseWriter, r *http.Request) {
+			r2 := *r
+			r2.Body = http.MaxBytesReader(w, r.Body, o.maxBodyBytes)
+			next(w, &r2)
+		}
+	}
+	if o.stageObserver != nil || o.stageTracer != nil || o.contextEnricher != nil {
+		h = stageHandler(h, o.stageObserver, o.stageTracer, o.stageNames)
+	}
+	if o.timeout > 0 {
+		h = timeoutHandler(h, o.timeout, o.timeoutResponder)
+	}
+	if o.rolloutGate != nil && o.input == nil {
+		next := h
+		h = func(w http.ResponseWriter, r *http.Request) {
+			if res := o.hidden(r, nil); res != nil {
+				res.Respond(w, r)
+				return
+			}
+			next(w, r)
+		}
+	}
+	if o.precheck != nil {
+		next := h
+		h = func(w http.ResponseWriter, r *http.Request) {
+			if res := o.precheck(r); res != nil {
+				res.Respond(w, r)
+				return
+			}
+			next(w, r)
+		}
+	}
+	if o.panicHandler != nil {
+		h = Recover(h, o.panicHandler)
+	}
+	h = serverErrorHandler(h)
+	if o.sloTarget > 0 || o.serverTiming {
+		h = timeHandler(h, o)
+	}
+	if o.responseObserver != nil {
+		h = observeHandler(h, o.responseObserver)
+	}
+	if o.meter != nil {
+		h = meterHandler(h, o.meter)
+	}
+	if o.accessLogger != nil {
+		h = logAccessHandler(h, o.accessLogger, o.accessLogFields)
+	}
+	if o.headerFilter != nil {
+		h = headerFilterHandler(h, o.headerFilter)
+	}
+
+	return h
+}
+
+// handleWithInput decodes the request before passing it to the handler.
 type handleWithInput[T any] struct {
-	decodeFunc RequestDecodeFunc[T]
-	handler    RequestHandlerWithInput[T]
+	decodeFunc         RequestDecodeFunc[T]
+	decodeErrorHandler DecodeErrorHandler
+	contextEnricher    ContextEnricher
+	hidden             func(r *http.Request, decoded any) Responder
+	handler            RequestHandlerWithInput[T]
 }
 
 // HandleWithInput converts a RequestHandlerWithInput to an http.HandlerFunc.
-func HandleWithInput[T any](handler RequestHandlerWithInput[T], opts ...func(*handleWithInput[T])) http.HandlerFunc {
-	h := &handleWithInput[T]{
-		decodeFunc: JSONBodyDecode[T],
-		handler:    handler,
+// The request body is decoded as JSON; use HandleWithDecoder for another decoder.
+func HandleWithInput[T any](handler RequestHandlerWithInput[T], opts ...HandlerOption) http.HandlerFunc {
+	return handleWithDecoder(nil, handler, newHandlerOptions(opts...))
+}
+
+// HandleWithDecoder converts a RequestHandlerWithInput to an http.HandlerFunc that decodes
+// its input with decodeFunc. The decoder is an argument rather than a HandlerOption so that
+// its type is checked against the input of the handler at compile time.
+func HandleWithDecoder[T any](decodeFunc RequestDecodeFunc[T], handler RequestHandlerWithInput[T], opts ...HandlerOption) http.HandlerFunc {
+	return handleWithDecoder(decodeFunc, handler, newHandlerOptions(opts...))
+}
+
+// handleWithDecoder creates the handler of HandleWithDecoder with options that are already
+// built. A nil decodeFunc decodes the input with the decoder set with WithDecodeFunc, or as
+// JSON.
+func handleWithDecoder[T any](decodeFunc RequestDecodeFunc[T], handler RequestHandlerWithInput[T], o handlerOptions) http.HandlerFunc {
+	if decodeFunc == nil {
+		decodeFunc = JSONBodyDecode[T]
+		if o.decodeFunc != nil {
+			fn, ok := o.decodeFunc.(RequestDecodeFunc[T])
+			if !ok {
+				panic(fmt.Sprintf("httphandler: decode func %T does not match handler input %T", o.decodeFunc, decodeFunc))
+			}
+			decodeFunc = fn
+		}
 	}
-	for _, opt := range opts {
-		opt(h)
+
+	h := &handleWithInput[T]{
+		decodeFunc:         decodeFunc,
+		decodeErrorHandler: o.decodeErrorHandler,
+		contextEnricher:    o.contextEnricher,
+		hidden:             o.hidden,
+		handler:            handler,
 	}
+	o.input = reflect.TypeFor[T]()
 
-	return h.ServeHTTP
+	return o.wrap(h.ServeHTTP)
 }
 
 // ServeHTTP implements the http.Handler interface.
 func (h *handleWithInput[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t := timingFrom(r)
+
+	r, mh := withMiddlewareHeaders(r)
+	start := t.start()
 	input, err := h.decodeFunc(r)
+	t.observeDecode(start)
+	w = mh.writer(w)
 	if err != nil {
-		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		res := h.hidden(r, nil)
+		if res == nil {
+			res = decodeErrorResponder(r, err, h.decodeErrorHandler)
+		}
+		t.writeHeader(w)
+		start = t.start()
+		res.Respond(w, r)
+		t.observeEncode(start)
+		return
+	}
+	if res := h.hidden(r, input); res != nil {
+		t.writeHeader(w)
+		res.Respond(w, r)
 		return
 	}
+	if h.contextEnricher != nil {
+		values := stageValues(r)
+		if len(values) == 0 {
+			values = []any{input}
+		}
+		r = r.WithContext(h.contextEnricher(r.Context(), values...))
+	}
 
+	start = t.start()
 	res := h.handler(r, input)
+	t.observeHandle(start)
+	t.writeHeader(w)
+
 	if res == nil {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
+	start = t.start()
 	res.Respond(w, r)
+	t.observeEncode(start)
+}
+
+// RequestHandlerCtxWithInput handles an HTTP request with its context and decoded input and returns a Responder.
+type RequestHandlerCtxWithInput[T any] func(ctx context.Context, r *http.Request, input T) Responder
+
+// HandleCtxWithInput converts a RequestHandlerCtxWithInput to an http.HandlerFunc.
+// It accepts the same options as HandleWithInput.
+func HandleCtxWithInput[T any](handler RequestHandlerCtxWithInput[T], opts ...HandlerOption) http.HandlerFunc {
+	return HandleWithInput(func(r *http.Request, input T) Responder {
+		return handler(r.Context(), r, input)
+	}, opts...)
+}
+
+// WithDecodeFunc sets the decode function of a handler created by HandleWithInput and its
+// variants. The type of the decoded value must match the input of the handler, which is checked
+// when the handler is created. It is ignored by HandleWithDecoder.
+//
+// Deprecated: Use HandleWithDecoder, whose decoder is type-checked at compile time.
+func WithDecodeFunc[T any](decodeFunc RequestDecodeFunc[T]) HandlerOption {
+	return func(o *handlerOptions) {
+		o.decodeFunc = decodeFunc
+	}
+}
+
+// WithDecodeErrorHandler sets the function that renders the response when decoding fails.
+// By default a 400 Bad Request with a plain text body is sent.
+func WithDecodeErrorHandler(fn DecodeErrorHandler) HandlerOption {
+	return func(o *handlerOptions) {
+		o.decodeErrorHandler = fn
+	}
+}
+
+// WithPrecheck sets a check that runs before the request body is read.
+// If check returns a Responder, it is sent and the body is never read. For requests with
+// "Expect: 100-continue" this means the client is refused before it uploads the body, since
+// net/http only sends 100 Continue on the first read. Use it for authentication or size checks
+// on routes that accept large bodies.
+func WithPrecheck(check func(r *http.Request) Responder) HandlerOption {
+	return func(o *handlerOptions) {
+		o.precheck = check
+	}
+}
+
+// WithMaxBodyBytes limits the request body to n bytes. Reading past the limit fails with
+// *http.MaxBytesError, which the default decode error handler renders as 413 Payload Too Large.
+// Use it with WithPrecheck(MaxContentLength(n)) to also refuse bodies declared too large before
+// any of it is read.
+func WithMaxBodyBytes(n int64) HandlerOption {
+	return func(o *handlerOptions) {
+		o.maxBodyBytes = n
+	}
+}
+
+// MaxContentLength returns a check for WithPrecheck that rejects requests whose declared
+// Content-Length is over limit with 413 Payload Too Large.
+func MaxContentLength(limit int64) func(r *http.Request) Responder {
+	return func(r *http.Request) Responder {
+		if r.ContentLength > limit {
+			return PayloadTooLarge(limit)
+		}
+		return nil
+	}
+}
+
+// defaultDecodeErrorHandler responds with 413 Payload Too Large if the body was over its limit,
+// with 415 Unsupported Media Type if its Content-Type was rejected, with 403 Forbidden if the
+// principal lacks required scopes, and with 400 Bad Request otherwise.
+func defaultDecodeErrorHandler(_ *http.Request, err error) Responder {
+	return defaultDecodeResponse(err)
 }
 
-// WithDecodeFunc sets the decode function for the handler.
-func WithDecodeFunc[T any](decodeFunc RequestDecodeFunc[T]) func(*handleWithInput[T]) {
-	return func(h *handleWithInput[T]) {
-		h.decodeFunc = decodeFunc
+// defaultDecodeResponse returns the plain text response for a decoding failure.
+func defaultDecodeResponse(err error) *statusResponder {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return PayloadTooLarge(maxBytesErr.Limit)
 	}
+
+	var mediaTypeErr *MediaTypeError
+	if errors.As(err, &mediaTypeErr) {
+		return UnsupportedMediaType(mediaTypeErr.Supported...)
+	}
+
+	var scopeErr *ScopeError
+	if errors.As(err, &scopeErr) {
+		return InsufficientScope(scopeErr.Missing...)
+	}
+
+	return &statusResponder{
+		statusCode: http.StatusBadRequest,
+		message:    "Invalid request payload",
+	}
+}
+
+// DecodeErrorClass is the status code and headers that the default decode error handler sends
+// for a decoding failure.
+type DecodeErrorClass struct {
+	Status int
+	// Header holds the headers the status requires, e.g. WWW-Authenticate with
+	// error="insufficient_scope" for 403 Forbidden and Accept-Post for 415 Unsupported Media Type.
+	Header http.Header
+}
+
+// ClassifyDecodeError returns the status code and headers that the default decode error handler
+// sends for err, for decode error handlers that render another format: 413 Payload Too Large,
+// 415 Unsupported Media Type, 403 Forbidden or 400 Bad Request.
+func ClassifyDecodeError(err error) DecodeErrorClass {
+	res := defaultDecodeResponse(err)
+	return DecodeErrorClass{Status: res.statusCode, Header: res.header.Clone()}
+}
+
+// DecodeErrorStatus returns the status code of ClassifyDecodeError(err).
+func DecodeErrorStatus(err error) int {
+	return ClassifyDecodeError(err).Status
 }
 
 var ErrJSONDecode = errors.New("fail to decode json")
 
+// jsonUnmarshaler holds the function set with SetJSONUnmarshaler.
+var jsonUnmarshaler atomic.Pointer[func(data []byte, v any) error]
+
+// SetJSONUnmarshaler replaces encoding/json for JSONBodyDecode, e.g. with a faster implementation.
+// The body is then read in full before it is unmarshaled. Passing nil restores encoding/json.
+// JSONBodyStrict always uses encoding/json.
+func SetJSONUnmarshaler(unmarshal func(data []byte, v any) error) {
+	if unmarshal == nil {
+		jsonUnmarshaler.Store(nil)
+		return
+	}
+	jsonUnmarshaler.Store(&unmarshal)
+}
+
 func JSONBodyDecode[T any](r *http.Request) (T, error) {
 	var v T
+	if unmarshal := jsonUnmarshaler.Load(); unmarshal != nil {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return v, fmt.Errorf("%w: %w", ErrJSONDecode, err)
+		}
+		if err := (*unmarshal)(data, &v); err != nil {
+			return v, fmt.Errorf("%w: %w", ErrJSONDecode, err)
+		}
+		return v, nil
+	}
+
 	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
 		return v, fmt.Errorf("%w: %w", ErrJSONDecode, err)
 	}
 
 	return v, nil
 }
+
+// RequestHandlerE handles an HTTP request and returns a Responder or an error.
+type RequestHandlerE func(r *http.Request) (Responder, error)
+
+// RequestHandlerWithInputE handles an HTTP request with decoded input and returns a Responder or an error.
+type RequestHandlerWithInputE[T any] func(r *http.Request, input T) (Responder, error)
+
+// ErrorMapper converts an error returned by a handler into a Responder.
+type ErrorMapper func(r *http.Request, err error) Responder
+
+// HandleE converts a RequestHandlerE to an http.HandlerFunc.
+// A returned error is rendered with the Responder returned by the error mapper, see WithErrorMapper.
+func HandleE(handler RequestHandlerE, opts ...HandlerOption) http.HandlerFunc {
+	o := newHandlerOptions(opts...)
+
+	return o.wrap(handle(func(r *http.Request) Responder {
+		res, err := handler(r)
+		if err != nil {
+			recordRequestError(r, err)
+			return o.errorMapper(r, err)
+		}
+		return res
+	}))
+}
+
+// HandleWithInputE converts a RequestHandlerWithInputE to an http.HandlerFunc.
+// It accepts the same options as HandleWithInput, and renders a returned error like HandleE.
+func HandleWithInputE[T any](handler RequestHandlerWithInputE[T], opts ...HandlerOption) http.HandlerFunc {
+	return handleWithDecoderE(nil, handler, newHandlerOptions(opts...))
+}
+
+// HandleWithDecoderE is like HandleWithInputE with the input decoded by decodeFunc, see
+// HandleWithDecoder.
+func HandleWithDecoderE[T any](decodeFunc RequestDecodeFunc[T], handler RequestHandlerWithInputE[T], opts ...HandlerOption) http.HandlerFunc {
+	return handleWithDecoderE(decodeFunc, handler, newHandlerOptions(opts...))
+}
+
+// handleWithDecoderE creates the handler of HandleWithDecoderE with options that are already
+// built, see handleWithDecoder.
+func handleWithDecoderE[T any](decodeFunc RequestDecodeFunc[T], handler RequestHandlerWithInputE[T], o handlerOptions) http.HandlerFunc {
+	return handleWithDecoder(decodeFunc, func(r *http.Request, input T) Responder {
+		res, err := handler(r, input)
+		if err != nil {
+			recordRequestError(r, err)
+			return o.errorMapper(r, err)
+		}
+		return res
+	}, o)
+}
+
+// WithErrorMapper sets the function that converts errors returned by handlers into Responders.
+// By default a 500 Internal Server Error is sent, see SetInternalServerErrorWriter.
+func WithErrorMapper(mapper ErrorMapper) HandlerOption {
+	return func(o *handlerOptions) {
+		o.errorMapper = mapper
+	}
+}
+
+// defaultErrorMapper responds with 500 Internal Server Error.
+func defaultErrorMapper(_ *http.Request, err error) Responder {
+	return ResponderFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeInternalServerError(w, err)
+	})
+}
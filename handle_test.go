@@ -1,9 +1,16 @@
 package httphandler_test
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/alvinchoong/go-httphandler"
@@ -138,7 +145,7 @@ func TestHandleWithInput(t *testing.T) {
 			r := httptest.NewRequest(http.MethodPost, "/test", nil)
 			w := httptest.NewRecorder()
 
-			given := httphandler.HandleWithInput(tc.handler, httphandler.WithDecodeFunc(tc.decode))
+			given := httphandler.HandleWithDecoder(tc.decode, tc.handler)
 
 			// When:
 			given.ServeHTTP(w, r)
@@ -154,3 +161,599 @@ func TestHandleWithInput(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleWithInput_DecodeErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	r := httptest.NewRequest(http.MethodPost, "/test", nil)
+	w := httptest.NewRecorder()
+
+	given := httphandler.HandleWithDecoder(
+		func(r *http.Request) (string, error) {
+			return "", errors.New("decoding failed")
+		},
+		func(r *http.Request, input string) httphandler.Responder {
+			t.Errorf("handler: should not be called on decoding failure")
+			return nil
+		},
+		httphandler.WithDecodeErrorHandler(func(r *http.Request, err error) httphandler.Responder {
+			return &mockResponder{
+				StatusCode: http.StatusUnprocessableEntity,
+				Body:       err.Error(),
+			}
+		}),
+	)
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status code: want %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+
+	if w.Body.String() != "decoding failed" {
+		t.Errorf("body: want '%s', got '%s'", "decoding failed", w.Body.String())
+	}
+}
+
+func TestHandleWithInput_DecodeFunc(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	w := httptest.NewRecorder()
+
+	given := httphandler.HandleWithInput(
+		func(r *http.Request, input int) httphandler.Responder {
+			return &mockResponder{StatusCode: http.StatusOK, Body: strconv.Itoa(input)}
+		},
+		httphandler.WithDecodeFunc(func(r *http.Request) (int, error) { return 42, nil }),
+	)
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Body.String() != "42" {
+		t.Errorf("body: want '%s', got '%s'", "42", w.Body.String())
+	}
+}
+
+func TestHandleWithInput_DecodeFuncMismatch(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("HandleWithInput: want panic for mismatched decode func")
+		}
+	}()
+
+	httphandler.HandleWithInput(
+		func(r *http.Request, input string) httphandler.Responder { return nil },
+		httphandler.WithDecodeFunc(func(r *http.Request) (int, error) { return 0, nil }),
+	)
+}
+
+func TestHandleCtx(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ctxKey("user"), "alice"))
+
+	given := httphandler.HandleCtx(func(ctx context.Context, r *http.Request) httphandler.Responder {
+		if ctx != r.Context() {
+			t.Errorf("context: want request context")
+		}
+		return &mockResponder{StatusCode: http.StatusOK, Body: ctx.Value(ctxKey("user")).(string)}
+	})
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w.Body.String() != "alice" {
+		t.Errorf("body: want '%s', got '%s'", "alice", w.Body.String())
+	}
+}
+
+func TestHandleCtxWithInput(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"bob"}`))
+	r = r.WithContext(context.WithValue(r.Context(), ctxKey("user"), "alice"))
+
+	given := httphandler.HandleCtxWithInput(func(ctx context.Context, r *http.Request, input map[string]string) httphandler.Responder {
+		return &mockResponder{
+			StatusCode: http.StatusOK,
+			Body:       ctx.Value(ctxKey("user")).(string) + ":" + input["name"],
+		}
+	})
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w.Body.String() != "alice:bob" {
+		t.Errorf("body: want '%s', got '%s'", "alice:bob", w.Body.String())
+	}
+}
+
+func TestHandleE(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("not found")
+	mapper := func(r *http.Request, err error) httphandler.Responder {
+		if errors.Is(err, errNotFound) {
+			return &mockResponder{StatusCode: http.StatusNotFound, Body: "Not Found"}
+		}
+		return &mockResponder{StatusCode: http.StatusBadGateway, Body: "Bad Gateway"}
+	}
+
+	testCases := []struct {
+		desc      string
+		given     httphandler.RequestHandlerE
+		givenOpts []httphandler.HandlerOption
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			desc: "handle success",
+			given: func(r *http.Request) (httphandler.Responder, error) {
+				return &mockResponder{StatusCode: http.StatusOK, Body: "Success"}, nil
+			},
+			wantCode: http.StatusOK,
+			wantBody: "Success",
+		},
+		{
+			desc: "handle nil",
+			given: func(r *http.Request) (httphandler.Responder, error) {
+				return nil, nil
+			},
+			wantCode: http.StatusNoContent,
+			wantBody: "",
+		},
+		{
+			desc: "handle error | default mapper",
+			given: func(r *http.Request) (httphandler.Responder, error) {
+				return nil, errNotFound
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: "Internal Server Error\n",
+		},
+		{
+			desc: "handle error | custom mapper",
+			given: func(r *http.Request) (httphandler.Responder, error) {
+				return nil, errNotFound
+			},
+			givenOpts: []httphandler.HandlerOption{httphandler.WithErrorMapper(mapper)},
+			wantCode:  http.StatusNotFound,
+			wantBody:  "Not Found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			httphandler.HandleE(tc.given, tc.givenOpts...).ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if w.Body.String() != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleWithInputE(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"bob"}`))
+
+	given := httphandler.HandleWithInputE(
+		func(r *http.Request, input map[string]string) (httphandler.Responder, error) {
+			return nil, errors.New("user " + input["name"] + " exists")
+		},
+		httphandler.WithErrorMapper(func(r *http.Request, err error) httphandler.Responder {
+			return &mockResponder{StatusCode: http.StatusConflict, Body: err.Error()}
+		}),
+	)
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusConflict {
+		t.Errorf("status code: want %d, got %d", http.StatusConflict, w.Code)
+	}
+
+	if w.Body.String() != "user bob exists" {
+		t.Errorf("body: want '%s', got '%s'", "user bob exists", w.Body.String())
+	}
+}
+
+func TestHandleWithDecoderE_OptionsAppliedOnce(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	var applied int
+	counter := reflect.MakeFunc(reflect.TypeFor[httphandler.HandlerOption](), func([]reflect.Value) []reflect.Value {
+		applied++
+		return nil
+	}).Interface().(httphandler.HandlerOption)
+
+	// When:
+	httphandler.HandleWithDecoderE(
+		func(r *http.Request) (string, error) { return "", nil },
+		func(r *http.Request, input string) (httphandler.Responder, error) { return nil, nil },
+		counter,
+	)
+
+	// Then:
+	if applied != 1 {
+		t.Errorf("option applied: want %d times, got %d", 1, applied)
+	}
+}
+
+// readTracker records whether the request body has been read.
+type readTracker struct {
+	io.Reader
+	read bool
+}
+
+func (rt *readTracker) Read(p []byte) (int, error) {
+	rt.read = true
+	return rt.Reader.Read(p)
+}
+
+func TestHandleWithInput_Precheck(t *testing.T) {
+	t.Parallel()
+
+	requireAuth := func(r *http.Request) httphandler.Responder {
+		if r.Header.Get("Authorization") == "" {
+			return &mockResponder{StatusCode: http.StatusUnauthorized, Body: "Unauthorized"}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		desc         string
+		givenCheck   func(r *http.Request) httphandler.Responder
+		givenAuth    string
+		givenBody    string
+		wantCode     int
+		wantBodyRead bool
+		wantRespBody string
+	}{
+		{
+			desc:         "auth | accepted",
+			givenCheck:   requireAuth,
+			givenAuth:    "Bearer token",
+			givenBody:    `{"name":"alice"}`,
+			wantCode:     http.StatusOK,
+			wantBodyRead: true,
+			wantRespBody: "alice",
+		},
+		{
+			desc:         "auth | rejected",
+			givenCheck:   requireAuth,
+			givenBody:    `{"name":"alice"}`,
+			wantCode:     http.StatusUnauthorized,
+			wantBodyRead: false,
+			wantRespBody: "Unauthorized",
+		},
+		{
+			desc:         "content length | rejected",
+			givenCheck:   httphandler.MaxContentLength(4),
+			givenBody:    `{"name":"alice"}`,
+			wantCode:     http.StatusRequestEntityTooLarge,
+			wantBodyRead: false,
+			wantRespBody: "Request body too large: limit is 4 bytes\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			body := &readTracker{Reader: strings.NewReader(tc.givenBody)}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", body)
+			r.ContentLength = int64(len(tc.givenBody))
+			r.Header.Set("Expect", "100-continue")
+			if tc.givenAuth != "" {
+				r.Header.Set("Authorization", tc.givenAuth)
+			}
+
+			given := httphandler.HandleWithInput(
+				func(r *http.Request, input map[string]string) httphandler.Responder {
+					return &mockResponder{StatusCode: http.StatusOK, Body: input["name"]}
+				},
+				httphandler.WithPrecheck(tc.givenCheck),
+			)
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if body.read != tc.wantBodyRead {
+				t.Errorf("body read: want %t, got %t", tc.wantBodyRead, body.read)
+			}
+
+			if w.Body.String() != tc.wantRespBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantRespBody, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestSetDefaultHandlerOptions is not parallel because it changes the package-level defaults.
+func TestSetDefaultHandlerOptions(t *testing.T) {
+	reject := func(code int) func(r *http.Request) httphandler.Responder {
+		return func(r *http.Request) httphandler.Responder {
+			return &mockResponder{StatusCode: code}
+		}
+	}
+	ok := func(r *http.Request) httphandler.Responder {
+		return &mockResponder{StatusCode: http.StatusOK}
+	}
+	okWithInput := func(r *http.Request, input map[string]string) httphandler.Responder {
+		return &mockResponder{StatusCode: http.StatusOK}
+	}
+	decodeError := func(code int) httphandler.DecodeErrorHandler {
+		return func(r *http.Request, err error) httphandler.Responder {
+			return &mockResponder{StatusCode: code}
+		}
+	}
+
+	testCases := []struct {
+		desc          string
+		givenDefaults []httphandler.HandlerOption
+		givenHandler  func(opts ...httphandler.HandlerOption) http.HandlerFunc
+		givenOpts     []httphandler.HandlerOption
+		wantCode      int
+	}{
+		{
+			desc:          "handle | default applied",
+			givenDefaults: []httphandler.HandlerOption{httphandler.WithPrecheck(reject(http.StatusUnauthorized))},
+			givenHandler: func(opts ...httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.Handle(ok, opts...)
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			desc:          "handle | handler option takes precedence",
+			givenDefaults: []httphandler.HandlerOption{httphandler.WithPrecheck(reject(http.StatusUnauthorized))},
+			givenHandler: func(opts ...httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.Handle(ok, opts...)
+			},
+			givenOpts: []httphandler.HandlerOption{httphandler.WithPrecheck(reject(http.StatusForbidden))},
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			desc:          "handle with input | default applied",
+			givenDefaults: []httphandler.HandlerOption{httphandler.WithDecodeErrorHandler(decodeError(http.StatusUnprocessableEntity))},
+			givenHandler: func(opts ...httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.HandleWithInput(okWithInput, opts...)
+			},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			desc:          "handle with input | handler option takes precedence",
+			givenDefaults: []httphandler.HandlerOption{httphandler.WithDecodeErrorHandler(decodeError(http.StatusUnprocessableEntity))},
+			givenHandler: func(opts ...httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.HandleWithInput(okWithInput, opts...)
+			},
+			givenOpts: []httphandler.HandlerOption{httphandler.WithDecodeErrorHandler(decodeError(http.StatusTeapot))},
+			wantCode:  http.StatusTeapot,
+		},
+		{
+			desc: "handle e | default panic handler applied",
+			givenDefaults: []httphandler.HandlerOption{httphandler.WithPanicHandler(func(ctx context.Context, recovered any, stack []byte) httphandler.Responder {
+				return &mockResponder{StatusCode: http.StatusServiceUnavailable}
+			})},
+			givenHandler: func(opts ...httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) { panic("boom") }, opts...)
+			},
+			wantCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			httphandler.SetDefaultHandlerOptions(tc.givenDefaults...)
+			defer httphandler.SetDefaultHandlerOptions()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not json"))
+
+			// When:
+			tc.givenHandler(tc.givenOpts...).ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+// TestSetDefaultHandlerOptions_ExistingHandlers is not parallel because it changes the package-level defaults.
+func TestSetDefaultHandlerOptions_ExistingHandlers(t *testing.T) {
+	// Given:
+	given := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return &mockResponder{StatusCode: http.StatusOK}
+	})
+	httphandler.SetDefaultHandlerOptions(httphandler.WithPrecheck(httphandler.MaxContentLength(0)))
+	defer httphandler.SetDefaultHandlerOptions()
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestHandleWithInput_MaxBodyBytes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc      string
+		givenBody string
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			desc:      "within limit",
+			givenBody: `{"name":"al"}`,
+			wantCode:  http.StatusOK,
+			wantBody:  "al",
+		},
+		{
+			desc:      "over limit",
+			givenBody: `{"name":"alice"}`,
+			wantCode:  http.StatusRequestEntityTooLarge,
+			wantBody:  "Request body too large: limit is 13 bytes\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.givenBody))
+
+			given := httphandler.HandleWithInput(
+				func(r *http.Request, input map[string]string) httphandler.Responder {
+					return &mockResponder{StatusCode: http.StatusOK, Body: input["name"]}
+				},
+				httphandler.WithMaxBodyBytes(13),
+			)
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+// TestSetJSONUnmarshaler is not parallel because it changes the package-level unmarshaler.
+func TestSetJSONUnmarshaler(t *testing.T) {
+	// Given:
+	var gotData string
+	httphandler.SetJSONUnmarshaler(func(data []byte, v any) error {
+		gotData = string(data)
+		return json.Unmarshal(data, v)
+	})
+	defer httphandler.SetJSONUnmarshaler(nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"alice"}`))
+
+	// When:
+	got, err := httphandler.JSONBodyDecode[map[string]string](r)
+
+	// Then:
+	if err != nil {
+		t.Errorf("error: want nil, got %v", err)
+	}
+
+	if got["name"] != "alice" {
+		t.Errorf("name: want '%s', got '%s'", "alice", got["name"])
+	}
+
+	if gotData != `{"name":"alice"}` {
+		t.Errorf("data: want '%s', got '%s'", `{"name":"alice"}`, gotData)
+	}
+}
+
+func TestClassifyDecodeError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc       string
+		given      error
+		want       int
+		wantHeader http.Header
+	}{
+		{desc: "body too large", given: fmt.Errorf("decode: %w", &http.MaxBytesError{Limit: 1}), want: http.StatusRequestEntityTooLarge},
+		{
+			desc:       "media type",
+			given:      &httphandler.MediaTypeError{ContentType: "text/plain", Supported: []string{"application/json"}},
+			want:       http.StatusUnsupportedMediaType,
+			wantHeader: http.Header{"Accept-Post": {"application/json"}},
+		},
+		{
+			desc:       "scope",
+			given:      &httphandler.ScopeError{Missing: []string{"admin"}},
+			want:       http.StatusForbidden,
+			wantHeader: http.Header{"Www-Authenticate": {`Bearer error="insufficient_scope", scope="admin"`}},
+		},
+		{desc: "other", given: httphandler.ErrJSONDecode, want: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got := httphandler.ClassifyDecodeError(tc.given)
+
+			// Then:
+			if got.Status != tc.want {
+				t.Errorf("status code: want %d, got %d", tc.want, got.Status)
+			}
+			if !reflect.DeepEqual(got.Header, tc.wantHeader) {
+				t.Errorf("header: want %v, got %v", tc.wantHeader, got.Header)
+			}
+			if status := httphandler.DecodeErrorStatus(tc.given); status != tc.want {
+				t.Errorf("DecodeErrorStatus: want %d, got %d", tc.want, status)
+			}
+		})
+	}
+}
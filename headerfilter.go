@@ -0,0 +1,82 @@
+package httphandler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alvinchoong/go-httphandler/internal/deferred"
+)
+
+// HeaderFilter strips and renames response headers before they are sent, e.g. so that internal
+// and debugging headers never leak to the public edge. Names are case-insensitive, and a name
+// ending with "*" matches every header with that prefix, e.g. "X-Internal-*".
+type HeaderFilter struct {
+	// Allow, if not empty, lists the only headers that are sent.
+	Allow []string
+	// Deny lists headers that are never sent.
+	Deny []string
+	// Rename maps the names of headers to the names they are sent with. It applies to the
+	// headers that are allowed and not denied.
+	Rename map[string]string
+}
+
+// WithHeaderFilter applies filter to the headers of every response of the handler, including
+// the headers set by other options and by the default handlers. Use SetDefaultHandlerOptions to
+// apply it to every handler; a filter set on a handler replaces the default one.
+func WithHeaderFilter(filter HeaderFilter) HandlerOption {
+	return func(o *handlerOptions) {
+		o.headerFilter = &filter
+	}
+}
+
+// headerFilterHandler wraps h so that filter is applied to its response headers, also when h
+// sets headers without writing anything.
+func headerFilterHandler(h http.HandlerFunc, filter *HeaderFilter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mw := deferred.Mapper(w, filter.apply)
+		h(mw, r)
+		deferred.Commit(mw)
+	}
+}
+
+// apply returns the headers to send instead of header.
+func (f *HeaderFilter) apply(status int, header http.Header) (int, http.Header) {
+	filtered := make(http.Header, len(header))
+	for key, values := range header {
+		if len(f.Allow) > 0 && !matchHeader(f.Allow, key) {
+			continue
+		}
+		if matchHeader(f.Deny, key) {
+			continue
+		}
+		if name, ok := f.rename(key); ok {
+			key = name
+		}
+		filtered[key] = append(filtered[key], values...)
+	}
+	return status, filtered
+}
+
+// rename returns the name that key is sent with, if it is renamed.
+func (f *HeaderFilter) rename(key string) (string, bool) {
+	for from, to := range f.Rename {
+		if strings.EqualFold(from, key) {
+			return http.CanonicalHeaderKey(to), true
+		}
+	}
+	return "", false
+}
+
+// matchHeader reports whether key matches one of the names, see HeaderFilter.
+func matchHeader(names []string, key string) bool {
+	for _, name := range names {
+		if prefix, ok := strings.CutSuffix(name, "*"); ok {
+			if len(key) >= len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+				return true
+			}
+		} else if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestWithHeaderFilter(t *testing.T) {
+	t.Parallel()
+
+	handler := func(r *http.Request) httphandler.Responder {
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("X-Internal-Shard", "7")
+			w.Header().Set("X-Debug", "on")
+			w.Header().Set("X-Request-Id", "abc")
+			w.Write([]byte("ok"))
+		})
+	}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.HeaderFilter
+		wantHeaders map[string]string
+	}{
+		{
+			desc:  "deny prefix and name",
+			given: httphandler.HeaderFilter{Deny: []string{"x-internal-*", "X-Debug"}},
+			wantHeaders: map[string]string{
+				"Content-Type":     "text/plain",
+				"X-Internal-Shard": "",
+				"X-Debug":          "",
+				"X-Request-Id":     "abc",
+			},
+		},
+		{
+			desc:  "allow list",
+			given: httphandler.HeaderFilter{Allow: []string{"Content-Type", "X-Request-*"}},
+			wantHeaders: map[string]string{
+				"Content-Type":     "text/plain",
+				"X-Internal-Shard": "",
+				"X-Debug":          "",
+				"X-Request-Id":     "abc",
+			},
+		},
+		{
+			desc: "rename",
+			given: httphandler.HeaderFilter{
+				Deny:   []string{"X-Internal-*"},
+				Rename: map[string]string{"x-request-id": "X-Correlation-ID"},
+			},
+			wantHeaders: map[string]string{
+				"X-Internal-Shard": "",
+				"X-Debug":          "on",
+				"X-Request-Id":     "",
+				"X-Correlation-Id": "abc",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			h := httphandler.Handle(handler, httphandler.WithHeaderFilter(tc.given))
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			h(w, r)
+
+			// Then:
+			if w.Code != http.StatusOK {
+				t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+			}
+			for k, v := range tc.wantHeaders {
+				if got := w.Header().Get(k); got != v {
+					t.Errorf("header %q: want %q, got %q", k, v, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithHeaderFilter_NoWrite(t *testing.T) {
+	t.Parallel()
+
+	// Given: a handler that sets headers without writing anything
+	h := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Internal-Shard", "7")
+			w.Header().Set("X-Request-Id", "abc")
+		})
+	}, httphandler.WithHeaderFilter(httphandler.HeaderFilter{Deny: []string{"X-Internal-*"}}))
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// When:
+	h(w, r)
+
+	// Then: the implicit 200 OK is sent with the filtered headers
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Result().Header.Get("X-Internal-Shard"); got != "" {
+		t.Errorf("header %q: want %q, got %q", "X-Internal-Shard", "", got)
+	}
+	if got := w.Result().Header.Get("X-Request-Id"); got != "abc" {
+		t.Errorf("header %q: want %q, got %q", "X-Request-Id", "abc", got)
+	}
+}
+
+// TestWithHeaderFilter_Default is not parallel because it changes the package-level defaults.
+func TestWithHeaderFilter_Default(t *testing.T) {
+	// Given: a global filter, and a route with its own filter
+	httphandler.SetDefaultHandlerOptions(httphandler.WithHeaderFilter(httphandler.HeaderFilter{Deny: []string{"X-Internal-*"}}))
+	defer httphandler.SetDefaultHandlerOptions()
+
+	handler := func(r *http.Request) httphandler.Responder {
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Internal-Shard", "7")
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	global := httphandler.Handle(handler)
+	route := httphandler.Handle(handler, httphandler.WithHeaderFilter(httphandler.HeaderFilter{}))
+
+	// When:
+	w1 := httptest.NewRecorder()
+	global(w1, httptest.NewRequest(http.MethodGet, "/", nil))
+	w2 := httptest.NewRecorder()
+	route(w2, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Then: the route's filter replaces the global one
+	if got := w1.Header().Get("X-Internal-Shard"); got != "" {
+		t.Errorf("global header: want %q, got %q", "", got)
+	}
+	if got := w2.Header().Get("X-Internal-Shard"); got != "7" {
+		t.Errorf("route header: want %q, got %q", "7", got)
+	}
+}
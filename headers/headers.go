@@ -0,0 +1,111 @@
+// Package headers provides typed helpers for common response headers.
+// Each helper returns a canonical header name and a validated value, so it can be passed
+// directly to any WithHeader method:
+//
+//	jsonresp.Success(user).WithHeader(headers.Location(u))
+//
+// The helpers panic on invalid input, which is almost always a programming error.
+package headers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentLanguage returns the Content-Language header for one or more BCP 47 language tags.
+func ContentLanguage(tags ...string) (string, string) {
+	if len(tags) == 0 {
+		panic("headers: ContentLanguage requires at least one tag")
+	}
+	for _, tag := range tags {
+		if !isLanguageTag(tag) {
+			panic(fmt.Sprintf("headers: invalid language tag %q", tag))
+		}
+	}
+
+	return "Content-Language", strings.Join(tags, ", ")
+}
+
+// Location returns the Location header for u.
+func Location(u *url.URL) (string, string) {
+	if u == nil {
+		panic("headers: Location requires a URL")
+	}
+
+	return "Location", u.String()
+}
+
+// Link returns a Link header (RFC 8288) pointing to href with the relation type rel,
+// e.g. Link("next", "/users?cursor=abc").
+func Link(rel, href string) (string, string) {
+	if !isToken(rel) {
+		panic(fmt.Sprintf("headers: invalid link relation %q", rel))
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		panic(fmt.Sprintf("headers: invalid link target %q: %v", href, err))
+	}
+
+	return "Link", fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
+
+// RetryAfter returns the Retry-After header for a delay, rounded up to whole seconds.
+func RetryAfter(d time.Duration) (string, string) {
+	if d < 0 {
+		panic(fmt.Sprintf("headers: negative Retry-After %s", d))
+	}
+	seconds := (d + time.Second - 1) / time.Second
+
+	return "Retry-After", strconv.FormatInt(int64(seconds), 10)
+}
+
+// CacheControl returns the Cache-Control header for the directives, e.g.
+// CacheControl("private", "max-age=60").
+func CacheControl(directives ...string) (string, string) {
+	if len(directives) == 0 {
+		panic("headers: CacheControl requires at least one directive")
+	}
+	for _, directive := range directives {
+		name, _, _ := strings.Cut(directive, "=")
+		if !isToken(name) {
+			panic(fmt.Sprintf("headers: invalid cache directive %q", directive))
+		}
+	}
+
+	return "Cache-Control", strings.Join(directives, ", ")
+}
+
+// isLanguageTag reports whether s looks like a BCP 47 language tag:
+// hyphen-separated alphanumeric subtags of 1 to 8 characters, starting with a letter.
+func isLanguageTag(s string) bool {
+	subtags := strings.Split(s, "-")
+	for i, subtag := range subtags {
+		if len(subtag) == 0 || len(subtag) > 8 {
+			return false
+		}
+		for _, c := range subtag {
+			isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+			isDigit := c >= '0' && c <= '9'
+			if !isLetter && !(isDigit && i > 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isToken reports whether s is a non-empty HTTP token (RFC 9110).
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c <= ' ' || c >= 0x7f || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+	return true
+}
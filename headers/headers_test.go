@@ -0,0 +1,89 @@
+package headers_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler/headers"
+)
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc      string
+		given     func() (string, string)
+		wantKey   string
+		wantValue string
+		wantPanic bool
+	}{
+		{
+			desc:      "content language",
+			given:     func() (string, string) { return headers.ContentLanguage("en-US", "zh-Hant") },
+			wantKey:   "Content-Language",
+			wantValue: "en-US, zh-Hant",
+		},
+		{
+			desc:      "content language | invalid",
+			given:     func() (string, string) { return headers.ContentLanguage("en_US") },
+			wantPanic: true,
+		},
+		{
+			desc: "location",
+			given: func() (string, string) {
+				return headers.Location(&url.URL{Path: "/users/1", RawQuery: "tab=profile"})
+			},
+			wantKey:   "Location",
+			wantValue: "/users/1?tab=profile",
+		},
+		{
+			desc:      "link",
+			given:     func() (string, string) { return headers.Link("next", "/users?cursor=abc") },
+			wantKey:   "Link",
+			wantValue: `</users?cursor=abc>; rel="next"`,
+		},
+		{
+			desc:      "link | invalid relation",
+			given:     func() (string, string) { return headers.Link("next page", "/users") },
+			wantPanic: true,
+		},
+		{
+			desc:      "retry after",
+			given:     func() (string, string) { return headers.RetryAfter(1500 * time.Millisecond) },
+			wantKey:   "Retry-After",
+			wantValue: "2",
+		},
+		{
+			desc:      "cache control",
+			given:     func() (string, string) { return headers.CacheControl("private", "max-age=60") },
+			wantKey:   "Cache-Control",
+			wantValue: "private, max-age=60",
+		},
+		{
+			desc:      "cache control | invalid",
+			given:     func() (string, string) { return headers.CacheControl("max age=60") },
+			wantPanic: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if gotPanic := recover() != nil; gotPanic != tc.wantPanic {
+					t.Errorf("panic: want %t, got %t", tc.wantPanic, gotPanic)
+				}
+			}()
+
+			// When:
+			gotKey, gotValue := tc.given()
+
+			// Then:
+			if gotKey != tc.wantKey || gotValue != tc.wantValue {
+				t.Errorf("header: want %s: %s, got %s: %s", tc.wantKey, tc.wantValue, gotKey, gotValue)
+			}
+		})
+	}
+}
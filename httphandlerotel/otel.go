@@ -0,0 +1,97 @@
+// Package httphandlerotel traces the handlers of this module with OpenTelemetry: a span per
+// request, and a child span per stage of a combined decoder, with decode errors recorded as
+// span events. It is a separate module so that the OpenTelemetry API stays an optional
+// dependency.
+//
+//	tracer := otel.Tracer("orders")
+//	h := httphandler.HandleWithDecoder(httphandler.Combine2(decodeTenant, decodeUser), createOrder,
+//		httphandler.WithStageNames("tenant", "user"),
+//		httphandlerotel.WithStageSpans(tracer),
+//	)
+//	mux.Handle("POST /orders", httphandlerotel.Handle(h, tracer, "POST /orders"))
+package httphandlerotel
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/alvinchoong/go-httphandler"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Handle wraps h so that every request is traced with a server span named spanName, or the
+// request method if spanName is empty. The span continues the trace of the caller, extracted
+// from the request headers, e.g. traceparent, with the global propagator set with
+// otel.SetTextMapPropagator. It records the method, the path and the status code of the
+// response, and the error that caused it, if known, e.g. a decoder or handler error.
+// Responses with a 5xx status code set the span status to Error.
+func Handle(h http.Handler, tracer trace.Tracer, spanName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := spanName
+		if name == "" {
+			name = r.Method
+		}
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		rec, r := httphandler.RecordResponse(w, r.WithContext(ctx))
+		h.ServeHTTP(rec, r)
+
+		status := rec.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if err := rec.Err(); err != nil {
+			span.RecordError(err)
+		}
+		if status >= 500 {
+			span.SetStatus(codes.Error, strconv.Itoa(status))
+		}
+	})
+}
+
+// WithStageSpans creates a child span of the request span around each stage of the combined
+// decoder of the handler, named after the stage, see httphandler.WithStageNames, so that the
+// spans created by the decoder of a stage are children of its span. A failed stage records its
+// error on its span, and as a "decode error" event on the request span.
+// It sets the stage tracer of the handler, replacing any other.
+func WithStageSpans(tracer trace.Tracer) httphandler.HandlerOption {
+	return httphandler.WithStageTracer(StageTracer(tracer))
+}
+
+// StageTracer returns an httphandler.StageTracer that traces stages as WithStageSpans does,
+// e.g. to combine it with another tracer.
+func StageTracer(tracer trace.Tracer) httphandler.StageTracer {
+	return func(ctx context.Context, stage int, name string) (context.Context, func(err error)) {
+		spanName := name
+		if spanName == "" {
+			spanName = "stage " + strconv.Itoa(stage)
+		}
+		attrs := []attribute.KeyValue{
+			attribute.Int("httphandler.stage", stage),
+			attribute.String("httphandler.stage.name", name),
+		}
+
+		stageCtx, span := tracer.Start(ctx, spanName, trace.WithAttributes(attrs...))
+		return stageCtx, func(err error) {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				trace.SpanFromContext(ctx).AddEvent("decode error",
+					trace.WithAttributes(append(attrs, attribute.String("exception.message", err.Error()))...),
+				)
+			}
+			span.End()
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package httphandlerotel_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/httphandlerotel"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandle(t *testing.T) {
+	t.Parallel()
+
+	errUser := errors.New("unknown user")
+
+	decodeTenant := func(r *http.Request) (string, error) {
+		return r.Header.Get("X-Tenant"), nil
+	}
+	decodeUser := func(r *http.Request) (string, error) {
+		if r.Header.Get("X-User") == "" {
+			return "", errUser
+		}
+		return r.Header.Get("X-User"), nil
+	}
+
+	testCases := []struct {
+		desc          string
+		givenUser     string
+		wantCode      int
+		wantStatus    codes.Code
+		wantStages    []string
+		wantFailed    string
+		wantEvents    []string
+		wantSpanError bool
+	}{
+		{
+			desc:       "success",
+			givenUser:  "alice",
+			wantCode:   http.StatusNoContent,
+			wantStatus: codes.Unset,
+			wantStages: []string{"tenant", "user"},
+		},
+		{
+			desc:          "decode error",
+			wantCode:      http.StatusBadRequest,
+			wantStatus:    codes.Unset,
+			wantStages:    []string{"tenant", "user"},
+			wantFailed:    "user",
+			wantEvents:    []string{"decode error", "exception"},
+			wantSpanError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			spans := tracetest.NewSpanRecorder()
+			tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+			h := httphandler.HandleWithDecoder(httphandler.Combine2(decodeTenant, decodeUser), func(r *http.Request, input httphandler.Tuple2[string, string]) httphandler.Responder {
+				return nil
+			},
+				httphandler.WithStageNames("tenant", "user"),
+				httphandlerotel.WithStageSpans(tracer),
+			)
+			r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			r.Header.Set("X-Tenant", "acme")
+			r.Header.Set("X-User", tc.givenUser)
+			w := httptest.NewRecorder()
+
+			// When:
+			httphandlerotel.Handle(h, tracer, "POST /orders").ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			ended := spans.Ended()
+			if len(ended) != len(tc.wantStages)+1 {
+				t.Fatalf("spans: want %d, got %d", len(tc.wantStages)+1, len(ended))
+			}
+			root := ended[len(ended)-1]
+			if root.Name() != "POST /orders" {
+				t.Errorf("request span: want %q, got %q", "POST /orders", root.Name())
+			}
+			if root.Status().Code != tc.wantStatus {
+				t.Errorf("request span status: want %v, got %v", tc.wantStatus, root.Status().Code)
+			}
+			var events []string
+			for _, e := range root.Events() {
+				events = append(events, e.Name)
+			}
+			if len(events) != len(tc.wantEvents) {
+				t.Errorf("request span events: want %v, got %v", tc.wantEvents, events)
+			}
+			for i, stage := range ended[:len(ended)-1] {
+				if stage.Name() != tc.wantStages[i] {
+					t.Errorf("stage span %d: want %q, got %q", i, tc.wantStages[i], stage.Name())
+				}
+				if stage.Parent().SpanID() != root.SpanContext().SpanID() {
+					t.Errorf("stage span %q: want child of the request span", stage.Name())
+				}
+				if failed := stage.Status().Code == codes.Error; failed != (stage.Name() == tc.wantFailed) {
+					t.Errorf("stage span %q: want failed %v, got %v", stage.Name(), !failed, failed)
+				}
+				if stage.EndTime().Before(stage.StartTime()) {
+					t.Errorf("stage span %q: ends before it starts", stage.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestHandle_ServerError(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	spans := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+	h := httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+		return nil, errors.New("database down")
+	})
+
+	// When:
+	httphandlerotel.Handle(h, tracer, "").ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	// Then:
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("spans: want 1, got %d", len(ended))
+	}
+	if ended[0].Name() != http.MethodGet {
+		t.Errorf("span name: want %q, got %q", http.MethodGet, ended[0].Name())
+	}
+	if ended[0].Status().Code != codes.Error {
+		t.Errorf("span status: want %v, got %v", codes.Error, ended[0].Status().Code)
+	}
+	if len(ended[0].Events()) != 1 || ended[0].Events()[0].Name != "exception" {
+		t.Errorf("span events: want the recorded error, got %v", ended[0].Events())
+	}
+}
+
+// TestHandle_Propagation is not parallel because it changes the global propagator.
+func TestHandle_Propagation(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	// Given: a request from a caller in trace 4bf92f3577b34da6a3ce929d0e0e4736
+	spans := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+	h := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return nil
+	})
+	r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	r.Header.Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	// When:
+	httphandlerotel.Handle(h, tracer, "").ServeHTTP(httptest.NewRecorder(), r)
+
+	// Then: the request span continues the caller's trace
+	ended := spans.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("spans: want 1, got %d", len(ended))
+	}
+	if got := ended[0].SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID: want %s, got %s", "4bf92f3577b34da6a3ce929d0e0e4736", got)
+	}
+	if got := ended[0].Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID: want %s, got %s", "00f067aa0ba902b7", got)
+	}
+}
+
+// fixedClock is a Clock that always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// TestWithStageSpans_Clock is not parallel because it changes the package-level clock.
+func TestWithStageSpans_Clock(t *testing.T) {
+	httphandler.SetClock(fixedClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	defer httphandler.SetClock(nil)
+
+	// Given: a stage whose decoder creates its own span
+	spans := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("test")
+	decodeUser := func(r *http.Request) (string, error) {
+		_, span := tracer.Start(r.Context(), "lookup user")
+		defer span.End()
+		return "alice", nil
+	}
+	decodeTenant := func(r *http.Request) (string, error) {
+		return "acme", nil
+	}
+	h := httphandler.HandleWithDecoder(httphandler.Combine2(decodeTenant, decodeUser), func(r *http.Request, input httphandler.Tuple2[string, string]) httphandler.Responder {
+		return nil
+	},
+		httphandler.WithStageNames("tenant", "user"),
+		httphandlerotel.WithStageSpans(tracer),
+	)
+	start := time.Now()
+
+	// When:
+	httphandlerotel.Handle(h, tracer, "POST /orders").ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	// Then: stage spans are timed on the system clock, around the spans of their decoder
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans.Ended() {
+		byName[span.Name()] = span
+	}
+	for _, name := range []string{"tenant", "user"} {
+		stage, ok := byName[name]
+		if !ok {
+			t.Fatalf("stage span %q: want ended, got none", name)
+		}
+		if stage.StartTime().Before(start) || stage.EndTime().After(time.Now()) {
+			t.Errorf("stage span %q: want within the request on the system clock, got %v to %v", name, stage.StartTime(), stage.EndTime())
+		}
+	}
+	lookup, ok := byName["lookup user"]
+	if !ok {
+		t.Fatalf("decoder span: want ended, got none")
+	}
+	if lookup.Parent().SpanID() != byName["user"].SpanContext().SpanID() {
+		t.Errorf("decoder span: want child of the %q stage span", "user")
+	}
+}
@@ -0,0 +1,152 @@
+// Package imageresp provides responders that render images as PNG, such as QR codes for
+// authenticator enrollment or payment links.
+package imageresp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// ContentType is the media type of the responses written by this package.
+const ContentType = "image/png"
+
+// DefaultCacheControl is the Cache-Control header sent unless WithCacheControl is used.
+const DefaultCacheControl = "private, max-age=300"
+
+// Ensure pngResponder implements Responder.
+var _ httphandler.Responder = (*pngResponder)(nil)
+
+// PNG creates a responder that sends img encoded as PNG with a 200 OK status.
+func PNG(img image.Image) *pngResponder {
+	return &pngResponder{
+		statusCode:   http.StatusOK,
+		cacheControl: DefaultCacheControl,
+		render: func() (image.Image, error) {
+			return img, nil
+		},
+	}
+}
+
+// pngResponder handles PNG image HTTP responses.
+type pngResponder struct {
+	logger       httphandler.Logger
+	header       http.Header
+	override     http.Header
+	statusCode   int
+	cookies      []*http.Cookie
+	render       func() (image.Image, error)
+	encoder      QREncoder
+	cacheControl string
+}
+
+// Respond encodes the image and sends it with custom headers, cookies and status code.
+// The image is encoded in full before anything is written, so a failure still results in a
+// clean 500 Internal Server Error.
+func (res *pngResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	img, err := res.render()
+	if err != nil {
+		httphandler.WriteInternalServerError(w, res.logger, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		httphandler.WriteInternalServerError(w, res.logger, err)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	if res.cacheControl != "" {
+		w.Header().Set("Cache-Control", res.cacheControl)
+	}
+
+	if notModified(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		httphandler.LogResponse(res.logger, http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(res.statusCode)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		httphandler.LogRequestError(res.logger, err, "status_code", res.statusCode)
+		return
+	}
+
+	httphandler.LogResponse(res.logger, res.statusCode, "bytes", buf.Len())
+}
+
+// notModified reports whether If-None-Match of r matches etag.
+func notModified(r *http.Request, etag string) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	for _, tag := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCacheControl sets the Cache-Control directives of the response, DefaultCacheControl by
+// default. Use "no-store" for images that contain secrets, e.g. TOTP enrollment codes.
+// Without directives, no Cache-Control header is sent.
+func (res *pngResponder) WithCacheControl(directives ...string) *pngResponder {
+	res.cacheControl = strings.Join(directives, ", ")
+	return res
+}
+
+// WithLogger sets the logger for the responder.
+func (res *pngResponder) WithLogger(logger httphandler.Logger) *pngResponder {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *pngResponder) WithStatus(status int) *pngResponder {
+	res.statusCode = status
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *pngResponder) WithHeader(key, value string) *pngResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *pngResponder) SetHeader(key, value string) *pngResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *pngResponder) WithCookie(cookie *http.Cookie) *pngResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
@@ -0,0 +1,69 @@
+package imageresp
+
+import (
+	"errors"
+	"image"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrNoQREncoder is the error of a QRCode response when no QREncoder is set.
+var ErrNoQREncoder = errors.New("imageresp: no QR encoder set")
+
+// QREncoder renders data as a QR code image of about size×size pixels. It is implemented
+// with a QR code library of choice, so that this module does not depend on one.
+type QREncoder interface {
+	QRCode(data string, size int) (image.Image, error)
+}
+
+// QREncoderFunc is an adapter to allow the use of ordinary functions as QREncoders.
+type QREncoderFunc func(data string, size int) (image.Image, error)
+
+// QRCode calls f(data, size).
+func (f QREncoderFunc) QRCode(data string, size int) (image.Image, error) {
+	return f(data, size)
+}
+
+// qrEncoder holds the encoder set with SetQREncoder.
+var qrEncoder atomic.Pointer[QREncoder]
+
+// SetQREncoder sets the encoder used by QRCode responders that have none set with
+// WithEncoder. Passing nil removes it.
+func SetQREncoder(enc QREncoder) {
+	if enc == nil {
+		qrEncoder.Store(nil)
+		return
+	}
+	qrEncoder.Store(&enc)
+}
+
+// QRCode creates a responder that sends data as a QR code of about size×size pixels,
+// rendered with the encoder set with WithEncoder or SetQREncoder, and encoded as PNG.
+// The response is cacheable, see WithCacheControl, and conditional requests are answered
+// with 304 Not Modified. Without an encoder, a 500 Internal Server Error is sent.
+func QRCode(data string, size int) *pngResponder {
+	res := &pngResponder{
+		statusCode:   http.StatusOK,
+		cacheControl: DefaultCacheControl,
+	}
+	res.render = func() (image.Image, error) {
+		enc := res.encoder
+		if enc == nil {
+			if p := qrEncoder.Load(); p != nil {
+				enc = *p
+			}
+		}
+		if enc == nil {
+			return nil, ErrNoQREncoder
+		}
+		return enc.QRCode(data, size)
+	}
+	return res
+}
+
+// WithEncoder sets the encoder of a QRCode responder, instead of the one set with
+// SetQREncoder.
+func (res *pngResponder) WithEncoder(enc QREncoder) *pngResponder {
+	res.encoder = enc
+	return res
+}
@@ -0,0 +1,150 @@
+package imageresp_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/imageresp"
+)
+
+// squareEncoder renders a black square instead of a QR code, so that the tests do not depend on
+// a QR code library.
+var squareEncoder = imageresp.QREncoderFunc(func(data string, size int) (image.Image, error) {
+	if data == "" {
+		return nil, errors.New("empty data")
+	}
+	return image.NewGray(image.Rect(0, 0, size, size)), nil
+})
+
+func TestQRCode_Respond(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantSize    int
+	}{
+		{
+			desc:     "encoded",
+			given:    imageresp.QRCode("otpauth://totp/x", 64).WithEncoder(squareEncoder),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":  imageresp.ContentType,
+				"Cache-Control": imageresp.DefaultCacheControl,
+			},
+			wantSize: 64,
+		},
+		{
+			desc:     "cache control | with header",
+			given:    imageresp.QRCode("pay:123", 8).WithEncoder(squareEncoder).WithCacheControl("no-store").WithHeader("X-Test-1", "test value 1"),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Cache-Control": "no-store",
+				"X-Test-1":      "test value 1",
+			},
+			wantSize: 8,
+		},
+		{
+			desc:     "no encoder",
+			given:    imageresp.QRCode("pay:123", 8),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			desc:     "encoder error",
+			given:    imageresp.QRCode("", 8).WithEncoder(squareEncoder),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			desc:     "png",
+			given:    imageresp.PNG(image.NewGray(image.Rect(0, 0, 4, 4))),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": imageresp.ContentType,
+			},
+			wantSize: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/qr", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Fatalf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			for key, want := range tc.wantHeaders {
+				if got := w.Header().Get(key); got != want {
+					t.Errorf("header %s: want %q, got %q", key, want, got)
+				}
+			}
+			if tc.wantSize == 0 {
+				return
+			}
+			img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+			if err != nil {
+				t.Fatalf("body: want PNG, got %v", err)
+			}
+			if got := img.Bounds().Dx(); got != tc.wantSize {
+				t.Errorf("image size: want %d, got %d", tc.wantSize, got)
+			}
+		})
+	}
+}
+
+func TestQRCode_NotModified(t *testing.T) {
+	t.Parallel()
+
+	// Given: the ETag of a previous response
+	res := imageresp.QRCode("pay:123", 16).WithEncoder(squareEncoder)
+	first := httptest.NewRecorder()
+	res.Respond(first, httptest.NewRequest(http.MethodGet, "/qr", nil))
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("ETag: want set, got none")
+	}
+
+	// When:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/qr", nil)
+	r.Header.Set("If-None-Match", etag)
+	res.Respond(w, r)
+
+	// Then:
+	if w.Code != http.StatusNotModified {
+		t.Errorf("status code: want %d, got %d", http.StatusNotModified, w.Code)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("body: want empty, got %d bytes", w.Body.Len())
+	}
+}
+
+// TestSetQREncoder is not parallel because it changes the package-level encoder.
+func TestSetQREncoder(t *testing.T) {
+	imageresp.SetQREncoder(squareEncoder)
+	defer imageresp.SetQREncoder(nil)
+
+	// When:
+	w := httptest.NewRecorder()
+	imageresp.QRCode("pay:123", 8).Respond(w, httptest.NewRequest(http.MethodGet, "/qr", nil))
+
+	// Then:
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+}
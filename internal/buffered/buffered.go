@@ -0,0 +1,71 @@
+// Package buffered provides the ResponseWriter that keeps a response in memory, so that it can
+// be inspected, replayed or signed once it is complete. It is shared by the root package and
+// package webhook.
+package buffered
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// Writer is an http.ResponseWriter that keeps the response in memory.
+type Writer struct {
+	header      http.Header
+	snapshot    http.Header
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// NewWriter returns an empty Writer.
+func NewWriter() *Writer {
+	return &Writer{header: http.Header{}}
+}
+
+// Header returns the header map.
+func (w *Writer) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the status code and a snapshot of the headers.
+func (w *Writer) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.statusCode = statusCode
+	w.snapshot = w.header.Clone()
+}
+
+// Write appends to the body.
+func (w *Writer) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// Status returns the status code written, or 200 OK if none was, as net/http sends it.
+func (w *Writer) Status() int {
+	w.WriteHeader(http.StatusOK)
+	return w.statusCode
+}
+
+// SentHeader returns the headers as they were when the status code was written. Headers set
+// afterwards are not sent by net/http, so they are left out.
+func (w *Writer) SentHeader() http.Header {
+	w.WriteHeader(http.StatusOK)
+	return w.snapshot
+}
+
+// Body returns the body written.
+func (w *Writer) Body() []byte {
+	return w.body.Bytes()
+}
+
+// Respond sends the buffered response, so that it can be replayed as a Responder.
+func (w *Writer) Respond(rw http.ResponseWriter, _ *http.Request) {
+	for key, values := range w.SentHeader() {
+		rw.Header()[key] = values
+	}
+	rw.WriteHeader(w.Status())
+	_, _ = rw.Write(w.body.Bytes())
+}
@@ -0,0 +1,155 @@
+// Package deferred provides the ResponseWriter that applies the custom headers and cookies of
+// responders once, right before the status code is written. It is shared by the root package
+// and package responder, which cannot import each other.
+package deferred
+
+import "net/http"
+
+// Ensure writer implements http.Flusher.
+var _ http.Flusher = (*writer)(nil)
+
+// Writer returns a ResponseWriter that applies cookies, header and override to w once, right
+// before the status code is written. The values in header are added unless the responder set
+// the key itself after Writer was called; the values in override replace any other value.
+// The cookies are set as they are, so the cookie policy must already be applied.
+func Writer(w http.ResponseWriter, header, override http.Header, cookies []*http.Cookie) http.ResponseWriter {
+	if len(header) == 0 && len(override) == 0 && len(cookies) == 0 {
+		return w
+	}
+
+	preset := make(map[string]bool, len(w.Header()))
+	for key := range w.Header() {
+		preset[key] = true
+	}
+	return &writer{
+		ResponseWriter: w,
+		header:         header,
+		override:       override,
+		cookies:        cookies,
+		preset:         preset,
+	}
+}
+
+// writer applies cookies and headers when the status code is written.
+type writer struct {
+	http.ResponseWriter
+	header    http.Header
+	override  http.Header
+	cookies   []*http.Cookie
+	preset    map[string]bool
+	committed bool
+}
+
+func (w *writer) WriteHeader(statusCode int) {
+	w.commit()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	w.commit()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush commits the headers before flushing, since flushing writes the status code.
+func (w *writer) Flush() {
+	w.commit()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// commit applies the cookies, the headers that the responder did not set itself and the
+// overriding headers, once.
+func (w *writer) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+
+	for _, cookie := range w.cookies {
+		http.SetCookie(w.ResponseWriter, cookie)
+	}
+
+	h := w.ResponseWriter.Header()
+	for key, values := range w.header {
+		if _, ok := h[key]; ok && !w.preset[key] {
+			continue
+		}
+		for _, value := range values {
+			h.Add(key, value)
+		}
+	}
+	for key, values := range w.override {
+		h[key] = values
+	}
+}
+
+// Ensure mapper implements http.Flusher.
+var _ http.Flusher = (*mapper)(nil)
+
+// Mapper returns a ResponseWriter that passes the status code and a copy of the headers of the
+// response to fn right before the status code is written, and writes the status code and
+// headers that fn returns instead. A nil header returned by fn leaves the headers unchanged.
+func Mapper(w http.ResponseWriter, fn func(status int, header http.Header) (int, http.Header)) http.ResponseWriter {
+	return &mapper{ResponseWriter: w, fn: fn}
+}
+
+// mapper maps the status code and headers when the status code is written.
+type mapper struct {
+	http.ResponseWriter
+	fn        func(status int, header http.Header) (int, http.Header)
+	committed bool
+}
+
+func (w *mapper) WriteHeader(statusCode int) {
+	if w.committed {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.committed = true
+
+	statusCode, header := w.fn(statusCode, w.ResponseWriter.Header().Clone())
+	if header != nil {
+		h := w.ResponseWriter.Header()
+		for key := range h {
+			if _, ok := header[key]; !ok {
+				delete(h, key)
+			}
+		}
+		for key, values := range header {
+			h[key] = values
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *mapper) Write(b []byte) (int, error) {
+	if !w.committed {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush writes the status code before flushing, since flushing writes it otherwise.
+func (w *mapper) Flush() {
+	if !w.committed {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *mapper) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Commit writes 200 OK through w, a ResponseWriter returned by Mapper, if nothing was written
+// yet, so that the headers are mapped before the server sends them with its implicit 200 OK.
+func Commit(w http.ResponseWriter) {
+	if mw, ok := w.(*mapper); ok && !mw.committed {
+		mw.WriteHeader(http.StatusOK)
+	}
+}
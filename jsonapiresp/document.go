@@ -0,0 +1,107 @@
+package jsonapiresp
+
+// Document is a JSON:API top-level document. It holds either Data or Errors.
+type Document struct {
+	Data     any            `json:"data,omitempty"`
+	Errors   []ErrorObject  `json:"errors,omitempty"`
+	Meta     map[string]any `json:"meta,omitempty"`
+	Links    Links          `json:"links,omitempty"`
+	Included []*Resource    `json:"included,omitempty"`
+}
+
+// Links maps link names, e.g. "self" or "next", to URLs.
+type Links map[string]string
+
+// Resource is a JSON:API resource object. Attributes is encoded as the "attributes" member
+// and must encode as a JSON object without the resource's id and type.
+type Resource struct {
+	Type          string                  `json:"type"`
+	ID            string                  `json:"id,omitempty"`
+	Attributes    any                     `json:"attributes,omitempty"`
+	Relationships map[string]Relationship `json:"relationships,omitempty"`
+	Links         Links                   `json:"links,omitempty"`
+	Meta          map[string]any          `json:"meta,omitempty"`
+}
+
+// NewResource creates a resource object of the specified type and id.
+func NewResource(typ, id string, attributes any) *Resource {
+	return &Resource{
+		Type:       typ,
+		ID:         id,
+		Attributes: attributes,
+	}
+}
+
+// Relate adds the relationship named name, e.g. with ToOne or ToMany.
+func (res *Resource) Relate(name string, rel Relationship) *Resource {
+	if res.Relationships == nil {
+		res.Relationships = map[string]Relationship{}
+	}
+	res.Relationships[name] = rel
+	return res
+}
+
+// Link adds the link named name to the resource.
+func (res *Resource) Link(name, href string) *Resource {
+	if res.Links == nil {
+		res.Links = Links{}
+	}
+	res.Links[name] = href
+	return res
+}
+
+// Identifier returns the resource identifier object of the resource.
+func (res *Resource) Identifier() Identifier {
+	return Identifier{Type: res.Type, ID: res.ID}
+}
+
+// Identifier is a JSON:API resource identifier object, the linkage of a relationship.
+type Identifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Relationship is a JSON:API relationship object.
+type Relationship struct {
+	Data  any            `json:"data"`
+	Links Links          `json:"links,omitempty"`
+	Meta  map[string]any `json:"meta,omitempty"`
+}
+
+// ToOne returns a to-one relationship to the resource of the specified type and id.
+// An empty id results in an empty relationship, encoded as null.
+func ToOne(typ, id string) Relationship {
+	if id == "" {
+		return Relationship{}
+	}
+	return Relationship{Data: Identifier{Type: typ, ID: id}}
+}
+
+// ToMany returns a to-many relationship to the resources of the specified type and ids.
+// It is encoded as an array even if it is empty.
+func ToMany(typ string, ids ...string) Relationship {
+	data := make([]Identifier, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, Identifier{Type: typ, ID: id})
+	}
+	return Relationship{Data: data}
+}
+
+// ErrorObject is a JSON:API error object.
+type ErrorObject struct {
+	ID     string         `json:"id,omitempty"`
+	Status string         `json:"status,omitempty"`
+	Code   string         `json:"code,omitempty"`
+	Title  string         `json:"title,omitempty"`
+	Detail string         `json:"detail,omitempty"`
+	Source *ErrorSource   `json:"source,omitempty"`
+	Links  Links          `json:"links,omitempty"`
+	Meta   map[string]any `json:"meta,omitempty"`
+}
+
+// ErrorSource refers to the part of the request that caused an error.
+type ErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Header    string `json:"header,omitempty"`
+}
@@ -0,0 +1,209 @@
+// Package jsonapiresp provides responders for JSON:API documents (https://jsonapi.org).
+package jsonapiresp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// ContentType is the media type of JSON:API documents.
+const ContentType = "application/vnd.api+json"
+
+// Ensure documentResponder implements Responder.
+var _ httphandler.Responder = (*documentResponder)(nil)
+
+// Data creates a response with the resource as primary data and a default status code of 200 OK.
+// A nil resource is sent as null, e.g. for an empty to-one related resource.
+func Data(resource *Resource) *documentResponder {
+	return &documentResponder{
+		statusCode: http.StatusOK,
+		doc:        Document{Data: resource},
+	}
+}
+
+// Collection creates a response with the resources as primary data and a default status code
+// of 200 OK. The data is sent as an array even if it is empty.
+func Collection(resources []*Resource) *documentResponder {
+	if resources == nil {
+		resources = []*Resource{}
+	}
+	return &documentResponder{
+		statusCode: http.StatusOK,
+		doc:        Document{Data: resources},
+	}
+}
+
+// Error creates an error response with a single error object with the specified detail message
+// and HTTP status code. The 'err' parameter can be used for internal logging.
+func Error(err error, detail string, code int) *documentResponder {
+	return Errors(err, code, ErrorObject{
+		Status: strconv.Itoa(code),
+		Title:  http.StatusText(code),
+		Detail: detail,
+	})
+}
+
+// Errors creates an error response with the error objects and the specified HTTP status code.
+// The 'err' parameter can be used for internal logging.
+func Errors(err error, code int, errs ...ErrorObject) *documentResponder {
+	return &documentResponder{
+		statusCode: code,
+		doc:        Document{Errors: errs},
+		err:        err,
+	}
+}
+
+// InternalServerError creates a standardized internal server error response.
+// The 'err' parameter can be used for internal logging.
+func InternalServerError(err error) *documentResponder {
+	return Errors(err, http.StatusInternalServerError, ErrorObject{
+		Status: strconv.Itoa(http.StatusInternalServerError),
+		Title:  http.StatusText(http.StatusInternalServerError),
+	})
+}
+
+// DecodeErrorHandler renders decoding failures as a 400 Bad Request error document.
+// It can be used with httphandler.WithDecodeErrorHandler.
+func DecodeErrorHandler(_ *http.Request, err error) httphandler.Responder {
+	return Error(err, "Invalid request payload", http.StatusBadRequest)
+}
+
+// WithDecodeErrors returns a handler option that renders decoding failures as error documents.
+func WithDecodeErrors() httphandler.HandlerOption {
+	return httphandler.WithDecodeErrorHandler(DecodeErrorHandler)
+}
+
+// Preset returns a handler option that renders every failure as an error document: decoding
+// failures with the status code and headers of httphandler.ClassifyDecodeError, and errors
+// returned by handlers and recovered panics as 500 Internal Server Error.
+func Preset() httphandler.HandlerOption {
+	return httphandler.Bundle(
+		httphandler.WithDecodeErrorHandler(presetDecodeErrorHandler),
+		httphandler.WithErrorMapper(func(_ *http.Request, err error) httphandler.Responder {
+			return InternalServerError(err)
+		}),
+		httphandler.WithPanicHandler(func(_ context.Context, recovered any, _ []byte) httphandler.Responder {
+			return InternalServerError(fmt.Errorf("panic: %v", recovered))
+		}),
+	)
+}
+
+// presetDecodeErrorHandler renders decoding failures with the status code and headers of
+// httphandler.ClassifyDecodeError.
+func presetDecodeErrorHandler(_ *http.Request, err error) httphandler.Responder {
+	class := httphandler.ClassifyDecodeError(err)
+	detail := http.StatusText(class.Status)
+	if class.Status == http.StatusBadRequest {
+		detail = "Invalid request payload"
+	}
+	res := Error(err, detail, class.Status)
+	for key, values := range class.Header {
+		for _, value := range values {
+			res.WithHeader(key, value)
+		}
+	}
+	return res
+}
+
+// MapError returns an httphandler.ErrorMapper that renders any error as an error document with
+// the specified detail message and HTTP status code, e.g. for use with httphandler.ErrorRegistry.
+func MapError(detail string, code int) httphandler.ErrorMapper {
+	return func(_ *http.Request, err error) httphandler.Responder {
+		return Error(err, detail, code)
+	}
+}
+
+// documentResponder handles application/vnd.api+json HTTP responses.
+type documentResponder struct {
+	logger     httphandler.Logger
+	header     http.Header
+	override   http.Header
+	statusCode int
+	cookies    []*http.Cookie
+	doc        Document
+	err        error
+}
+
+// Respond sends the document with custom headers, cookies and status code.
+func (res *documentResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+	httphandler.RecordError(r, res.err)
+
+	// Write the document.
+	b := responder.Encode(w, res.statusCode, ContentType, res.doc, json.Marshal, res.logger)
+	if b == nil {
+		return
+	}
+	responder.Log(res.logger, res.statusCode, res.err, "response_body", b)
+}
+
+// WithIncluded adds resources related to the primary data to the "included" member,
+// forming a compound document.
+func (res *documentResponder) WithIncluded(resources ...*Resource) *documentResponder {
+	res.doc.Included = append(res.doc.Included, resources...)
+	return res
+}
+
+// WithMeta adds a member to the top-level "meta" object.
+func (res *documentResponder) WithMeta(key string, value any) *documentResponder {
+	if res.doc.Meta == nil {
+		res.doc.Meta = map[string]any{}
+	}
+	res.doc.Meta[key] = value
+	return res
+}
+
+// WithLink adds a link to the top-level "links" object, e.g. "self" or "next".
+func (res *documentResponder) WithLink(name, href string) *documentResponder {
+	if res.doc.Links == nil {
+		res.doc.Links = Links{}
+	}
+	res.doc.Links[name] = href
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *documentResponder) WithStatus(status int) *documentResponder {
+	res.statusCode = status
+	return res
+}
+
+// WithLogger sets the logger for the responder.
+func (res *documentResponder) WithLogger(logger httphandler.Logger) *documentResponder {
+	res.logger = logger
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *documentResponder) WithHeader(key, value string) *documentResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *documentResponder) SetHeader(key, value string) *documentResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *documentResponder) WithCookie(cookie *http.Cookie) *documentResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
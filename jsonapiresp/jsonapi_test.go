@@ -0,0 +1,285 @@
+package jsonapiresp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonapiresp"
+)
+
+type article struct {
+	Title string `json:"title"`
+}
+
+type person struct {
+	Name string `json:"name"`
+}
+
+func TestDocument_Respond(t *testing.T) {
+	t.Parallel()
+
+	cookie := &http.Cookie{
+		Name:  "test-cookie",
+		Value: "test-value",
+	}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantCookies []*http.Cookie
+		wantBody    string
+	}{
+		{
+			desc:     "data",
+			given:    jsonapiresp.Data(jsonapiresp.NewResource("articles", "1", article{Title: "Hello"})),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/vnd.api+json",
+			},
+			wantBody: `{"data":{"type":"articles","id":"1","attributes":{"title":"Hello"}}}`,
+		},
+		{
+			desc:     "data | nil resource",
+			given:    jsonapiresp.Data(nil),
+			wantCode: http.StatusOK,
+			wantBody: `{"data":null}`,
+		},
+		{
+			desc:     "collection | empty",
+			given:    jsonapiresp.Collection(nil),
+			wantCode: http.StatusOK,
+			wantBody: `{"data":[]}`,
+		},
+		{
+			desc: "collection | with everything",
+			given: jsonapiresp.Collection([]*jsonapiresp.Resource{
+				jsonapiresp.NewResource("articles", "1", article{Title: "Hello"}).
+					Relate("author", jsonapiresp.ToOne("people", "9")).
+					Relate("comments", jsonapiresp.ToMany("comments")).
+					Link("self", "/articles/1"),
+			}).
+				WithIncluded(jsonapiresp.NewResource("people", "9", person{Name: "Dan"})).
+				WithMeta("total", 1).
+				WithLink("self", "/articles").
+				WithStatus(http.StatusCreated).
+				WithHeader("X-Test-1", "test value 1").
+				WithCookie(cookie),
+			wantCode: http.StatusCreated,
+			wantHeaders: map[string]string{
+				"X-Test-1": "test value 1",
+			},
+			wantCookies: []*http.Cookie{cookie},
+			wantBody: `{"data":[{"type":"articles","id":"1","attributes":{"title":"Hello"},` +
+				`"relationships":{"author":{"data":{"type":"people","id":"9"}},"comments":{"data":[]}},` +
+				`"links":{"self":"/articles/1"}}],"meta":{"total":1},"links":{"self":"/articles"},` +
+				`"included":[{"type":"people","id":"9","attributes":{"name":"Dan"}}]}`,
+		},
+		{
+			desc:     "error",
+			given:    jsonapiresp.Error(errors.New("invalid id"), "Invalid ID provided", http.StatusBadRequest),
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"errors":[{"status":"400","title":"Bad Request","detail":"Invalid ID provided"}]}`,
+		},
+		{
+			desc: "errors",
+			given: jsonapiresp.Errors(errors.New("validation"), http.StatusUnprocessableEntity,
+				jsonapiresp.ErrorObject{
+					Code:   "required",
+					Detail: "title is required",
+					Source: &jsonapiresp.ErrorSource{Pointer: "/data/attributes/title"},
+				}),
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: `{"errors":[{"code":"required","detail":"title is required",` +
+				`"source":{"pointer":"/data/attributes/title"}}]}`,
+		},
+		{
+			desc:     "internal server error",
+			given:    jsonapiresp.InternalServerError(errors.New("database failure")),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"errors":[{"status":"500","title":"Internal Server Error"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			gotCode := w.Code
+			if gotCode != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, gotCode)
+			}
+
+			gotHeaders := w.Header()
+			for key, wantValue := range tc.wantHeaders {
+				gotValue := gotHeaders.Get(key)
+				if gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			gotCookies := w.Result().Cookies()
+			if len(gotCookies) != len(tc.wantCookies) {
+				t.Errorf("cookie count: want %d, got %d", len(tc.wantCookies), len(gotCookies))
+			}
+
+			for i, want := range tc.wantCookies {
+				got := gotCookies[i]
+				if got.Name != want.Name || got.Value != want.Value {
+					t.Errorf("cookie %d: want {%s=%s}, got {%s=%s}",
+						i, want.Name, want.Value, got.Name, got.Value)
+				}
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
+
+func TestWithDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{"))
+
+	given := httphandler.HandleWithInput(
+		func(r *http.Request, input map[string]any) httphandler.Responder {
+			t.Errorf("handler: should not be called on decoding failure")
+			return nil
+		},
+		jsonapiresp.WithDecodeErrors(),
+	)
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status code: want %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != jsonapiresp.ContentType {
+		t.Errorf("Content-Type: want %s, got %s", jsonapiresp.ContentType, got)
+	}
+
+	wantBody := `{"errors":[{"status":"400","title":"Bad Request","detail":"Invalid request payload"}]}`
+	if gotBody := w.Body.String(); gotBody != wantBody {
+		t.Errorf("body: want '%s', got '%s'", wantBody, gotBody)
+	}
+}
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc       string
+		given      http.HandlerFunc
+		body       string
+		wantCode   int
+		wantHeader http.Header
+		wantBody   string
+	}{
+		{
+			desc: "decode error",
+			given: httphandler.HandleWithInput(func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}, jsonapiresp.Preset()),
+			body:     "{",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"errors":[{"status":"400","title":"Bad Request","detail":"Invalid request payload"}]}`,
+		},
+		{
+			desc: "body too large",
+			given: httphandler.HandleWithInput(func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}, jsonapiresp.Preset(), httphandler.WithMaxBodyBytes(2)),
+			body:     `{"a":1}`,
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: `{"errors":[{"status":"413","title":"Request Entity Too Large","detail":"Request Entity Too Large"}]}`,
+		},
+		{
+			desc: "insufficient scope",
+			given: httphandler.HandleWithDecoder(func(r *http.Request) (map[string]any, error) {
+				return nil, &httphandler.ScopeError{Missing: []string{"admin"}}
+			}, func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}, jsonapiresp.Preset()),
+			wantCode:   http.StatusForbidden,
+			wantHeader: http.Header{"Www-Authenticate": {`Bearer error="insufficient_scope", scope="admin"`}},
+			wantBody:   `{"errors":[{"status":"403","title":"Forbidden","detail":"Forbidden"}]}`,
+		},
+		{
+			desc: "unsupported media type",
+			given: httphandler.HandleWithDecoder(func(r *http.Request) (map[string]any, error) {
+				return nil, &httphandler.MediaTypeError{ContentType: "text/plain", Supported: []string{"application/json"}}
+			}, func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}, jsonapiresp.Preset()),
+			wantCode:   http.StatusUnsupportedMediaType,
+			wantHeader: http.Header{"Accept-Post": {"application/json"}},
+			wantBody:   `{"errors":[{"status":"415","title":"Unsupported Media Type","detail":"Unsupported Media Type"}]}`,
+		},
+		{
+			desc: "handler error",
+			given: httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+				return nil, errors.New("boom")
+			}, jsonapiresp.Preset()),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"errors":[{"status":"500","title":"Internal Server Error"}]}`,
+		},
+		{
+			desc: "panic",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				panic("boom")
+			}, jsonapiresp.Preset()),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"errors":[{"status":"500","title":"Internal Server Error"}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+
+			// When:
+			tc.given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			for key := range tc.wantHeader {
+				if got, want := w.Header().Get(key), tc.wantHeader.Get(key); got != want {
+					t.Errorf("%s: want %s, got %s", key, want, got)
+				}
+			}
+			if got := w.Header().Get("Content-Type"); got != jsonapiresp.ContentType {
+				t.Errorf("Content-Type: want %s, got %s", jsonapiresp.ContentType, got)
+			}
+			if gotBody := w.Body.String(); gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
 )
 
 // Ensure errorResponder implements Responder.
@@ -14,7 +15,18 @@ var _ httphandler.Responder = (*errorResponder)(nil)
 func Error(err error, message string, code int) *errorResponder {
 	return &errorResponder{
 		statusCode: code,
-		errMessage: message,
+		body:       map[string]string{"error": message},
+		err:        err,
+	}
+}
+
+// ErrorRaw creates an error response that encodes body as is instead of wrapping a message in
+// {"error": ...}, for APIs that define their own error shape.
+// The 'err' parameter can be used for internal logging.
+func ErrorRaw[T any](err error, body T, code int) *errorResponder {
+	return &errorResponder{
+		statusCode: code,
+		body:       body,
 		err:        err,
 	}
 }
@@ -24,7 +36,7 @@ func Error(err error, message string, code int) *errorResponder {
 func InternalServerError(err error) *errorResponder {
 	return &errorResponder{
 		statusCode: http.StatusInternalServerError,
-		errMessage: "Internal Server Error",
+		body:       map[string]string{"error": "Internal Server Error"},
 		err:        err,
 	}
 }
@@ -33,28 +45,21 @@ func InternalServerError(err error) *errorResponder {
 type errorResponder struct {
 	logger     httphandler.Logger
 	header     http.Header
+	override   http.Header
 	statusCode int
 	cookies    []*http.Cookie
-	errMessage string
+	body       any
 	err        error
 }
 
 // Respond sends the JSON error response with custom headers, cookies, and status code.
-func (res *errorResponder) Respond(w http.ResponseWriter, _ *http.Request) {
-	// Set cookies.
-	for _, cookie := range res.cookies {
-		http.SetCookie(w, cookie)
-	}
-
-	// Add custom headers.
-	for key, values := range res.header {
-		for _, value := range values {
-			w.Header().Add(key, value)
-		}
-	}
+func (res *errorResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+	httphandler.RecordError(r, res.err)
 
 	// Write the error JSON response.
-	writeJSON(w, map[string]string{"error": res.errMessage}, res.statusCode, res.logger)
+	writeJSON(w, res.body, res.statusCode, "application/json", res.logger)
 	httphandler.LogRequestError(res.logger, res.err)
 }
 
@@ -64,7 +69,8 @@ func (res *errorResponder) WithLogger(logger httphandler.Logger) *errorResponder
 	return res
 }
 
-// WithHeader adds a custom header to the response.
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
 func (res *errorResponder) WithHeader(key, value string) *errorResponder {
 	if res.header == nil {
 		res.header = http.Header{}
@@ -73,8 +79,27 @@ func (res *errorResponder) WithHeader(key, value string) *errorResponder {
 	return res
 }
 
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *errorResponder) SetHeader(key, value string) *errorResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
 // WithCookie adds a cookie to the response.
 func (res *errorResponder) WithCookie(cookie *http.Cookie) *errorResponder {
 	res.cookies = append(res.cookies, cookie)
 	return res
 }
+
+// MapError returns an httphandler.ErrorMapper that renders any error as a JSON error response
+// with the specified message and HTTP status code, e.g. for use with httphandler.ErrorRegistry.
+func MapError(message string, code int) httphandler.ErrorMapper {
+	return func(_ *http.Request, err error) httphandler.Responder {
+		return Error(err, message, code)
+	}
+}
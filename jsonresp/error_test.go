@@ -48,6 +48,15 @@ func TestError_Respond(t *testing.T) {
 			wantCookies: []*http.Cookie{cookie},
 			wantBody:    `{"error":"Post not found"}`,
 		},
+		{
+			desc: "raw body",
+			given: jsonresp.ErrorRaw(errors.New("invalid id"), struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}{"invalid_id", "Invalid ID provided"}, http.StatusBadRequest),
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"code":"invalid_id","message":"Invalid ID provided"}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,110 @@
+package jsonresp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// HALContentType is the media type of HAL documents.
+const HALContentType = "application/hal+json"
+
+// HALLink is a link object of a HAL document.
+type HALLink struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// HALResource is a HAL resource: the properties of data with "_links" and "_embedded" added.
+// Build it with NewHALResource and send it with HAL.
+type HALResource struct {
+	data     any
+	links    map[string][]HALLink
+	embedded map[string]any
+}
+
+// NewHALResource creates a resource with the properties of data, which must encode as a JSON object.
+// data may be nil for resources that only carry links.
+func NewHALResource(data any) *HALResource {
+	return &HALResource{data: data}
+}
+
+// Link adds a link with the relation rel. A relation with a single link is written as an object,
+// and as an array once more links are added.
+func (h *HALResource) Link(rel, href string) *HALResource {
+	return h.AddLink(rel, HALLink{Href: href})
+}
+
+// AddLink adds a link object with the relation rel. See Link.
+func (h *HALResource) AddLink(rel string, link HALLink) *HALResource {
+	if h.links == nil {
+		h.links = map[string][]HALLink{}
+	}
+	h.links[rel] = append(h.links[rel], link)
+	return h
+}
+
+// Embed embeds a single resource with the relation rel.
+func (h *HALResource) Embed(rel string, resource *HALResource) *HALResource {
+	if h.embedded == nil {
+		h.embedded = map[string]any{}
+	}
+	h.embedded[rel] = resource
+	return h
+}
+
+// EmbedList embeds a list of resources with the relation rel. It is written as an array even if
+// it has a single element.
+func (h *HALResource) EmbedList(rel string, resources []*HALResource) *HALResource {
+	if h.embedded == nil {
+		h.embedded = map[string]any{}
+	}
+	if resources == nil {
+		resources = []*HALResource{}
+	}
+	h.embedded[rel] = resources
+	return h
+}
+
+// MarshalJSON implements json.Marshaler.
+func (h *HALResource) MarshalJSON() ([]byte, error) {
+	doc := map[string]any{}
+	if h.data != nil {
+		b, err := json.Marshal(h.data)
+		if err != nil {
+			return nil, err
+		}
+		var props map[string]json.RawMessage
+		if err := json.Unmarshal(b, &props); err != nil {
+			return nil, fmt.Errorf("hal resource data must encode as a JSON object: %w", err)
+		}
+		for key, value := range props {
+			doc[key] = value
+		}
+	}
+
+	if len(h.links) > 0 {
+		links := make(map[string]any, len(h.links))
+		for rel, list := range h.links {
+			if len(list) == 1 {
+				links[rel] = list[0]
+				continue
+			}
+			links[rel] = list
+		}
+		doc["_links"] = links
+	}
+
+	if len(h.embedded) > 0 {
+		doc["_embedded"] = h.embedded
+	}
+
+	return json.Marshal(doc)
+}
+
+// HAL creates a successResponder that sends resource as application/hal+json.
+func HAL(resource *HALResource) *successResponder[HALResource] {
+	return Success(resource).WithContentType(HALContentType)
+}
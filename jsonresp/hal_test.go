@@ -0,0 +1,85 @@
+package jsonresp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func TestHAL_Respond(t *testing.T) {
+	t.Parallel()
+
+	type Order struct {
+		ID    string `json:"id"`
+		Total int    `json:"total"`
+	}
+
+	testCases := []struct {
+		desc            string
+		given           httphandler.Responder
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			desc: "links",
+			given: jsonresp.HAL(jsonresp.NewHALResource(&Order{ID: "1", Total: 30}).
+				Link("self", "/orders/1").
+				AddLink("find", jsonresp.HALLink{Href: "/orders{?id}", Templated: true})),
+			wantCode:        http.StatusOK,
+			wantContentType: jsonresp.HALContentType,
+			wantBody:        `{"_links":{"find":{"href":"/orders{?id}","templated":true},"self":{"href":"/orders/1"}},"id":"1","total":30}`,
+		},
+		{
+			desc: "embedded | with status",
+			given: jsonresp.HAL(jsonresp.NewHALResource(nil).
+				Link("self", "/orders").
+				Link("curies", "/docs/a").
+				Link("curies", "/docs/b").
+				EmbedList("orders", []*jsonresp.HALResource{
+					jsonresp.NewHALResource(&Order{ID: "1"}).Link("self", "/orders/1"),
+				}).
+				Embed("customer", jsonresp.NewHALResource(map[string]string{"name": "alice"}))).
+				WithStatus(http.StatusPartialContent),
+			wantCode:        http.StatusPartialContent,
+			wantContentType: jsonresp.HALContentType,
+			wantBody:        `{"_embedded":{"customer":{"name":"alice"},"orders":[{"_links":{"self":{"href":"/orders/1"}},"id":"1","total":0}]},"_links":{"curies":[{"href":"/docs/a"},{"href":"/docs/b"}],"self":{"href":"/orders"}}}`,
+		},
+		{
+			desc:            "data not an object",
+			given:           jsonresp.HAL(jsonresp.NewHALResource([]int{1})),
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Internal Server Error\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test-hal", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tc.wantContentType {
+				t.Errorf("content type: want '%s', got '%s'", tc.wantContentType, got)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
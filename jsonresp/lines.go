@@ -0,0 +1,156 @@
+package jsonresp
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// NDJSONContentType is the media type of newline-delimited JSON.
+const NDJSONContentType = "application/x-ndjson"
+
+// Ensure linesResponder implements Responder.
+var _ httphandler.Responder = (*linesResponder[any])(nil)
+
+// StreamLines creates a responder that writes the values produced by iter as newline-delimited JSON
+// (NDJSON / JSON Lines) without buffering them in memory.
+// iter calls yield for every value and stops if yield returns an error.
+// If iter fails before the first value, a 500 Internal Server Error is sent; later failures
+// end the stream early since the status code has already been sent. The stream also ends, with
+// the error logged, when the request context is done because the client is gone or the
+// deadline passed.
+func StreamLines[T any](iter func(yield func(T) error) error) *linesResponder[T] {
+	return &linesResponder[T]{
+		statusCode: http.StatusOK,
+		iter:       iter,
+		flushEvery: 1,
+	}
+}
+
+// linesResponder handles streaming NDJSON HTTP responses.
+type linesResponder[T any] struct {
+	logger       httphandler.Logger
+	header       http.Header
+	override     http.Header
+	statusCode   int
+	cookies      []*http.Cookie
+	iter         func(yield func(T) error) error
+	flushEvery   int
+	writeTimeout time.Duration
+	onAbort      func(err error)
+}
+
+// Respond streams the NDJSON response with custom headers, cookies and status code.
+func (res *linesResponder[T]) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// Fail the writes to a client that reads too slowly.
+	if res.writeTimeout > 0 {
+		var stop func()
+		w, stop = responder.WriteTimeout(w, r, res.writeTimeout, res.onAbort)
+		defer stop()
+	}
+
+	w.Header().Set("Content-Type", NDJSONContentType)
+
+	// Write the status code with the first line, so that an early failure can still be reported.
+	rc := http.NewResponseController(w)
+	lines := 0
+	ctx := r.Context()
+	err := res.iter(func(v T) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if lines == 0 {
+			w.WriteHeader(res.statusCode)
+		}
+		if _, err := w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+		lines++
+		if lines%res.flushEvery == 0 {
+			_ = rc.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if lines == 0 {
+			httphandler.WriteInternalServerError(w, res.logger, err)
+			return
+		}
+		httphandler.LogRequestError(res.logger, err, "status_code", res.statusCode, "lines", lines)
+		return
+	}
+
+	if lines == 0 {
+		w.WriteHeader(res.statusCode)
+	}
+	httphandler.LogResponse(res.logger, res.statusCode, "lines", lines)
+}
+
+// WithLogger sets the logger for the responder.
+func (res *linesResponder[T]) WithLogger(logger httphandler.Logger) *linesResponder[T] {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *linesResponder[T]) WithStatus(status int) *linesResponder[T] {
+	res.statusCode = status
+	return res
+}
+
+// WithFlushEvery flushes the response after every n lines instead of after every line.
+func (res *linesResponder[T]) WithFlushEvery(n int) *linesResponder[T] {
+	res.flushEvery = max(n, 1)
+	return res
+}
+
+// WithWriteTimeout fails the response when a line or a flush takes longer than d to write, so
+// that a client that reads too slowly does not hold the connection. See WithOnAbort.
+func (res *linesResponder[T]) WithWriteTimeout(d time.Duration) *linesResponder[T] {
+	res.writeTimeout = d
+	return res
+}
+
+// WithOnAbort sets a function called with the error when the stream is aborted because a write
+// failed with WithWriteTimeout, e.g. to release resources held for the stream.
+func (res *linesResponder[T]) WithOnAbort(fn func(err error)) *linesResponder[T] {
+	res.onAbort = fn
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *linesResponder[T]) WithHeader(key, value string) *linesResponder[T] {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *linesResponder[T]) SetHeader(key, value string) *linesResponder[T] {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *linesResponder[T]) WithCookie(cookie *http.Cookie) *linesResponder[T] {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
@@ -0,0 +1,128 @@
+package jsonresp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func TestStreamLines_Respond(t *testing.T) {
+	t.Parallel()
+
+	type Row struct {
+		ID int `json:"id"`
+	}
+
+	rows := func(n int, err error) func(yield func(Row) error) error {
+		return func(yield func(Row) error) error {
+			for i := 1; i <= n; i++ {
+				if err := yield(Row{ID: i}); err != nil {
+					return err
+				}
+			}
+			return err
+		}
+	}
+
+	testCases := []struct {
+		desc            string
+		given           httphandler.Responder
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			desc:            "rows",
+			given:           jsonresp.StreamLines(rows(3, nil)),
+			wantCode:        http.StatusOK,
+			wantContentType: jsonresp.NDJSONContentType,
+			wantBody:        "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n",
+		},
+		{
+			desc:            "no rows | with status",
+			given:           jsonresp.StreamLines(rows(0, nil)).WithStatus(http.StatusAccepted),
+			wantCode:        http.StatusAccepted,
+			wantContentType: jsonresp.NDJSONContentType,
+			wantBody:        "",
+		},
+		{
+			desc:            "error before first row",
+			given:           jsonresp.StreamLines(rows(0, errors.New("boom"))),
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Internal Server Error\n",
+		},
+		{
+			desc:            "error after first row | with flush every",
+			given:           jsonresp.StreamLines(rows(2, errors.New("boom"))).WithFlushEvery(10),
+			wantCode:        http.StatusOK,
+			wantContentType: jsonresp.NDJSONContentType,
+			wantBody:        "{\"id\":1}\n{\"id\":2}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test-lines", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tc.wantContentType {
+				t.Errorf("content type: want '%s', got '%s'", tc.wantContentType, got)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestStreamLines_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	// Given: a client that goes away after the second line
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	produced := 0
+	res := jsonresp.StreamLines(func(yield func(int) error) error {
+		for i := 1; i <= 100; i++ {
+			if err := yield(i); err != nil {
+				return err
+			}
+			produced++
+			if i == 2 {
+				cancel()
+			}
+		}
+		return nil
+	})
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	// When:
+	res.Respond(w, r)
+
+	// Then: the stream ends without encoding the remaining values
+	if got := w.Body.String(); got != "1\n2\n" {
+		t.Errorf("body: want %q, got %q", "1\n2\n", got)
+	}
+	if produced != 2 {
+		t.Errorf("produced: want 2, got %d", produced)
+	}
+}
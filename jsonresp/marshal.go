@@ -0,0 +1,16 @@
+package jsonresp
+
+import "sync/atomic"
+
+var marshaler atomic.Pointer[func(v any) ([]byte, error)]
+
+// SetMarshaler replaces encoding/json for the JSON responses of this package, e.g. with a faster
+// implementation. Passing nil restores encoding/json. Responses sent with WithStreaming always
+// use encoding/json.
+func SetMarshaler(marshal func(v any) ([]byte, error)) {
+	if marshal == nil {
+		marshaler.Store(nil)
+		return
+	}
+	marshaler.Store(&marshal)
+}
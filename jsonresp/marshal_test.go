@@ -0,0 +1,67 @@
+package jsonresp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+// TestSetMarshaler is not parallel because it changes the package-level marshaler.
+func TestSetMarshaler(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		givenMarshal func(v any) ([]byte, error)
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			desc: "custom marshaler",
+			givenMarshal: func(v any) ([]byte, error) {
+				return json.MarshalIndent(v, "", " ")
+			},
+			wantCode: http.StatusOK,
+			wantBody: "{\n \"id\": 1\n}",
+		},
+		{
+			desc: "custom marshaler error",
+			givenMarshal: func(v any) ([]byte, error) {
+				return nil, errors.New("boom")
+			},
+			wantCode: http.StatusInternalServerError,
+			wantBody: "Internal Server Error\n",
+		},
+		{
+			desc:         "restored default",
+			givenMarshal: nil,
+			wantCode:     http.StatusOK,
+			wantBody:     `{"id":1}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			jsonresp.SetMarshaler(tc.givenMarshal)
+			defer jsonresp.SetMarshaler(nil)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			jsonresp.Success(&map[string]int{"id": 1}).Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
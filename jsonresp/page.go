@@ -0,0 +1,22 @@
+package jsonresp
+
+// PageBody is the JSON document written by Page.
+type PageBody[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Page creates a successResponder for one page of a list, with the total number of items
+// and the cursor of the next page, if any. A nil items slice is written as an empty array.
+func Page[T any](items []T, total int, nextCursor string) *successResponder[PageBody[T]] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return Success(&PageBody[T]{
+		Items:      items,
+		Total:      total,
+		NextCursor: nextCursor,
+	})
+}
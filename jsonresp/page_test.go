@@ -0,0 +1,59 @@
+package jsonresp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func TestPage_Respond(t *testing.T) {
+	t.Parallel()
+
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	testCases := []struct {
+		desc     string
+		given    httphandler.Responder
+		wantBody string
+	}{
+		{
+			desc:     "empty",
+			given:    jsonresp.Page[User](nil, 0, ""),
+			wantBody: `{"items":[],"total":0}`,
+		},
+		{
+			desc:     "with next cursor",
+			given:    jsonresp.Page([]User{{Name: "alice"}, {Name: "bob"}}, 5, "c2"),
+			wantBody: `{"items":[{"name":"alice"},{"name":"bob"}],"total":5,"next_cursor":"c2"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != http.StatusOK {
+				t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
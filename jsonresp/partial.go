@@ -0,0 +1,99 @@
+package jsonresp
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// Ensure partialResponder implements Responder.
+var _ httphandler.Responder = (*partialResponder[any])(nil)
+
+// Partial creates a responder for data aggregated from several sources where some of them failed,
+// e.g. with the result of httphandler.GatherAll.
+// The body is {"data": ..., "failed_sources": [...]}; the errors themselves are only logged.
+func Partial[T any](data *T, failures map[string]error) *partialResponder[T] {
+	return &partialResponder[T]{
+		statusCode: http.StatusOK,
+		data:       data,
+		failures:   failures,
+	}
+}
+
+// partialBody is the JSON document written by partialResponder.
+type partialBody[T any] struct {
+	Data          *T       `json:"data"`
+	FailedSources []string `json:"failed_sources"`
+}
+
+// partialResponder handles JSON HTTP responses with partial failures.
+type partialResponder[T any] struct {
+	logger     httphandler.Logger
+	header     http.Header
+	override   http.Header
+	statusCode int
+	cookies    []*http.Cookie
+	data       *T
+	failures   map[string]error
+}
+
+// Respond sends the JSON response with custom headers, cookies and status code.
+func (res *partialResponder[T]) Respond(w http.ResponseWriter, _ *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// List the failed sources in a stable order.
+	failed := make([]string, 0, len(res.failures))
+	for source := range res.failures {
+		failed = append(failed, source)
+	}
+	sort.Strings(failed)
+
+	// Write the JSON response.
+	b := writeJSON(w, partialBody[T]{Data: res.data, FailedSources: failed}, res.statusCode, "application/json", res.logger)
+	for _, source := range failed {
+		httphandler.LogRequestError(res.logger, res.failures[source], "source", source)
+	}
+	httphandler.LogResponse(res.logger, res.statusCode, "response_body", b)
+}
+
+// WithLogger sets the logger for the responder.
+func (res *partialResponder[T]) WithLogger(logger httphandler.Logger) *partialResponder[T] {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *partialResponder[T]) WithStatus(status int) *partialResponder[T] {
+	res.statusCode = status
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *partialResponder[T]) WithHeader(key, value string) *partialResponder[T] {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *partialResponder[T]) SetHeader(key, value string) *partialResponder[T] {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *partialResponder[T]) WithCookie(cookie *http.Cookie) *partialResponder[T] {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
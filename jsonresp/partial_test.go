@@ -0,0 +1,81 @@
+package jsonresp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func TestPartial_Respond(t *testing.T) {
+	t.Parallel()
+
+	type Dashboard struct {
+		Profile string `json:"profile"`
+	}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			desc:     "no failures",
+			given:    jsonresp.Partial(&Dashboard{Profile: "alice"}, nil),
+			wantCode: http.StatusOK,
+			wantBody: `{"data":{"profile":"alice"},"failed_sources":[]}`,
+		},
+		{
+			desc: "with failures | with everything",
+			given: jsonresp.Partial(&Dashboard{Profile: "alice"}, map[string]error{
+				"orders":  errors.New("timeout"),
+				"billing": errors.New("connection refused"),
+			}).
+				WithStatus(http.StatusMultiStatus).
+				WithHeader("X-Test-1", "test value 1"),
+			wantCode: http.StatusMultiStatus,
+			wantHeaders: map[string]string{
+				"X-Test-1": "test value 1",
+			},
+			wantBody: `{"data":{"profile":"alice"},"failed_sources":["billing","orders"]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test-partial", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			gotCode := w.Code
+			if gotCode != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, gotCode)
+			}
+
+			gotHeaders := w.Header()
+			for key, wantValue := range tc.wantHeaders {
+				gotValue := gotHeaders.Get(key)
+				if gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
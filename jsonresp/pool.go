@@ -0,0 +1,62 @@
+package jsonresp
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+// BufferPool provides the buffers JSON responses are encoded into before they are written.
+type BufferPool interface {
+	// Get returns an empty buffer.
+	Get() *bytes.Buffer
+	// Put returns a buffer that is no longer used.
+	Put(buf *bytes.Buffer)
+}
+
+// maxPooledBufferSize is the capacity above which the default pool drops buffers, so that
+// a few large responses do not keep memory alive.
+const maxPooledBufferSize = 64 << 10
+
+// syncBufferPool is the default BufferPool, backed by a sync.Pool.
+type syncBufferPool struct {
+	pool sync.Pool
+}
+
+// Get returns an empty buffer.
+func (p *syncBufferPool) Get() *bytes.Buffer {
+	if buf, ok := p.pool.Get().(*bytes.Buffer); ok {
+		return buf
+	}
+	return &bytes.Buffer{}
+}
+
+// Put resets the buffer and keeps it for reuse unless it has grown too large.
+func (p *syncBufferPool) Put(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	p.pool.Put(buf)
+}
+
+var bufferPool atomic.Pointer[BufferPool]
+
+func init() {
+	SetBufferPool(nil)
+}
+
+// SetBufferPool replaces the pool of buffers used to encode JSON responses, e.g. to tune
+// the size of the buffers that are kept. Passing nil restores the default pool, which drops
+// buffers larger than 64 KiB.
+func SetBufferPool(pool BufferPool) {
+	if pool == nil {
+		pool = &syncBufferPool{}
+	}
+	bufferPool.Store(&pool)
+}
+
+// getBufferPool returns the pool set with SetBufferPool.
+func getBufferPool() BufferPool {
+	return *bufferPool.Load()
+}
@@ -0,0 +1,51 @@
+package jsonresp_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+// countingPool is a BufferPool that counts its calls.
+type countingPool struct {
+	gets, puts int
+}
+
+func (p *countingPool) Get() *bytes.Buffer {
+	p.gets++
+	return &bytes.Buffer{}
+}
+
+func (p *countingPool) Put(buf *bytes.Buffer) {
+	p.puts++
+}
+
+// TestSetBufferPool is not parallel because it changes the package-level buffer pool.
+func TestSetBufferPool(t *testing.T) {
+	pool := &countingPool{}
+	jsonresp.SetBufferPool(pool)
+	defer jsonresp.SetBufferPool(nil)
+
+	for id := 1; id <= 2; id++ {
+		// Given:
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		// When:
+		jsonresp.Success(&map[string]int{"id": id}).Respond(w, r)
+
+		// Then:
+		want := fmt.Sprintf(`{"id":%d}`, id)
+		if got := w.Body.String(); got != want {
+			t.Errorf("body: want '%s', got '%s'", want, got)
+		}
+	}
+
+	if pool.gets != 2 || pool.puts != 2 {
+		t.Errorf("pool calls: want 2 gets and 2 puts, got %d gets and %d puts", pool.gets, pool.puts)
+	}
+}
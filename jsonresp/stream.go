@@ -0,0 +1,64 @@
+package jsonresp
+
+import (
+	"bufio"
+	"context"
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+var (
+	marshalerType     = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// encodeStream writes v as JSON followed by a newline. A slice or array, possibly behind
+// pointers, is encoded element by element and ctx is checked before each element, so that a
+// large body is not encoded for a client that is gone or whose deadline passed.
+// The error of ctx is returned in that case.
+func encodeStream(ctx context.Context, w io.Writer, v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() && !customMarshaler(rv.Type()) {
+		rv = rv.Elem()
+	}
+	if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || customMarshaler(rv.Type()) ||
+		rv.Type().Elem().Kind() == reflect.Uint8 || (rv.Kind() == reflect.Slice && rv.IsNil()) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return json.NewEncoder(w).Encode(v)
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('[')
+	for i := 0; i < rv.Len(); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Encode addressable elements by pointer, as encoding/json does, so that a MarshalJSON
+		// method with a pointer receiver is used like in the buffered path.
+		elem := rv.Index(i)
+		if elem.CanAddr() {
+			elem = elem.Addr()
+		}
+		b, err := json.Marshal(elem.Interface())
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		if _, err := bw.Write(b); err != nil {
+			return err
+		}
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+// customMarshaler reports whether values of t encode themselves.
+func customMarshaler(t reflect.Type) bool {
+	return t.Implements(marshalerType) || t.Implements(textMarshalerType)
+}
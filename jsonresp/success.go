@@ -1,10 +1,13 @@
 package jsonresp
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
 )
 
 // Ensure successResponder implements Responder.
@@ -13,36 +16,72 @@ var _ httphandler.Responder = (*successResponder[any])(nil)
 // Success creates a new successResponder with the provided data and a default status code of 200 OK.
 func Success[T any](data *T) *successResponder[T] {
 	return &successResponder[T]{
-		statusCode: http.StatusOK,
-		data:       data,
+		statusCode:  http.StatusOK,
+		contentType: "application/json",
+		data:        data,
 	}
 }
 
 // successResponder handles successful JSON HTTP responses.
 type successResponder[T any] struct {
-	logger     httphandler.Logger
-	header     http.Header
-	statusCode int
-	cookies    []*http.Cookie
-	data       *T
+	logger        httphandler.Logger
+	header        http.Header
+	override      http.Header
+	statusCode    int
+	contentType   string
+	cookies       []*http.Cookie
+	data          *T
+	preferMinimal bool
+	streaming     bool
+	writeTimeout  time.Duration
+	onAbort       func(err error)
+	envelope      func(data any) any
 }
 
 // Respond sends the JSON response with custom headers, cookies and status code.
-func (res *successResponder[T]) Respond(w http.ResponseWriter, _ *http.Request) {
-	// Set cookies.
-	for _, cookie := range res.cookies {
-		http.SetCookie(w, cookie)
+func (res *successResponder[T]) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// Omit the body if the client asked for a minimal response. A status code other than
+	// 200 OK, e.g. 201 Created with its Location header, is kept.
+	if res.preferMinimal && httphandler.ParsePrefer(r).Return == "minimal" {
+		status := res.statusCode
+		if status == http.StatusOK {
+			status = http.StatusNoContent
+		}
+		w.Header().Set("Preference-Applied", "return=minimal")
+		w.WriteHeader(status)
+		httphandler.LogResponse(res.logger, status)
+		return
+	}
+
+	var body any = res.data
+	if res.envelope != nil {
+		body = res.envelope(res.data)
 	}
 
-	// Add custom headers.
-	for key, values := range res.header {
-		for _, value := range values {
-			w.Header().Add(key, value)
+	// Encode directly to the connection if the body may be too large to buffer.
+	if res.streaming {
+		// Fail the writes to a client that reads too slowly.
+		if res.writeTimeout > 0 {
+			var stop func()
+			w, stop = responder.WriteTimeout(w, r, res.writeTimeout, res.onAbort)
+			defer stop()
+		}
+
+		w.Header().Set("Content-Type", res.contentType)
+		w.WriteHeader(res.statusCode)
+		if err := encodeStream(r.Context(), w, body); err != nil {
+			httphandler.LogRequestError(res.logger, err, "status_code", res.statusCode)
+			return
 		}
+		httphandler.LogResponse(res.logger, res.statusCode)
+		return
 	}
 
 	// Write the JSON response.
-	b := writeJSON(w, res.data, res.statusCode, res.logger)
+	b := writeJSON(w, body, res.statusCode, res.contentType, res.logger)
 	httphandler.LogResponse(res.logger, res.statusCode, "response_body", b)
 }
 
@@ -58,7 +97,55 @@ func (res *successResponder[T]) WithStatus(status int) *successResponder[T] {
 	return res
 }
 
-// WithHeader adds a custom header to the response.
+// WithContentType sets the Content-Type of the response, for media types based on JSON
+// such as application/hal+json. It defaults to application/json.
+func (res *successResponder[T]) WithContentType(contentType string) *successResponder[T] {
+	res.contentType = contentType
+	return res
+}
+
+// WithEnvelope wraps the data with envelope before it is encoded, e.g. to send
+// {"data": ..., "meta": ...} documents. envelope receives the *T passed to Success.
+func (res *successResponder[T]) WithEnvelope(envelope func(data any) any) *successResponder[T] {
+	res.envelope = envelope
+	return res
+}
+
+// WithStreaming encodes the data directly to the response instead of buffering it first,
+// keeping memory constant for large payloads. The status code is sent before encoding, so an
+// encoding failure truncates the body instead of resulting in a 500 Internal Server Error.
+// Slices are encoded element by element, and encoding stops early, with the error logged, when
+// the request context is done because the client is gone or the deadline passed.
+// The body ends with a newline and is not logged.
+func (res *successResponder[T]) WithStreaming() *successResponder[T] {
+	res.streaming = true
+	return res
+}
+
+// WithWriteTimeout fails a response sent with WithStreaming when a write takes longer than d,
+// so that a client that reads too slowly does not hold the connection. See WithOnAbort.
+func (res *successResponder[T]) WithWriteTimeout(d time.Duration) *successResponder[T] {
+	res.writeTimeout = d
+	return res
+}
+
+// WithOnAbort sets a function called with the error when the response is aborted because a
+// write failed with WithWriteTimeout, e.g. to release resources held for the response.
+func (res *successResponder[T]) WithOnAbort(fn func(err error)) *successResponder[T] {
+	res.onAbort = fn
+	return res
+}
+
+// WithPreferMinimal makes the responder honor "Prefer: return=minimal" (RFC 7240) by sending
+// the response without a body when the client asks for it. 200 OK becomes 204 No Content, and
+// any other status code, e.g. 201 Created, is kept along with the headers.
+func (res *successResponder[T]) WithPreferMinimal() *successResponder[T] {
+	res.preferMinimal = true
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
 func (res *successResponder[T]) WithHeader(key, value string) *successResponder[T] {
 	if res.header == nil {
 		res.header = http.Header{}
@@ -67,6 +154,17 @@ func (res *successResponder[T]) WithHeader(key, value string) *successResponder[
 	return res
 }
 
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *successResponder[T]) SetHeader(key, value string) *successResponder[T] {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
 // WithCookie adds a cookie to the response.
 func (res *successResponder[T]) WithCookie(cookie *http.Cookie) *successResponder[T] {
 	res.cookies = append(res.cookies, cookie)
@@ -75,13 +173,30 @@ func (res *successResponder[T]) WithCookie(cookie *http.Cookie) *successResponde
 
 // writeJSON encodes the data as JSON and writes it to the ResponseWriter with the specified status code.
 // If encoding fails, it responds with a 500 Internal Server Error.
-func writeJSON(w http.ResponseWriter, v any, status int, logger httphandler.Logger) []byte {
-	w.Header().Set("Content-Type", "application/json")
-
-	b, err := json.Marshal(v)
-	if err != nil {
-		httphandler.WriteInternalServerError(w, logger, err, "data", v)
-		return nil
+// The data is encoded with the marshaler set with SetMarshaler, or else with encoding/json into a
+// buffer from the pool set with SetBufferPool. The written body is
+// returned for logging, so it is only copied out of the buffer if logger is not nil.
+func writeJSON(w http.ResponseWriter, v any, status int, contentType string, logger httphandler.Logger) []byte {
+	w.Header().Set("Content-Type", contentType)
+
+	var b []byte
+	if marshal := marshaler.Load(); marshal != nil {
+		var err error
+		if b, err = (*marshal)(v); err != nil {
+			httphandler.WriteInternalServerError(w, logger, err, "data", v)
+			return nil
+		}
+	} else {
+		pool := getBufferPool()
+		buf := pool.Get()
+		defer pool.Put(buf)
+
+		if err := json.NewEncoder(buf).Encode(v); err != nil {
+			httphandler.WriteInternalServerError(w, logger, err, "data", v)
+			return nil
+		}
+		// Drop the newline written by the encoder, to match json.Marshal.
+		b = bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
 	}
 
 	w.WriteHeader(status)
@@ -90,5 +205,8 @@ func writeJSON(w http.ResponseWriter, v any, status int, logger httphandler.Logg
 		return nil
 	}
 
-	return b
+	if logger == nil {
+		return nil
+	}
+	return bytes.Clone(b)
 }
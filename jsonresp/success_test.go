@@ -1,10 +1,16 @@
 package jsonresp_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alvinchoong/go-httphandler"
 	"github.com/alvinchoong/go-httphandler/jsonresp"
@@ -51,6 +57,39 @@ func TestSuccess_Respond(t *testing.T) {
 			wantCookies: []*http.Cookie{cookie},
 			wantBody:    `{"message":"Created Successfully"}`,
 		},
+		{
+			desc: "streaming | with everything",
+			given: jsonresp.Success(&SuccessData{Message: "Streamed"}).
+				WithHeader("X-Test-1", "test value 1").
+				WithStatus(http.StatusAccepted).
+				WithStreaming(),
+			wantCode: http.StatusAccepted,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/json",
+				"X-Test-1":     "test value 1",
+			},
+			wantBody: `{"message":"Streamed"}`,
+		},
+		{
+			desc: "set header | replaces content type",
+			given: jsonresp.Success(&SuccessData{Message: "Success"}).
+				WithHeader("Content-Type", "text/plain").
+				SetHeader("Content-Type", "application/vnd.api+json"),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/vnd.api+json",
+			},
+			wantBody: `{"message":"Success"}`,
+		},
+		{
+			desc: "with envelope",
+			given: jsonresp.Success(&SuccessData{Message: "Wrapped"}).
+				WithEnvelope(func(data any) any {
+					return map[string]any{"data": data, "meta": map[string]int{"version": 1}}
+				}),
+			wantCode: http.StatusOK,
+			wantBody: `{"data":{"message":"Wrapped"},"meta":{"version":1}}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -98,3 +137,236 @@ func TestSuccess_Respond(t *testing.T) {
 		})
 	}
 }
+
+func TestSuccess_RespondPreferMinimal(t *testing.T) {
+	t.Parallel()
+
+	type SuccessData struct {
+		Message string `json:"message"`
+	}
+
+	testCases := []struct {
+		desc         string
+		given        httphandler.Responder
+		givenPrefer  string
+		wantCode     int
+		wantApplied  string
+		wantLocation string
+		wantBody     string
+	}{
+		{
+			desc:        "enabled | prefer minimal",
+			given:       jsonresp.Success(&SuccessData{Message: "Updated"}).WithPreferMinimal(),
+			givenPrefer: "return=minimal",
+			wantCode:    http.StatusNoContent,
+			wantApplied: "return=minimal",
+			wantBody:    "",
+		},
+		{
+			desc:         "enabled | prefer minimal | created",
+			given:        jsonresp.Success(&SuccessData{Message: "Created"}).WithStatus(http.StatusCreated).WithHeader("Location", "/users/1").WithPreferMinimal(),
+			givenPrefer:  "return=minimal",
+			wantCode:     http.StatusCreated,
+			wantApplied:  "return=minimal",
+			wantLocation: "/users/1",
+			wantBody:     "",
+		},
+		{
+			desc:        "enabled | prefer representation",
+			given:       jsonresp.Success(&SuccessData{Message: "Created"}).WithStatus(http.StatusCreated).WithPreferMinimal(),
+			givenPrefer: "return=representation",
+			wantCode:    http.StatusCreated,
+			wantApplied: "",
+			wantBody:    `{"message":"Created"}`,
+		},
+		{
+			desc:        "disabled | prefer minimal",
+			given:       jsonresp.Success(&SuccessData{Message: "Created"}).WithStatus(http.StatusCreated),
+			givenPrefer: "return=minimal",
+			wantCode:    http.StatusCreated,
+			wantApplied: "",
+			wantBody:    `{"message":"Created"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/test-success", nil)
+			r.Header.Set("Prefer", tc.givenPrefer)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Header().Get("Preference-Applied"); got != tc.wantApplied {
+				t.Errorf("Preference-Applied: want '%s', got '%s'", tc.wantApplied, got)
+			}
+
+			if got := w.Header().Get("Location"); got != tc.wantLocation {
+				t.Errorf("Location: want '%s', got '%s'", tc.wantLocation, got)
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
+
+func TestSuccess_StreamingContext(t *testing.T) {
+	t.Parallel()
+
+	type Item struct {
+		ID int `json:"id"`
+	}
+
+	items := []Item{{ID: 1}, {ID: 2}, {ID: 3}}
+	raw := []byte("hi")
+
+	testCases := []struct {
+		desc        string
+		givenCancel bool
+		given       httphandler.Responder
+		wantBody    string
+	}{
+		{
+			desc:     "slice | encoded element by element",
+			given:    jsonresp.Success(&items).WithStreaming(),
+			wantBody: "[{\"id\":1},{\"id\":2},{\"id\":3}]\n",
+		},
+		{
+			desc:     "empty slice",
+			given:    jsonresp.Success(&[]Item{}).WithStreaming(),
+			wantBody: "[]\n",
+		},
+		{
+			desc:     "nil slice",
+			given:    jsonresp.Success(new([]Item)).WithStreaming(),
+			wantBody: "null\n",
+		},
+		{
+			desc:     "bytes | base64",
+			given:    jsonresp.Success(&raw).WithStreaming(),
+			wantBody: "\"aGk=\"\n",
+		},
+		{
+			desc:        "cancelled | slice not encoded",
+			givenCancel: true,
+			given:       jsonresp.Success(&items).WithStreaming(),
+			wantBody:    "",
+		},
+		{
+			desc:        "cancelled | object not encoded",
+			givenCancel: true,
+			given:       jsonresp.Success(&Item{ID: 1}).WithStreaming(),
+			wantBody:    "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.givenCancel {
+				cancel()
+			}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != http.StatusOK {
+				t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+// pointerMarshaler encodes itself with a MarshalJSON method that has a pointer receiver.
+type pointerMarshaler struct {
+	N int
+}
+
+func (m *pointerMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"custom"`), nil
+}
+
+func TestSuccess_StreamingMatchesBuffered(t *testing.T) {
+	t.Parallel()
+
+	items := []pointerMarshaler{{N: 1}, {N: 2}}
+
+	// Given:
+	buffered, streamed := httptest.NewRecorder(), httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// When:
+	jsonresp.Success(&items).Respond(buffered, r)
+	jsonresp.Success(&items).WithStreaming().Respond(streamed, r)
+
+	// Then:
+	want := `["custom","custom"]`
+	if got := strings.TrimSpace(buffered.Body.String()); got != want {
+		t.Errorf("buffered body: want %s, got %s", want, got)
+	}
+	if got := strings.TrimSpace(streamed.Body.String()); got != want {
+		t.Errorf("streamed body: want %s, got %s", want, got)
+	}
+}
+
+func TestSuccess_StreamingWriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	// Given: a large streamed body to a client that never reads
+	chunk := json.RawMessage(`"` + strings.Repeat("x", 1<<20) + `"`)
+	data := make([]json.RawMessage, 64)
+	for i := range data {
+		data[i] = chunk
+	}
+	aborted := make(chan error, 1)
+	srv := httptest.NewServer(httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return jsonresp.Success(&data).
+			WithStreaming().
+			WithWriteTimeout(100 * time.Millisecond).
+			WithOnAbort(func(err error) { aborted <- err })
+	}))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	// When:
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: example.com\r\n\r\n")); err != nil {
+		t.Fatalf("write request: %v", err)
+	}
+
+	// Then: the response is aborted instead of holding the connection
+	select {
+	case err := <-aborted:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("abort error: want %v, got %v", os.ErrDeadlineExceeded, err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("abort: want called, got not called within 10s")
+	}
+}
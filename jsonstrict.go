@@ -0,0 +1,120 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrJSONTrailingData     = errors.New("unexpected data after json value")
+)
+
+// MediaTypeError reports a request body whose Content-Type is not supported by the decoder.
+// It matches ErrUnsupportedMediaType with errors.Is, and the default decode error handler
+// renders it as 415 Unsupported Media Type advertising Supported.
+type MediaTypeError struct {
+	ContentType string
+	Supported   []string
+}
+
+// Error implements the error interface.
+func (e *MediaTypeError) Error() string {
+	return fmt.Sprintf("%s: %q, want %s", ErrUnsupportedMediaType, e.ContentType, strings.Join(e.Supported, ", "))
+}
+
+// Is reports whether target is ErrUnsupportedMediaType.
+func (e *MediaTypeError) Is(target error) bool {
+	return target == ErrUnsupportedMediaType
+}
+
+// JSONOption configures the decoder returned by JSONBodyStrict.
+type JSONOption func(*jsonOptions)
+
+// jsonOptions holds the settings that can be changed with a JSONOption.
+type jsonOptions struct {
+	disallowUnknownFields bool
+	disallowTrailingData  bool
+	requireContentType    bool
+	maxBytes              int64
+}
+
+// JSONDisallowUnknownFields rejects objects with keys that do not match a field of the target type.
+func JSONDisallowUnknownFields() JSONOption {
+	return func(o *jsonOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// JSONDisallowTrailingData rejects bodies that contain anything but whitespace after the first JSON value.
+func JSONDisallowTrailingData() JSONOption {
+	return func(o *jsonOptions) {
+		o.disallowTrailingData = true
+	}
+}
+
+// JSONRequireContentType rejects requests whose Content-Type is not application/json
+// with a *MediaTypeError.
+func JSONRequireContentType() JSONOption {
+	return func(o *jsonOptions) {
+		o.requireContentType = true
+	}
+}
+
+// JSONMaxBytes limits the body to n bytes. A larger body fails with *http.MaxBytesError,
+// which the default decode error handler renders as 413 Payload Too Large.
+func JSONMaxBytes(n int64) JSONOption {
+	return func(o *jsonOptions) {
+		o.maxBytes = n
+	}
+}
+
+// JSONBodyStrict returns a decoder like JSONBodyDecode with the checks enabled by opts.
+// Without options it behaves like JSONBodyDecode.
+func JSONBodyStrict[T any](opts ...JSONOption) RequestDecodeFunc[T] {
+	var o jsonOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(r *http.Request) (T, error) {
+		var v T
+
+		if o.requireContentType {
+			contentType := r.Header.Get("Content-Type")
+			if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "application/json" {
+				return v, &MediaTypeError{ContentType: contentType, Supported: []string{"application/json"}}
+			}
+		}
+
+		body := r.Body
+		if o.maxBytes > 0 {
+			body = http.MaxBytesReader(nil, body, o.maxBytes)
+		}
+
+		dec := json.NewDecoder(body)
+		if o.disallowUnknownFields {
+			dec.DisallowUnknownFields()
+		}
+		if err := dec.Decode(&v); err != nil {
+			return v, fmt.Errorf("%w: %w", ErrJSONDecode, err)
+		}
+
+		if o.disallowTrailingData {
+			if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					return v, fmt.Errorf("%w: %w", ErrJSONDecode, err)
+				}
+				return v, fmt.Errorf("%w: %w", ErrJSONDecode, ErrJSONTrailingData)
+			}
+		}
+
+		return v, nil
+	}
+}
@@ -0,0 +1,162 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestJSONBodyStrict(t *testing.T) {
+	t.Parallel()
+
+	type input struct {
+		Name string `json:"name"`
+	}
+
+	testCases := []struct {
+		desc             string
+		givenOpts        []httphandler.JSONOption
+		givenContentType string
+		givenBody        string
+		want             input
+		wantErr          error
+	}{
+		{
+			desc:      "no options | lenient",
+			givenBody: `{"name":"alice","age":3} garbage`,
+			want:      input{Name: "alice"},
+		},
+		{
+			desc:      "unknown fields | rejected",
+			givenOpts: []httphandler.JSONOption{httphandler.JSONDisallowUnknownFields()},
+			givenBody: `{"name":"alice","age":3}`,
+			wantErr:   httphandler.ErrJSONDecode,
+		},
+		{
+			desc:      "trailing data | rejected",
+			givenOpts: []httphandler.JSONOption{httphandler.JSONDisallowTrailingData()},
+			givenBody: `{"name":"alice"} {"name":"bob"}`,
+			wantErr:   httphandler.ErrJSONTrailingData,
+		},
+		{
+			desc:      "trailing whitespace | accepted",
+			givenOpts: []httphandler.JSONOption{httphandler.JSONDisallowTrailingData()},
+			givenBody: "{\"name\":\"alice\"}\n\t ",
+			want:      input{Name: "alice"},
+		},
+		{
+			desc:             "content type | accepted",
+			givenOpts:        []httphandler.JSONOption{httphandler.JSONRequireContentType()},
+			givenContentType: "application/json; charset=utf-8",
+			givenBody:        `{"name":"alice"}`,
+			want:             input{Name: "alice"},
+		},
+		{
+			desc:             "content type | rejected",
+			givenOpts:        []httphandler.JSONOption{httphandler.JSONRequireContentType()},
+			givenContentType: "text/plain",
+			givenBody:        `{"name":"alice"}`,
+			wantErr:          httphandler.ErrUnsupportedMediaType,
+		},
+		{
+			desc:      "max bytes | rejected",
+			givenOpts: []httphandler.JSONOption{httphandler.JSONMaxBytes(8)},
+			givenBody: `{"name":"alice"}`,
+			wantErr:   httphandler.ErrJSONDecode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.givenBody))
+			if tc.givenContentType != "" {
+				r.Header.Set("Content-Type", tc.givenContentType)
+			}
+
+			// When:
+			got, err := httphandler.JSONBodyStrict[input](tc.givenOpts...)(r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if tc.wantErr == nil && got != tc.want {
+				t.Errorf("input: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONBodyStrict_DefaultDecodeErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc             string
+		givenContentType string
+		givenBody        string
+		wantCode         int
+		wantAcceptPost   string
+	}{
+		{
+			desc:             "unsupported media type",
+			givenContentType: "text/plain",
+			givenBody:        `{}`,
+			wantCode:         http.StatusUnsupportedMediaType,
+			wantAcceptPost:   "application/json",
+		},
+		{
+			desc:             "payload too large",
+			givenContentType: "application/json",
+			givenBody:        `{"name":"alice"}`,
+			wantCode:         http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:             "trailing data",
+			givenContentType: "application/json",
+			givenBody:        `{}x`,
+			wantCode:         http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.givenBody))
+			r.Header.Set("Content-Type", tc.givenContentType)
+
+			given := httphandler.HandleWithDecoder(
+				httphandler.JSONBodyStrict[map[string]string](
+					httphandler.JSONRequireContentType(),
+					httphandler.JSONMaxBytes(8),
+					httphandler.JSONDisallowTrailingData(),
+				),
+				func(r *http.Request, input map[string]string) httphandler.Responder {
+					return &mockResponder{StatusCode: http.StatusOK}
+				},
+			)
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Header().Get("Accept-Post"); got != tc.wantAcceptPost {
+				t.Errorf("accept post: want '%s', got '%s'", tc.wantAcceptPost, got)
+			}
+		})
+	}
+}
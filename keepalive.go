@@ -0,0 +1,157 @@
+package httphandler
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Ensure keepAliveResponder implements Responder.
+var _ Responder = (*keepAliveResponder)(nil)
+
+// WithKeepAlive wraps a slow Responder so that filler bytes are written every interval until
+// the wrapped Responder starts writing, keeping proxies from closing an idle connection.
+// The filler must be ignorable by the client for the given content type, e.g. whitespace
+// before a JSON document or ": \n" comments for text/event-stream.
+//
+// Once filler has been sent the status (200 OK) and headers are committed, so the Content-Type
+// is set from contentType, and any status or headers set later by the wrapped Responder are lost.
+// If the wrapped Responder writes before the first interval elapses, the response is unchanged.
+func WithKeepAlive(res Responder, contentType string, interval time.Duration, filler []byte) Responder {
+	return &keepAliveResponder{
+		responder:   res,
+		contentType: contentType,
+		interval:    interval,
+		filler:      filler,
+	}
+}
+
+// keepAliveResponder writes filler bytes while the wrapped Responder is not ready.
+type keepAliveResponder struct {
+	responder   Responder
+	contentType string
+	interval    time.Duration
+	filler      []byte
+}
+
+// Respond delegates to the wrapped Responder and writes filler bytes until it starts writing.
+func (res *keepAliveResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	kw := &keepAliveWriter{
+		w:      w,
+		header: http.Header{},
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(res.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				if !kw.keepAlive(res.contentType, res.filler) {
+					return
+				}
+			}
+		}
+	}()
+
+	res.responder.Respond(kw, r)
+	close(done)
+	wg.Wait()
+
+	// Commit the headers if the wrapped Responder did not write anything.
+	kw.WriteHeader(http.StatusOK)
+}
+
+// keepAliveWriter serializes writes of the keep-alive ticker and the wrapped Responder.
+// The wrapped Responder gets its own header map, which is copied when it first writes.
+type keepAliveWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	mu        sync.Mutex
+	committed bool
+	started   bool
+}
+
+// Header returns the header map of the wrapped Responder.
+func (kw *keepAliveWriter) Header() http.Header {
+	return kw.header
+}
+
+// WriteHeader sends the headers and status code unless filler has already been sent.
+func (kw *keepAliveWriter) WriteHeader(statusCode int) {
+	kw.mu.Lock()
+	defer kw.mu.Unlock()
+
+	kw.writeHeader(statusCode)
+}
+
+// Write writes the body, sending the headers first if needed.
+func (kw *keepAliveWriter) Write(b []byte) (int, error) {
+	kw.mu.Lock()
+	defer kw.mu.Unlock()
+
+	kw.writeHeader(http.StatusOK)
+	return kw.w.Write(b)
+}
+
+// Flush flushes buffered data to the client.
+func (kw *keepAliveWriter) Flush() {
+	kw.mu.Lock()
+	defer kw.mu.Unlock()
+
+	kw.writeHeader(http.StatusOK)
+	_ = http.NewResponseController(kw.w).Flush()
+}
+
+// writeHeader marks the wrapped Responder as started and commits its headers if possible.
+// The caller must hold kw.mu.
+func (kw *keepAliveWriter) writeHeader(statusCode int) {
+	if kw.started {
+		return
+	}
+	kw.started = true
+
+	if kw.committed {
+		return
+	}
+	kw.committed = true
+
+	for key, values := range kw.header {
+		for _, value := range values {
+			kw.w.Header().Add(key, value)
+		}
+	}
+	kw.w.WriteHeader(statusCode)
+}
+
+// keepAlive writes the filler if the wrapped Responder has not started writing.
+// It reports whether the keep-alive should continue.
+func (kw *keepAliveWriter) keepAlive(contentType string, filler []byte) bool {
+	kw.mu.Lock()
+	defer kw.mu.Unlock()
+
+	if kw.started {
+		return false
+	}
+
+	if !kw.committed {
+		kw.committed = true
+		kw.w.Header().Set("Content-Type", contentType)
+		kw.w.WriteHeader(http.StatusOK)
+	}
+
+	if _, err := kw.w.Write(filler); err != nil {
+		return false
+	}
+	return http.NewResponseController(kw.w).Flush() == nil
+}
@@ -0,0 +1,90 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestWithKeepAlive(t *testing.T) {
+	t.Parallel()
+
+	slow := func(delay time.Duration) httphandler.Responder {
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Test-1", "test value 1")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"ok":true}`))
+		})
+	}
+
+	testCases := []struct {
+		desc            string
+		given           httphandler.Responder
+		wantCode        int
+		wantHeaders     map[string]string
+		wantFiller      bool
+		wantTrimmedBody string
+	}{
+		{
+			desc:     "fast responder | unchanged",
+			given:    httphandler.WithKeepAlive(slow(0), "application/json", time.Hour, []byte(" ")),
+			wantCode: http.StatusCreated,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/json",
+				"X-Test-1":     "test value 1",
+			},
+			wantFiller:      false,
+			wantTrimmedBody: `{"ok":true}`,
+		},
+		{
+			desc:     "slow responder | filler sent",
+			given:    httphandler.WithKeepAlive(slow(50*time.Millisecond), "application/json", 5*time.Millisecond, []byte(" ")),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/json",
+				"X-Test-1":     "",
+			},
+			wantFiller:      true,
+			wantTrimmedBody: `{"ok":true}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			for key, wantValue := range tc.wantHeaders {
+				if gotValue := w.Header().Get(key); gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			gotBody := w.Body.String()
+			if gotFiller := strings.HasPrefix(gotBody, " "); gotFiller != tc.wantFiller {
+				t.Errorf("filler: want %t, got %t", tc.wantFiller, gotFiller)
+			}
+
+			if got := strings.TrimSpace(gotBody); got != tc.wantTrimmedBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantTrimmedBody, got)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package httphandler
 
-import "net/http"
+import (
+	"net/http"
+	"sync/atomic"
+)
 
 type Logger interface {
 	Debug(msg string, args ...any)
@@ -9,7 +12,8 @@ type Logger interface {
 	Error(msg string, args ...any)
 }
 
-// WriteInternalServerError writes an HTTP 500 Internal Server Error response.
+// WriteInternalServerError writes an HTTP 500 Internal Server Error response with the writer
+// set with SetInternalServerErrorWriter, plain text by default.
 // If a logger is provided, it will also log the error.
 func WriteInternalServerError(w http.ResponseWriter, logger Logger, err error, args ...any) {
 	if logger != nil {
@@ -17,9 +21,56 @@ func WriteInternalServerError(w http.ResponseWriter, logger Logger, err error, a
 			append([]any{"error", err}, args...)...,
 		)
 	}
+	recordServerError(w, err)
+	writeInternalServerError(w, err)
+}
+
+// InternalServerErrorWriter writes the body of a 500 Internal Server Error response.
+// The Content-Type set by the responder that failed, if any, is still in w.Header(),
+// so the body can be written in the same format.
+type InternalServerErrorWriter func(w http.ResponseWriter, err error)
+
+// internalServerErrorWriter holds the writer set with SetInternalServerErrorWriter.
+var internalServerErrorWriter atomic.Pointer[InternalServerErrorWriter]
+
+// SetInternalServerErrorWriter sets the writer used for 500 Internal Server Error responses
+// sent by WriteInternalServerError, panic recovery and the default error mapper, e.g. to
+// send JSON bodies that clients can parse. Passing nil restores the plain text default.
+func SetInternalServerErrorWriter(fn InternalServerErrorWriter) {
+	if fn == nil {
+		internalServerErrorWriter.Store(nil)
+		return
+	}
+	internalServerErrorWriter.Store(&fn)
+}
+
+// PlainInternalServerError writes a plain text "Internal Server Error" body. It is the default
+// InternalServerErrorWriter.
+func PlainInternalServerError(w http.ResponseWriter, _ error) {
 	http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 }
 
+// JSONInternalServerError writes {"error":"Internal Server Error"} as application/json, like
+// the jsonresp error responses.
+func JSONInternalServerError(w http.ResponseWriter, _ error) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", "application/json")
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write([]byte(`{"error":"Internal Server Error"}`))
+}
+
+// writeInternalServerError writes a 500 Internal Server Error with the writer set with
+// SetInternalServerErrorWriter.
+func writeInternalServerError(w http.ResponseWriter, err error) {
+	if fn := internalServerErrorWriter.Load(); fn != nil {
+		(*fn)(w, err)
+		return
+	}
+	PlainInternalServerError(w, err)
+}
+
 // LogResponse logs the response status if a logger is provided.
 func LogResponse(logger Logger, status int, args ...any) {
 	if logger == nil {
@@ -0,0 +1,69 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// TestSetInternalServerErrorWriter is not parallel because it changes the package-level writer.
+func TestSetInternalServerErrorWriter(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		givenWriter     httphandler.InternalServerErrorWriter
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			desc:            "default",
+			givenWriter:     nil,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Internal Server Error\n",
+		},
+		{
+			desc:            "json",
+			givenWriter:     httphandler.JSONInternalServerError,
+			wantContentType: "application/json",
+			wantBody:        `{"error":"Internal Server Error"}`,
+		},
+		{
+			desc: "custom",
+			givenWriter: func(w http.ResponseWriter, err error) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(err.Error()))
+			},
+			wantContentType: "application/json",
+			wantBody:        "encode failure",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			httphandler.SetInternalServerErrorWriter(tc.givenWriter)
+			defer httphandler.SetInternalServerErrorWriter(nil)
+
+			w := httptest.NewRecorder()
+			w.Header().Set("Content-Type", "application/json")
+
+			// When:
+			httphandler.WriteInternalServerError(w, nil, errors.New("encode failure"))
+
+			// Then:
+			if w.Code != http.StatusInternalServerError {
+				t.Errorf("status code: want %d, got %d", http.StatusInternalServerError, w.Code)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tc.wantContentType {
+				t.Errorf("Content-Type: want %s, got %s", tc.wantContentType, got)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package httphandler
+
+import (
+	"net/http"
+	"time"
+)
+
+// LogRequests returns a middleware that logs every request handled by the wrapped handler
+// with its method, path, status code, response size, latency and the error that caused the
+// response, if known, e.g. the error of a decoder, a handler or an encoding failure recorded
+// by the responders of this module. Server errors and responses with an error are logged at
+// the error level, the others at the info level. A panic is logged with status 500 before it
+// is re-panicked. Requests abandoned by the client before a response was written are not
+// logged.
+func LogRequests(logger Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return logAccessHandler(next.ServeHTTP, logger, nil)
+	}
+}
+
+// WithAccessLogger logs every response of the handler, including responses for failed
+// decoding and recovered panics, as LogRequests does, with fields appended to each line. Set
+// it with SetDefaultHandlerOptions for one access log format across all handlers.
+func WithAccessLogger(logger Logger, fields ...any) HandlerOption {
+	return func(o *handlerOptions) {
+		o.accessLogger = logger
+		o.accessLogFields = fields
+	}
+}
+
+// WithAccessLog wraps res so that its Respond call is logged as LogRequests logs a request,
+// with fields appended, whatever logging res does itself, e.g. for a third-party Responder
+// without WithLogger.
+func WithAccessLog(res Responder, logger Logger, fields ...any) Responder {
+	return ResponderFunc(logAccessHandler(res.Respond, logger, fields))
+}
+
+// logAccessHandler wraps h so that its response is logged with logger, if not nil.
+func logAccessHandler(h http.HandlerFunc, logger Logger, fields []any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if logger == nil {
+			h(w, r)
+			return
+		}
+
+		rec, r := RecordResponse(w, r)
+		start := Now()
+
+		defer func() {
+			recovered := recover()
+			logAccess(logger, r, rec, Now().Sub(start), recovered, fields)
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		h(rec, r)
+	}
+}
+
+// logAccess logs the canonical access log line of the response recorded by rec. A recovered
+// panic is logged as a 500 Internal Server Error, and nothing is logged for an aborted request.
+func logAccess(logger Logger, r *http.Request, rec *ResponseRecorder, latency time.Duration, recovered any, fields []any) {
+	if recovered == nil && rec.Aborted() {
+		return
+	}
+	status, bytes, err := rec.Status(), rec.Bytes(), rec.Err()
+	if recovered != nil {
+		status, err = http.StatusInternalServerError, panicError(recovered)
+	}
+
+	args := append([]any{
+		"method", r.Method,
+		"path", r.URL.Path,
+		"status_code", status,
+		"bytes", bytes,
+		"latency", latency,
+	}, fields...)
+	if err != nil || status >= 500 {
+		logger.Error("Handled HTTP request", append(args, "error", err)...)
+		return
+	}
+	logger.Info("Handled HTTP request", args...)
+}
@@ -0,0 +1,244 @@
+package httphandler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func TestLogRequests(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := errors.New("order not found")
+	errDecode := errors.New("bad token")
+
+	testCases := []struct {
+		desc       string
+		given      http.Handler
+		wantLevel  string
+		wantStatus int
+		wantBytes  int
+		wantErr    string
+	}{
+		{
+			desc: "success",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				return jsonresp.Success(&map[string]string{"id": "1"})
+			}),
+			wantLevel:  "INFO",
+			wantStatus: http.StatusOK,
+			wantBytes:  len(`{"id":"1"}`),
+		},
+		{
+			desc: "error responder",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				return jsonresp.Error(errNotFound, "Not Found", http.StatusNotFound)
+			}),
+			wantLevel:  "ERROR",
+			wantStatus: http.StatusNotFound,
+			wantBytes:  len(`{"error":"Not Found"}`),
+			wantErr:    errNotFound.Error(),
+		},
+		{
+			desc: "handler error",
+			given: httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+				return nil, errNotFound
+			}),
+			wantLevel:  "ERROR",
+			wantStatus: http.StatusInternalServerError,
+			wantBytes:  len("Internal Server Error\n"),
+			wantErr:    errNotFound.Error(),
+		},
+		{
+			desc: "decode error",
+			given: httphandler.HandleWithDecoder(func(r *http.Request) (string, error) {
+				return "", errDecode
+			}, func(r *http.Request, input string) httphandler.Responder {
+				return nil
+			}),
+			wantLevel:  "ERROR",
+			wantStatus: http.StatusBadRequest,
+			wantErr:    errDecode.Error(),
+		},
+		{
+			desc: "plain http handler",
+			given: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+			}),
+			wantLevel:  "INFO",
+			wantStatus: http.StatusAccepted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			h := httphandler.LogRequests(logger)(tc.given)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/orders/1", nil)
+
+			// When:
+			h.ServeHTTP(w, r)
+
+			// Then:
+			var got struct {
+				Level      string
+				Msg        string
+				Method     string
+				Path       string
+				StatusCode int `json:"status_code"`
+				Bytes      int
+				Latency    *int64
+				Error      string
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("log: %v: %s", err, buf.String())
+			}
+			if got.Level != tc.wantLevel {
+				t.Errorf("level: want %s, got %s", tc.wantLevel, got.Level)
+			}
+			if got.Method != http.MethodPost || got.Path != "/orders/1" {
+				t.Errorf("request: want POST /orders/1, got %s %s", got.Method, got.Path)
+			}
+			if got.StatusCode != tc.wantStatus || w.Code != tc.wantStatus {
+				t.Errorf("status code: want %d, got %d (logged %d)", tc.wantStatus, w.Code, got.StatusCode)
+			}
+			if tc.wantBytes != 0 && got.Bytes != tc.wantBytes {
+				t.Errorf("bytes: want %d, got %d", tc.wantBytes, got.Bytes)
+			}
+			if got.Bytes != w.Body.Len() {
+				t.Errorf("bytes: want body length %d, got %d", w.Body.Len(), got.Bytes)
+			}
+			if got.Latency == nil {
+				t.Error("latency: want logged, got none")
+			}
+			if !strings.Contains(got.Error, tc.wantErr) || (tc.wantErr == "" && got.Error != "") {
+				t.Errorf("error: want %q, got %q", tc.wantErr, got.Error)
+			}
+		})
+	}
+}
+
+func TestLogRequests_Panic(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	h := httphandler.LogRequests(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	// When:
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic: want re-panicked, got none")
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	// Then:
+	if got := buf.String(); !strings.Contains(got, "status_code=500") || !strings.Contains(got, "error=\"panic: boom\"") {
+		t.Errorf("log: want status 500 and the panic, got %s", got)
+	}
+}
+
+func TestWithAccessLog(t *testing.T) {
+	t.Parallel()
+
+	errConflict := errors.New("version conflict")
+
+	testCases := []struct {
+		desc      string
+		given     func(logger httphandler.Logger) http.HandlerFunc
+		wantLevel string
+		wantCode  int
+		wantErr   string
+	}{
+		{
+			desc: "decorated responder",
+			given: func(logger httphandler.Logger) http.HandlerFunc {
+				return httphandler.Handle(func(r *http.Request) httphandler.Responder {
+					return httphandler.WithAccessLog(httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusAccepted)
+					}), logger, "route", "POST /orders")
+				})
+			},
+			wantLevel: "INFO",
+			wantCode:  http.StatusAccepted,
+		},
+		{
+			desc: "decorated error responder",
+			given: func(logger httphandler.Logger) http.HandlerFunc {
+				return httphandler.Handle(func(r *http.Request) httphandler.Responder {
+					return httphandler.WithAccessLog(jsonresp.Error(errConflict, "Conflict", http.StatusConflict), logger, "route", "POST /orders")
+				})
+			},
+			wantLevel: "ERROR",
+			wantCode:  http.StatusConflict,
+			wantErr:   errConflict.Error(),
+		},
+		{
+			desc: "handler option",
+			given: func(logger httphandler.Logger) http.HandlerFunc {
+				return httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+					return nil, errConflict
+				}, httphandler.WithAccessLogger(logger, "route", "POST /orders"))
+			},
+			wantLevel: "ERROR",
+			wantCode:  http.StatusInternalServerError,
+			wantErr:   errConflict.Error(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var buf bytes.Buffer
+			h := tc.given(slog.New(slog.NewJSONHandler(&buf, nil)))
+			w := httptest.NewRecorder()
+
+			// When:
+			h(w, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+			// Then:
+			var got struct {
+				Level      string
+				Route      string
+				StatusCode int `json:"status_code"`
+				Error      string
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("log: want one line, got %v: %s", err, buf.String())
+			}
+			if got.Level != tc.wantLevel {
+				t.Errorf("level: want %s, got %s", tc.wantLevel, got.Level)
+			}
+			if got.Route != "POST /orders" {
+				t.Errorf("route: want %q, got %q", "POST /orders", got.Route)
+			}
+			if got.StatusCode != tc.wantCode || w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d (logged %d)", tc.wantCode, w.Code, got.StatusCode)
+			}
+			if got.Error != tc.wantErr {
+				t.Errorf("error: want %q, got %q", tc.wantErr, got.Error)
+			}
+		})
+	}
+}
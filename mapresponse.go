@@ -0,0 +1,18 @@
+package httphandler
+
+import (
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler/internal/deferred"
+)
+
+// MapResponse wraps res so that fn can adjust the status code and headers of its response right
+// before they are sent, e.g. to send 200 OK instead of 204 No Content to legacy clients.
+// fn receives a copy of the headers, including the custom headers and cookies of res, and
+// returns the status code and headers to send instead. Returning a nil header leaves the
+// headers unchanged.
+func MapResponse(res Responder, fn func(status int, header http.Header) (int, http.Header)) Responder {
+	return ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+		res.Respond(deferred.Mapper(w, fn), r)
+	})
+}
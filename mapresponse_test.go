@@ -0,0 +1,93 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func TestMapResponse(t *testing.T) {
+	t.Parallel()
+
+	noContent := httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	data := map[string]string{"id": "1"}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		givenMap    func(status int, header http.Header) (int, http.Header)
+		wantCode    int
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			desc:  "force 200 for 204",
+			given: noContent,
+			givenMap: func(status int, header http.Header) (int, http.Header) {
+				if status == http.StatusNoContent {
+					return http.StatusOK, nil
+				}
+				return status, nil
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			desc:  "strip and add headers | custom headers visible",
+			given: jsonresp.Success(&data).WithHeader("X-Internal-Debug", "1"),
+			givenMap: func(status int, header http.Header) (int, http.Header) {
+				header.Del("X-Internal-Debug")
+				header.Set("X-Mapped", "yes")
+				return status, header
+			},
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":     "application/json",
+				"X-Internal-Debug": "",
+				"X-Mapped":         "yes",
+			},
+			wantBody: `{"id":"1"}`,
+		},
+		{
+			desc: "implicit 200 on write",
+			given: httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("hello"))
+			}),
+			givenMap: func(status int, header http.Header) (int, http.Header) {
+				return http.StatusAccepted, nil
+			},
+			wantCode: http.StatusAccepted,
+			wantBody: "hello",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			httphandler.MapResponse(tc.given, tc.givenMap).Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			for k, v := range tc.wantHeaders {
+				if got := w.Header().Get(k); got != v {
+					t.Errorf("header %q: want %q, got %q", k, v, got)
+				}
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+		})
+	}
+}
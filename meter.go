@@ -0,0 +1,106 @@
+package httphandler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Usage describes a handled request for metering, e.g. for billing or quotas.
+type Usage struct {
+	Request *http.Request
+	// Principal is the value set with SetUsagePrincipal, if any.
+	Principal any
+	// Units is the sum of the values passed to AddUsageUnits.
+	Units int
+	// Status is the status code of the response.
+	Status int
+	// Bytes is the size of the response body.
+	Bytes int64
+	// Variant is the variant chosen by Split, if the handler is one of its variants.
+	Variant string
+}
+
+// Succeeded reports whether the response has a 2xx status code.
+// Meters usually only charge for such requests.
+func (u Usage) Succeeded() bool {
+	return u.Status >= 200 && u.Status < 300
+}
+
+// Meter records the usage of handled requests.
+type Meter interface {
+	Record(ctx context.Context, usage Usage)
+}
+
+// MeterFunc is an adapter to allow the use of ordinary functions as Meters.
+type MeterFunc func(ctx context.Context, usage Usage)
+
+// Record calls f(ctx, usage).
+func (f MeterFunc) Record(ctx context.Context, usage Usage) {
+	f(ctx, usage)
+}
+
+// WithMeter records the usage of every request with meter after the response is written,
+// including responses for failed decoding and recovered panics. Requests abandoned by the
+// client before a response was written are not recorded.
+// Decoders and handlers describe the usage with SetUsagePrincipal and AddUsageUnits.
+func WithMeter(meter Meter) HandlerOption {
+	return func(o *handlerOptions) {
+		o.meter = meter
+	}
+}
+
+// usageKey is the context key of the *usage of a metered request.
+type usageKey struct{}
+
+// usage collects the values set by decoders and handlers of a metered request.
+type usage struct {
+	mu        sync.Mutex
+	principal any
+	units     int
+}
+
+// SetUsagePrincipal sets the principal that the usage of the request is recorded for.
+// It does nothing if the handler has no meter, see WithMeter.
+func SetUsagePrincipal(r *http.Request, principal any) {
+	if u, ok := r.Context().Value(usageKey{}).(*usage); ok {
+		u.mu.Lock()
+		u.principal = principal
+		u.mu.Unlock()
+	}
+}
+
+// AddUsageUnits adds units to the usage of the request, e.g. the number of items processed.
+// It does nothing if the handler has no meter, see WithMeter.
+func AddUsageUnits(r *http.Request, units int) {
+	if u, ok := r.Context().Value(usageKey{}).(*usage); ok {
+		u.mu.Lock()
+		u.units += units
+		u.mu.Unlock()
+	}
+}
+
+// meterHandler wraps h so that the usage of every request is recorded with meter.
+func meterHandler(h http.HandlerFunc, meter Meter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := &usage{}
+		r = r.WithContext(context.WithValue(r.Context(), usageKey{}, u))
+		rec, r := RecordResponse(w, r)
+
+		h(rec, r)
+		if rec.Aborted() {
+			return
+		}
+
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		meter.Record(r.Context(), Usage{
+			Request:   r,
+			Principal: u.principal,
+			Units:     u.units,
+			Status:    rec.Status(),
+			Bytes:     rec.Bytes(),
+			Variant:   Variant(r),
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package httphandler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestWithMeter(t *testing.T) {
+	t.Parallel()
+
+	type order struct {
+		Items []string `json:"items"`
+	}
+
+	testCases := []struct {
+		desc          string
+		givenBody     string
+		wantPrincipal any
+		wantUnits     int
+		wantStatus    int
+		wantBytes     int64
+		wantSucceeded bool
+	}{
+		{
+			desc:          "success",
+			givenBody:     `{"items":["a","b","c"]}`,
+			wantPrincipal: "alice",
+			wantUnits:     3,
+			wantStatus:    http.StatusCreated,
+			wantBytes:     2,
+			wantSucceeded: true,
+		},
+		{
+			desc:          "decode error",
+			givenBody:     `{`,
+			wantPrincipal: "alice",
+			wantStatus:    http.StatusBadRequest,
+			wantBytes:     int64(len("Invalid request payload\n")),
+			wantSucceeded: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var got httphandler.Usage
+			meter := httphandler.MeterFunc(func(ctx context.Context, usage httphandler.Usage) {
+				got = usage
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tc.givenBody))
+
+			given := httphandler.HandleWithDecoder(
+				func(r *http.Request) (order, error) {
+					httphandler.SetUsagePrincipal(r, "alice")
+					return httphandler.JSONBodyDecode[order](r)
+				},
+				func(r *http.Request, input order) httphandler.Responder {
+					httphandler.AddUsageUnits(r, len(input.Items))
+					return httphandler.Raw("text/plain", []byte("ok")).WithStatus(http.StatusCreated)
+				},
+				httphandler.WithMeter(meter),
+			)
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if got.Principal != tc.wantPrincipal {
+				t.Errorf("principal: want %v, got %v", tc.wantPrincipal, got.Principal)
+			}
+
+			if got.Units != tc.wantUnits {
+				t.Errorf("units: want %d, got %d", tc.wantUnits, got.Units)
+			}
+
+			if got.Status != tc.wantStatus {
+				t.Errorf("status code: want %d, got %d", tc.wantStatus, got.Status)
+			}
+
+			if got.Bytes != tc.wantBytes {
+				t.Errorf("bytes: want %d, got %d", tc.wantBytes, got.Bytes)
+			}
+
+			if got.Succeeded() != tc.wantSucceeded {
+				t.Errorf("succeeded: want %t, got %t", tc.wantSucceeded, got.Succeeded())
+			}
+		})
+	}
+}
+
+func TestWithMeter_Panic(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	var got httphandler.Usage
+	given := httphandler.Handle(
+		func(r *http.Request) httphandler.Responder { panic("boom") },
+		httphandler.WithPanicHandler(func(ctx context.Context, recovered any, stack []byte) httphandler.Responder { return nil }),
+		httphandler.WithMeter(httphandler.MeterFunc(func(ctx context.Context, usage httphandler.Usage) {
+			got = usage
+		})),
+	)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if got.Status != http.StatusInternalServerError {
+		t.Errorf("status code: want %d, got %d", http.StatusInternalServerError, got.Status)
+	}
+}
@@ -0,0 +1,17 @@
+package httphandler
+
+import "net/http"
+
+// MethodSwitch returns a handler that serves requests with safe methods (GET, HEAD, OPTIONS and TRACE)
+// with safe and all other methods with mutating. This allows a single route pattern to use a lighter
+// handler chain for reads and a stricter one (e.g. CSRF checks, body limits) for writes.
+func MethodSwitch(safe, mutating http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			safe.ServeHTTP(w, r)
+		default:
+			mutating.ServeHTTP(w, r)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestMethodSwitch(t *testing.T) {
+	t.Parallel()
+
+	given := httphandler.MethodSwitch(
+		httphandler.Handle(func(r *http.Request) httphandler.Responder {
+			return &mockResponder{StatusCode: http.StatusOK, Body: "safe"}
+		}),
+		httphandler.Handle(func(r *http.Request) httphandler.Responder {
+			return &mockResponder{StatusCode: http.StatusOK, Body: "mutating"}
+		}),
+	)
+
+	testCases := []struct {
+		desc     string
+		given    string
+		wantBody string
+	}{
+		{desc: "get", given: http.MethodGet, wantBody: "safe"},
+		{desc: "head", given: http.MethodHead, wantBody: "safe"},
+		{desc: "options", given: http.MethodOptions, wantBody: "safe"},
+		{desc: "post", given: http.MethodPost, wantBody: "mutating"},
+		{desc: "put", given: http.MethodPut, wantBody: "mutating"},
+		{desc: "delete", given: http.MethodDelete, wantBody: "mutating"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.given, "/", nil)
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Body.String() != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
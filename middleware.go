@@ -0,0 +1,162 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/alvinchoong/go-httphandler/internal/buffered"
+	"github.com/alvinchoong/go-httphandler/internal/deferred"
+)
+
+var ErrMiddlewareRejected = errors.New("middleware rejected request")
+
+// StageFromMiddleware returns a RequestDecodeFunc that runs an existing net/http middleware
+// and then extracts a typed value from the request it passes on (usually from its context).
+// If the middleware does not call the next handler, the request is treated as rejected and
+// anything the middleware wrote to the response is discarded. If it does, the headers and
+// cookies it set on the response are sent with the response of the handler created by
+// HandleWithDecoder or its variants, unless the responder sets the same header itself; they
+// are dropped when the decoder is run outside such a handler.
+func StageFromMiddleware[T any](mw func(http.Handler) http.Handler, extract func(*http.Request) (T, error)) RequestDecodeFunc[T] {
+	return func(r *http.Request) (T, error) {
+		var next *http.Request
+		h := mw(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			next = r
+		}))
+
+		w := &discardResponseWriter{header: http.Header{}, statusCode: http.StatusOK}
+		h.ServeHTTP(w, r)
+
+		if next == nil {
+			var v T
+			return v, fmt.Errorf("%w: status %d", ErrMiddlewareRejected, w.statusCode)
+		}
+		passMiddlewareHeaders(r, w.header)
+
+		return extract(next)
+	}
+}
+
+// FromMiddleware returns a RequestDecodeFunc that runs an existing net/http middleware as a
+// gate, e.g. a rate limiter or an IP allowlist. If the middleware does not call the next
+// handler, the decoder fails with an error that matches ErrMiddlewareRejected and halts the
+// handler with the response of the middleware, see Halt. If it does, the headers and cookies it
+// set on the response are sent with the response of the handler, as with StageFromMiddleware.
+// Changes the middleware makes to the request are not passed on; use StageFromMiddleware to
+// extract values from them.
+func FromMiddleware(mw func(http.Handler) http.Handler) RequestDecodeFunc[struct{}] {
+	return func(r *http.Request) (struct{}, error) {
+		passed := false
+		h := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			passed = true
+		}))
+
+		w := buffered.NewWriter()
+		h.ServeHTTP(w, r)
+
+		if !passed {
+			return struct{}{}, fmt.Errorf("%w: status %d: %w", ErrMiddlewareRejected, w.Status(), Halt(w))
+		}
+		passMiddlewareHeaders(r, w.Header())
+		return struct{}{}, nil
+	}
+}
+
+// middlewareHeaderKey is the context key of the *middlewareHeaders of a request.
+type middlewareHeaderKey struct{}
+
+// middlewareHeaders collects the response headers set by the middleware that decoders run, to
+// send them with the response of the handler. It is locked since Parallel decoders run
+// concurrently.
+type middlewareHeaders struct {
+	mu     sync.Mutex
+	header http.Header
+}
+
+// withMiddlewareHeaders returns r with a context in which the response headers set by the
+// middleware run by its decoders are collected.
+func withMiddlewareHeaders(r *http.Request) (*http.Request, *middlewareHeaders) {
+	mh := &middlewareHeaders{}
+	return r.WithContext(context.WithValue(r.Context(), middlewareHeaderKey{}, mh)), mh
+}
+
+// passMiddlewareHeaders adds header, set by a middleware that passed r on, to the headers sent
+// with the response of the handler. They are dropped if the decoder does not run in a handler
+// of this package.
+func passMiddlewareHeaders(r *http.Request, header http.Header) {
+	mh, ok := r.Context().Value(middlewareHeaderKey{}).(*middlewareHeaders)
+	if !ok || len(header) == 0 {
+		return
+	}
+
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+	if mh.header == nil {
+		mh.header = http.Header{}
+	}
+	for key, values := range header {
+		mh.header[key] = append(mh.header[key], values...)
+	}
+}
+
+// writer returns a ResponseWriter that adds the collected headers and cookies to w when the
+// status code is written. Headers that the responder sets itself take precedence, while
+// cookies are always added.
+func (mh *middlewareHeaders) writer(w http.ResponseWriter) http.ResponseWriter {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+	if len(mh.header) == 0 {
+		return w
+	}
+
+	header := mh.header.Clone()
+	cookies := (&http.Response{Header: http.Header{"Set-Cookie": header.Values("Set-Cookie")}}).Cookies()
+	header.Del("Set-Cookie")
+	return deferred.Writer(w, header, nil, cookies)
+}
+
+// ToMiddleware returns a net/http middleware that runs decode and stores the decoded value in
+// the request context under key before calling the next handler, so that decoders can be used
+// with routers and middleware chains that are not built with this package. The value can be
+// read back with FromContext[T](key). A decoding error is rendered with onError, or with the
+// default decode error handler if onError is nil, and the next handler is not called.
+func ToMiddleware[T any](decode RequestDecodeFunc[T], key any, onError DecodeErrorHandler) func(http.Handler) http.Handler {
+	if onError == nil {
+		onError = defaultDecodeErrorHandler
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			v, err := decode(r)
+			if err != nil {
+				decodeErrorResponder(r, err, onError).Respond(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), key, v)))
+		})
+	}
+}
+
+// discardResponseWriter is an http.ResponseWriter that records the status code and discards the body.
+type discardResponseWriter struct {
+	header     http.Header
+	statusCode int
+}
+
+// Header returns the header map.
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+// Write discards the body.
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// WriteHeader records the status code.
+func (w *discardResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
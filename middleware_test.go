@@ -0,0 +1,253 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// authMiddleware is a legacy middleware that stores the user in the request context.
+func authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := r.Header.Get("X-User")
+		if user == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey("user"), user)))
+	})
+}
+
+func TestStageFromMiddleware(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		given   string
+		want    string
+		wantErr error
+	}{
+		{
+			desc:  "middleware passes",
+			given: "alice",
+			want:  "alice",
+		},
+		{
+			desc:    "middleware rejects",
+			given:   "",
+			wantErr: httphandler.ErrMiddlewareRejected,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.given != "" {
+				r.Header.Set("X-User", tc.given)
+			}
+			decode := httphandler.StageFromMiddleware(authMiddleware, httphandler.FromContext[string](ctxKey("user")))
+
+			// When:
+			got, err := decode(r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("value: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStageFromMiddleware_Headers(t *testing.T) {
+	t.Parallel()
+
+	// Given: a middleware that sets headers and a cookie before passing the request on
+	session := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "99")
+			w.Header().Set("Cache-Control", "private")
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey("user"), "alice")))
+		})
+	}
+	h := httphandler.HandleWithDecoder(
+		httphandler.StageFromMiddleware(session, httphandler.FromContext[string](ctxKey("user"))),
+		func(r *http.Request, user string) httphandler.Responder {
+			return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "no-store")
+				http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark"})
+				w.Write([]byte(user))
+			})
+		},
+	)
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Then: the headers of the middleware are sent, unless the responder sets them itself
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "99" {
+		t.Errorf("X-RateLimit-Remaining: want %q, got %q", "99", got)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control: want %q, got %q", "no-store", got)
+	}
+	want := []string{"theme=dark", "session=abc"}
+	if got := w.Header().Values("Set-Cookie"); !slices.Equal(got, want) {
+		t.Errorf("Set-Cookie: want %v, got %v", want, got)
+	}
+	if got := w.Body.String(); got != "alice" {
+		t.Errorf("body: want %q, got %q", "alice", got)
+	}
+}
+
+func TestFromMiddleware(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		given    string
+		wantCode int
+		wantBody string
+	}{
+		{
+			desc:     "middleware passes",
+			given:    "alice",
+			wantCode: http.StatusOK,
+			wantBody: "ok",
+		},
+		{
+			desc:     "middleware rejects | its response is sent",
+			given:    "",
+			wantCode: http.StatusUnauthorized,
+			wantBody: "Unauthorized\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.given != "" {
+				r.Header.Set("X-User", tc.given)
+			}
+			w := httptest.NewRecorder()
+			h := httphandler.HandleWithDecoder(httphandler.FromMiddleware(authMiddleware), func(r *http.Request, _ struct{}) httphandler.Responder {
+				return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Write([]byte("ok"))
+				})
+			})
+
+			// When:
+			h(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestFromMiddleware_Error(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// When:
+	_, err := httphandler.FromMiddleware(authMiddleware)(r)
+
+	// Then:
+	if !errors.Is(err, httphandler.ErrMiddlewareRejected) {
+		t.Errorf("error: want %v, got %v", httphandler.ErrMiddlewareRejected, err)
+	}
+}
+
+func TestToMiddleware(t *testing.T) {
+	t.Parallel()
+
+	user := func(r *http.Request) (string, error) {
+		v := r.Header.Get("X-User")
+		if v == "" {
+			return "", errors.New("missing user")
+		}
+		return v, nil
+	}
+
+	testCases := []struct {
+		desc         string
+		given        string
+		givenOnError httphandler.DecodeErrorHandler
+		wantCode     int
+		wantBody     string
+	}{
+		{
+			desc:     "decoded | stored in context",
+			given:    "alice",
+			wantCode: http.StatusOK,
+			wantBody: "alice",
+		},
+		{
+			desc:     "fails | default decode error handler",
+			wantCode: http.StatusBadRequest,
+			wantBody: "Invalid request payload\n",
+		},
+		{
+			desc: "fails | custom decode error handler",
+			givenOnError: func(r *http.Request, err error) httphandler.Responder {
+				return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Error(w, err.Error(), http.StatusUnauthorized)
+				})
+			},
+			wantCode: http.StatusUnauthorized,
+			wantBody: "missing user\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.given != "" {
+				r.Header.Set("X-User", tc.given)
+			}
+			w := httptest.NewRecorder()
+			mw := httphandler.ToMiddleware(user, ctxKey("user"), tc.givenOnError)
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				v, _ := httphandler.FromContext[string](ctxKey("user"))(r)
+				w.Write([]byte(v))
+			}))
+
+			// When:
+			h.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package msgpackresp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var ErrDecode = errors.New("fail to decode msgpack")
+
+// Body decodes a MessagePack request body into a T, using its msgpack tags and falling back to
+// its json tags. Use it with httphandler.HandleWithDecoder.
+func Body[T any](r *http.Request) (T, error) {
+	var v T
+	dec := msgpack.NewDecoder(r.Body)
+	dec.SetCustomStructTag("json")
+	if err := dec.Decode(&v); err != nil {
+		return v, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	return v, nil
+}
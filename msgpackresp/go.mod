@@ -0,0 +1,12 @@
+module github.com/alvinchoong/go-httphandler/msgpackresp
+
+go 1.22
+
+require (
+	github.com/alvinchoong/go-httphandler v0.0.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+)
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+
+replace github.com/alvinchoong/go-httphandler => ../
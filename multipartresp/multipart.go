@@ -0,0 +1,249 @@
+// Package multipartresp provides a responder that streams multipart/form-data responses, e.g.
+// JSON metadata followed by a binary file, so that clients receive both atomically.
+package multipartresp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// ContentType is the media type of multipart/form-data responses, without the boundary parameter.
+const ContentType = "multipart/form-data"
+
+// Part is a part of a multipart response. Create it with JSON, File or Bytes.
+type Part struct {
+	name     string
+	filename string
+	header   textproto.MIMEHeader
+	data     []byte
+	reader   io.Reader
+	encode   func() ([]byte, error)
+}
+
+// JSON returns a part named name with v encoded as JSON.
+// v is encoded before the response is written, so an encoding failure results in a
+// 500 Internal Server Error.
+func JSON(name string, v any) Part {
+	return Part{
+		name:   name,
+		header: textproto.MIMEHeader{"Content-Type": {"application/json"}},
+		encode: func() ([]byte, error) { return json.Marshal(v) },
+	}
+}
+
+// File returns a part named name with the content of r as a file with the given file name.
+// An empty contentType defaults to application/octet-stream. If r is an io.Closer, it is closed
+// once the part is written.
+func File(name, filename, contentType string, r io.Reader) Part {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return Part{
+		name:     name,
+		filename: filename,
+		header:   textproto.MIMEHeader{"Content-Type": {contentType}},
+		reader:   r,
+	}
+}
+
+// Bytes returns a part named name with data as its body and the given content type.
+func Bytes(name, contentType string, data []byte) Part {
+	return Part{
+		name:   name,
+		header: textproto.MIMEHeader{"Content-Type": {contentType}},
+		data:   data,
+	}
+}
+
+// WithHeader returns a copy of the part with a header added, e.g. Content-Length or
+// Content-Transfer-Encoding. The Content-Disposition header is set from the name and file name.
+func (p Part) WithHeader(key, value string) Part {
+	header := make(textproto.MIMEHeader, len(p.header)+1)
+	for k, v := range p.header {
+		header[k] = append([]string(nil), v...)
+	}
+	header.Add(key, value)
+	p.header = header
+	return p
+}
+
+// Boundary returns a random boundary read from httphandler.Rand, suitable for
+// multipart.Writer.SetBoundary.
+func Boundary() (string, error) {
+	var b [16]byte
+	if _, err := io.ReadFull(httphandler.Rand(), b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// Ensure formDataResponder implements Responder.
+var _ httphandler.Responder = (*formDataResponder)(nil)
+
+// FormData creates a responder that streams the parts as multipart/form-data with a default
+// status code of 200 OK. The status code is sent before the first file part is read, so a read
+// failure truncates the body, leaving the closing boundary out so that clients can detect it.
+func FormData(parts ...Part) *formDataResponder {
+	return &formDataResponder{
+		statusCode: http.StatusOK,
+		parts:      parts,
+	}
+}
+
+// formDataResponder handles multipart/form-data HTTP responses.
+type formDataResponder struct {
+	logger     httphandler.Logger
+	header     http.Header
+	override   http.Header
+	statusCode int
+	cookies    []*http.Cookie
+	parts      []Part
+	boundary   string
+}
+
+// Respond streams the parts with custom headers, cookies and status code.
+func (res *formDataResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	defer res.closeParts()
+
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// Encode the JSON parts first, so that a failure can still be reported.
+	parts := make([]Part, len(res.parts))
+	for i, part := range res.parts {
+		if part.encode != nil {
+			b, err := part.encode()
+			if err != nil {
+				httphandler.WriteInternalServerError(w, res.logger, err, "part", part.name)
+				return
+			}
+			part.data = b
+		}
+		parts[i] = part
+	}
+
+	boundary := res.boundary
+	if boundary == "" {
+		var err error
+		if boundary, err = Boundary(); err != nil {
+			httphandler.WriteInternalServerError(w, res.logger, err)
+			return
+		}
+	}
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		httphandler.WriteInternalServerError(w, res.logger, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", mw.FormDataContentType())
+	w.WriteHeader(res.statusCode)
+
+	for _, part := range parts {
+		if err := writePart(mw, part); err != nil {
+			httphandler.LogRequestError(res.logger, err, "status_code", res.statusCode, "part", part.name)
+			return
+		}
+	}
+	if err := mw.Close(); err != nil {
+		httphandler.LogRequestError(res.logger, err, "status_code", res.statusCode)
+		return
+	}
+
+	httphandler.LogResponse(res.logger, res.statusCode, "parts", len(parts))
+}
+
+// writePart writes the headers and body of a part.
+func writePart(mw *multipart.Writer, part Part) error {
+	header := make(textproto.MIMEHeader, len(part.header)+1)
+	for k, v := range part.header {
+		header[k] = v
+	}
+	disposition := fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(part.name))
+	if part.filename != "" {
+		disposition += fmt.Sprintf(`; filename="%s"`, escapeQuotes(part.filename))
+	}
+	header.Set("Content-Disposition", disposition)
+
+	pw, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if part.reader != nil {
+		_, err = io.Copy(pw, part.reader)
+		return err
+	}
+	_, err = pw.Write(part.data)
+	return err
+}
+
+// closeParts closes the readers of the file parts that are io.Closers.
+func (res *formDataResponder) closeParts() {
+	for _, part := range res.parts {
+		if closer, ok := part.reader.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+// quoteEscaper escapes the characters that cannot appear unescaped in a quoted string.
+var quoteEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// escapeQuotes escapes s for a quoted Content-Disposition parameter.
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// WithBoundary sets the boundary instead of a random one, e.g. for reproducible output.
+func (res *formDataResponder) WithBoundary(boundary string) *formDataResponder {
+	res.boundary = boundary
+	return res
+}
+
+// WithLogger sets the logger for the responder.
+func (res *formDataResponder) WithLogger(logger httphandler.Logger) *formDataResponder {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *formDataResponder) WithStatus(status int) *formDataResponder {
+	res.statusCode = status
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *formDataResponder) WithHeader(key, value string) *formDataResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *formDataResponder) SetHeader(key, value string) *formDataResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *formDataResponder) WithCookie(cookie *http.Cookie) *formDataResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
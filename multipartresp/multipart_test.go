@@ -0,0 +1,241 @@
+package multipartresp_test
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/multipartresp"
+)
+
+type part struct {
+	name        string
+	filename    string
+	contentType string
+	header      map[string]string
+	body        string
+}
+
+// failingReader fails on the first read.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("boom")
+}
+
+// closeRecorder records whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestFormData_Respond(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantParts   []part
+		wantBody    string
+	}{
+		{
+			desc: "metadata and file",
+			given: multipartresp.FormData(
+				multipartresp.JSON("metadata", map[string]string{"id": "1"}),
+				multipartresp.File("file", "report.pdf", "application/pdf", strings.NewReader("%PDF-1.7")),
+			).WithBoundary("test-boundary"),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "multipart/form-data; boundary=test-boundary",
+			},
+			wantParts: []part{
+				{name: "metadata", contentType: "application/json", body: `{"id":"1"}`},
+				{name: "file", filename: "report.pdf", contentType: "application/pdf", body: "%PDF-1.7"},
+			},
+		},
+		{
+			desc: "part headers | with everything",
+			given: multipartresp.FormData(
+				multipartresp.Bytes("note", "text/plain", []byte("hello")).WithHeader("Content-Language", "en"),
+				multipartresp.File("file", `a "quoted" name.bin`, "", strings.NewReader("data")),
+			).WithStatus(http.StatusCreated).WithHeader("X-Test-1", "test value 1"),
+			wantCode: http.StatusCreated,
+			wantHeaders: map[string]string{
+				"X-Test-1": "test value 1",
+			},
+			wantParts: []part{
+				{name: "note", contentType: "text/plain", header: map[string]string{"Content-Language": "en"}, body: "hello"},
+				{name: "file", filename: `a "quoted" name.bin`, contentType: "application/octet-stream", body: "data"},
+			},
+		},
+		{
+			desc: "json encoding error",
+			given: multipartresp.FormData(
+				multipartresp.JSON("metadata", make(chan int)),
+			).WithHeader("X-Test-1", "test value 1"),
+			wantCode: http.StatusInternalServerError,
+			wantHeaders: map[string]string{
+				"X-Test-1": "test value 1",
+			},
+			wantBody: http.StatusText(http.StatusInternalServerError) + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given: a request and a recorder
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			w := httptest.NewRecorder()
+
+			// When: the responder responds
+			tc.given.Respond(w, r)
+
+			// Then: the status code and headers are as expected
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			for k, v := range tc.wantHeaders {
+				if got := w.Header().Get(k); got != v {
+					t.Errorf("header %q: want %q, got %q", k, v, got)
+				}
+			}
+
+			if tc.wantParts == nil {
+				if w.Body.String() != tc.wantBody {
+					t.Errorf("body: want %q, got %q", tc.wantBody, w.Body.String())
+				}
+				return
+			}
+
+			// Then: the parts are as expected
+			got := readParts(t, w.Header().Get("Content-Type"), w.Body)
+			if len(got) != len(tc.wantParts) {
+				t.Fatalf("parts: want %d, got %d", len(tc.wantParts), len(got))
+			}
+			for i, want := range tc.wantParts {
+				if got[i].name != want.name || got[i].filename != want.filename {
+					t.Errorf("part %d name: want %q %q, got %q %q", i, want.name, want.filename, got[i].name, got[i].filename)
+				}
+				if got[i].contentType != want.contentType {
+					t.Errorf("part %d content type: want %q, got %q", i, want.contentType, got[i].contentType)
+				}
+				for k, v := range want.header {
+					if got[i].header[k] != v {
+						t.Errorf("part %d header %q: want %q, got %q", i, k, v, got[i].header[k])
+					}
+				}
+				if got[i].body != want.body {
+					t.Errorf("part %d body: want %q, got %q", i, want.body, got[i].body)
+				}
+			}
+		})
+	}
+}
+
+func TestFormData_Truncated(t *testing.T) {
+	t.Parallel()
+
+	// Given: a file part that fails to read after the status code is sent
+	file := &closeRecorder{Reader: failingReader{}}
+	res := multipartresp.FormData(
+		multipartresp.JSON("metadata", "ok"),
+		multipartresp.File("file", "a.bin", "", file),
+	)
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	// When: the responder responds
+	res.Respond(w, r)
+
+	// Then: the status code is sent, the body has no closing boundary and the file is closed
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+	_, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("parse content type: %v", err)
+	}
+	if strings.Contains(w.Body.String(), "--"+params["boundary"]+"--") {
+		t.Errorf("body: want no closing boundary, got %q", w.Body.String())
+	}
+	if !file.closed {
+		t.Error("file: want closed")
+	}
+}
+
+func TestBoundary(t *testing.T) {
+	t.Parallel()
+
+	// When: two boundaries are generated
+	a, err := multipartresp.Boundary()
+	if err != nil {
+		t.Fatalf("boundary: %v", err)
+	}
+	b, err := multipartresp.Boundary()
+	if err != nil {
+		t.Fatalf("boundary: %v", err)
+	}
+
+	// Then: they are valid and differ
+	if err := multipart.NewWriter(io.Discard).SetBoundary(a); err != nil {
+		t.Errorf("set boundary %q: %v", a, err)
+	}
+	if a == b {
+		t.Errorf("boundary: want distinct values, got %q twice", a)
+	}
+}
+
+// readParts parses a multipart body with the boundary of contentType.
+func readParts(t *testing.T, contentType string, body io.Reader) []part {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("parse content type: %v", err)
+	}
+	if mediaType != multipartresp.ContentType {
+		t.Errorf("media type: want %q, got %q", multipartresp.ContentType, mediaType)
+	}
+
+	var parts []part
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		b, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		header := map[string]string{}
+		for k := range p.Header {
+			header[k] = p.Header.Get(k)
+		}
+		parts = append(parts, part{
+			name:        p.FormName(),
+			filename:    p.FileName(),
+			contentType: p.Header.Get("Content-Type"),
+			header:      header,
+			body:        string(b),
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package httphandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// NDJSONBody decodes a newline-delimited JSON (NDJSON / JSON Lines) request body lazily.
+// The returned iterator decodes one value at a time and passes it to yield, so bulk uploads
+// are never held in memory. It stops at the end of the body, at the first value that fails to
+// decode (wrapped in ErrJSONDecode), or at the first error returned by yield.
+// The iterator reads the body and can only be run once, from the handler.
+func NDJSONBody[T any](r *http.Request) (func(yield func(T) error) error, error) {
+	dec := json.NewDecoder(r.Body)
+
+	return func(yield func(T) error) error {
+		for record := 1; ; record++ {
+			var v T
+			if err := dec.Decode(&v); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				return fmt.Errorf("%w: record %d: %w", ErrJSONDecode, record, err)
+			}
+			if err := yield(v); err != nil {
+				return err
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,85 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestNDJSONBody(t *testing.T) {
+	t.Parallel()
+
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	errStop := errors.New("stop")
+
+	testCases := []struct {
+		desc      string
+		givenBody string
+		givenStop int
+		want      []item
+		wantErr   error
+	}{
+		{
+			desc:      "records",
+			givenBody: "{\"id\":1}\n{\"id\":2}\n\n{\"id\":3}\n",
+			want:      []item{{ID: 1}, {ID: 2}, {ID: 3}},
+		},
+		{
+			desc:      "empty body",
+			givenBody: "",
+		},
+		{
+			desc:      "invalid record",
+			givenBody: "{\"id\":1}\n{\"id\":\n",
+			want:      []item{{ID: 1}},
+			wantErr:   httphandler.ErrJSONDecode,
+		},
+		{
+			desc:      "yield error",
+			givenBody: "{\"id\":1}\n{\"id\":2}\n",
+			givenStop: 1,
+			want:      []item{{ID: 1}},
+			wantErr:   errStop,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.givenBody))
+			iter, err := httphandler.NDJSONBody[item](r)
+			if err != nil {
+				t.Fatalf("error: want nil, got %v", err)
+			}
+
+			// When:
+			var got []item
+			err = iter(func(v item) error {
+				got = append(got, v)
+				if len(got) == tc.givenStop {
+					return errStop
+				}
+				return nil
+			})
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("items: want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package httphandler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Ensure notModifiedResponder implements Responder.
+var _ Responder = (*notModifiedResponder)(nil)
+
+// NotModified creates a 304 Not Modified response, for a conditional request whose cached
+// representation is still current. The response has no body: the headers that describe one,
+// e.g. Content-Type and Content-Length, are removed, even if added with WithHeader.
+func NotModified() *notModifiedResponder {
+	return &notModifiedResponder{}
+}
+
+// bodyHeaders are the headers that describe a body, which a 304 response must not have.
+var bodyHeaders = []string{"Content-Type", "Content-Length", "Content-Encoding", "Transfer-Encoding"}
+
+// notModifiedResponder handles 304 Not Modified responses.
+type notModifiedResponder struct {
+	logger   Logger
+	header   http.Header
+	override http.Header
+	cookies  []*http.Cookie
+}
+
+// Respond sends the status code with custom headers and cookies, and no body.
+func (res *notModifiedResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	// A 304 response describes the cached representation and has no body, so the headers
+	// of a body would mislead caches.
+	header, override := res.header.Clone(), res.override.Clone()
+	for _, key := range bodyHeaders {
+		header.Del(key)
+		override.Del(key)
+		w.Header().Del(key)
+	}
+
+	// Apply cookies and custom headers when the status code is written.
+	w = deferHeaders(w, header, override, res.cookies)
+
+	w.WriteHeader(http.StatusNotModified)
+	LogResponse(res.logger, http.StatusNotModified)
+}
+
+// WithETag sets the entity tag of the cached representation, quoted if it is not already,
+// e.g. "v2" or W/"v2".
+func (res *notModifiedResponder) WithETag(etag string) *notModifiedResponder {
+	if !strings.HasSuffix(etag, `"`) || !(strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`)) {
+		etag = `"` + etag + `"`
+	}
+	return res.SetHeader("ETag", etag)
+}
+
+// WithLastModified sets the Last-Modified header of the cached representation.
+func (res *notModifiedResponder) WithLastModified(t time.Time) *notModifiedResponder {
+	return res.SetHeader("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
+
+// WithLogger sets the logger for the responder.
+func (res *notModifiedResponder) WithLogger(logger Logger) *notModifiedResponder {
+	res.logger = logger
+	return res
+}
+
+// WithHeader adds a custom header to the response, e.g. Cache-Control or Vary.
+func (res *notModifiedResponder) WithHeader(key, value string) *notModifiedResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader.
+func (res *notModifiedResponder) SetHeader(key, value string) *notModifiedResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *notModifiedResponder) WithCookie(cookie *http.Cookie) *notModifiedResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
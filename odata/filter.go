@@ -0,0 +1,307 @@
+package odata
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a node of a $filter expression: *Comparison, *Function, *Logical or *Not.
+type Expr interface {
+	expr()
+}
+
+// Comparison compares a field with a literal, e.g. "price gt 10".
+// Op is one of eq, ne, gt, ge, lt and le. Value is a string, float64, bool or nil.
+type Comparison struct {
+	Field string
+	Op    string
+	Value any
+}
+
+// Function is a string function applied to a field, e.g. "contains(name,'pro')".
+// Name is one of contains, startswith and endswith.
+type Function struct {
+	Name  string
+	Field string
+	Value string
+}
+
+// Logical combines two expressions with Op "and" or "or".
+type Logical struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+// Not negates an expression.
+type Not struct {
+	Expr Expr
+}
+
+func (*Comparison) expr() {}
+func (*Function) expr()   {}
+func (*Logical) expr()    {}
+func (*Not) expr()        {}
+
+var (
+	comparisonOps = map[string]bool{"eq": true, "ne": true, "gt": true, "ge": true, "lt": true, "le": true}
+	functions     = map[string]bool{"contains": true, "startswith": true, "endswith": true}
+)
+
+// token is a lexical token of a $filter expression.
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenLiteral
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// parser is a recursive descent parser for the supported $filter grammar:
+//
+//	or      = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = "not" unary | primary
+//	primary = "(" or ")" | function "(" field "," string ")" | field op literal
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	field  func(name string) (string, error)
+}
+
+// maxFilterDepth limits the nesting of "not" and parentheses in a $filter expression, so that
+// a crafted expression cannot exhaust the stack of the parser or of code walking the result.
+const maxFilterDepth = 32
+
+// parseFilter parses a $filter expression, translating field names with field.
+func parseFilter(s string, field func(name string) (string, error)) (Expr, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, field: field}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q", tok.text)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if tok := p.next(); tok.kind != kind {
+		return fmt.Errorf("want %s, got %q", text, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenIdent && p.peek().text == "or" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenIdent && p.peek().text == "and" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxFilterDepth {
+		return nil, fmt.Errorf("nested deeper than %d levels", maxFilterDepth)
+	}
+
+	if tok := p.peek(); tok.kind == tokenIdent && tok.text == "not" {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return expr, nil
+
+	case tok.kind == tokenIdent && functions[tok.text] && p.peek().kind == tokenLParen:
+		p.next()
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokenComma, `","`); err != nil {
+			return nil, err
+		}
+		arg := p.next()
+		value, ok := arg.value.(string)
+		if arg.kind != tokenLiteral || !ok {
+			return nil, fmt.Errorf("%s: want string, got %q", tok.text, arg.text)
+		}
+		if err := p.expect(tokenRParen, `")"`); err != nil {
+			return nil, err
+		}
+		return &Function{Name: tok.text, Field: field, Value: value}, nil
+
+	case tok.kind == tokenIdent:
+		p.pos--
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		op := p.next()
+		if op.kind != tokenIdent || !comparisonOps[op.text] {
+			return nil, fmt.Errorf("want comparison operator, got %q", op.text)
+		}
+		value := p.next()
+		if value.kind != tokenLiteral {
+			return nil, fmt.Errorf("want literal, got %q", value.text)
+		}
+		return &Comparison{Field: field, Op: op.text, Value: value.value}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+func (p *parser) parseField() (string, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return "", fmt.Errorf("want field, got %q", tok.text)
+	}
+	return p.field(tok.text)
+}
+
+// lex splits a $filter expression into tokens. The last token is always tokenEOF.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ","})
+			i++
+		case c == '\'':
+			// Strings are single-quoted; a quote inside is written twice.
+			var b strings.Builder
+			j := i + 1
+			for {
+				if j >= len(s) {
+					return nil, errors.New("unterminated string")
+				}
+				if s[j] == '\'' {
+					if j+1 < len(s) && s[j+1] == '\'' {
+						b.WriteByte('\'')
+						j += 2
+						continue
+					}
+					break
+				}
+				b.WriteByte(s[j])
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenLiteral, text: s[i : j+1], value: b.String()})
+			i = j + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(s) && (s[j] == '.' || s[j] == 'e' || s[j] == 'E' || (s[j] >= '0' && s[j] <= '9')) {
+				j++
+			}
+			n, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", s[i:j])
+			}
+			tokens = append(tokens, token{kind: tokenLiteral, text: s[i:j], value: n})
+			i = j
+		case unicode.IsLetter(rune(c)) || c == '_':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_' || s[j] == '/') {
+				j++
+			}
+			word := s[i:j]
+			switch word {
+			case "true", "false":
+				tokens = append(tokens, token{kind: tokenLiteral, text: word, value: word == "true"})
+			case "null":
+				tokens = append(tokens, token{kind: tokenLiteral, text: word})
+			default:
+				tokens = append(tokens, token{kind: tokenIdent, text: word})
+			}
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, text: "end of input"}), nil
+}
@@ -0,0 +1,121 @@
+package odata_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/odata"
+)
+
+func TestDecode_Filter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		given   string
+		want    odata.Expr
+		wantErr error
+	}{
+		{
+			desc:  "comparison | translated field",
+			given: "price gt 10.5",
+			want:  &odata.Comparison{Field: "unit_price", Op: "gt", Value: 10.5},
+		},
+		{
+			desc:  "string with quote",
+			given: "name eq 'O''Neil'",
+			want:  &odata.Comparison{Field: "name", Op: "eq", Value: "O'Neil"},
+		},
+		{
+			desc:  "null and bool literals",
+			given: "name eq null or id ne true",
+			want: &odata.Logical{
+				Op:    "or",
+				Left:  &odata.Comparison{Field: "name", Op: "eq", Value: nil},
+				Right: &odata.Comparison{Field: "id", Op: "ne", Value: true},
+			},
+		},
+		{
+			desc:  "precedence | and binds tighter than or",
+			given: "id eq 1 or id eq 2 and not contains(name,'pro')",
+			want: &odata.Logical{
+				Op:   "or",
+				Left: &odata.Comparison{Field: "id", Op: "eq", Value: 1.0},
+				Right: &odata.Logical{
+					Op:    "and",
+					Left:  &odata.Comparison{Field: "id", Op: "eq", Value: 2.0},
+					Right: &odata.Not{Expr: &odata.Function{Name: "contains", Field: "name", Value: "pro"}},
+				},
+			},
+		},
+		{
+			desc:  "parentheses",
+			given: "(id eq 1 or id eq 2) and startswith(name, 'a')",
+			want: &odata.Logical{
+				Op: "and",
+				Left: &odata.Logical{
+					Op:    "or",
+					Left:  &odata.Comparison{Field: "id", Op: "eq", Value: 1.0},
+					Right: &odata.Comparison{Field: "id", Op: "eq", Value: 2.0},
+				},
+				Right: &odata.Function{Name: "startswith", Field: "name", Value: "a"},
+			},
+		},
+		{
+			desc:    "unknown field",
+			given:   "secret eq 1",
+			wantErr: httphandler.ErrInvalidParam,
+		},
+		{
+			desc:    "unknown operator",
+			given:   "id like 1",
+			wantErr: httphandler.ErrInvalidParam,
+		},
+		{
+			desc:    "unterminated string",
+			given:   "name eq 'abc",
+			wantErr: httphandler.ErrInvalidParam,
+		},
+		{
+			desc:    "missing parenthesis",
+			given:   "(id eq 1",
+			wantErr: httphandler.ErrInvalidParam,
+		},
+		{
+			desc:  "nested parentheses",
+			given: strings.Repeat("(", 10) + "id eq 1" + strings.Repeat(")", 10),
+			want:  &odata.Comparison{Field: "id", Op: "eq", Value: 1.0},
+		},
+		{
+			desc:    "nested too deep",
+			given:   strings.Repeat("not (", 40) + "id eq 1" + strings.Repeat(")", 40),
+			wantErr: httphandler.ErrInvalidParam,
+		},
+		{
+			desc:    "trailing tokens",
+			given:   "id eq 1 id",
+			wantErr: httphandler.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got, err := odata.Decode(testConfig)(newRequest(map[string]string{"$filter": tc.given}))
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if !reflect.DeepEqual(got.Filter, tc.want) {
+				t.Errorf("filter: want %#v, got %#v", tc.want, got.Filter)
+			}
+		})
+	}
+}
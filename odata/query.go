@@ -0,0 +1,120 @@
+// Package odata decodes a subset of the OData query options ($filter, $select, $orderby,
+// $top and $skip) into typed structures.
+package odata
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// Query holds the decoded query options of a request.
+type Query struct {
+	// Filter is nil if $filter is absent.
+	Filter Expr
+	Select []string
+	// OrderBy lists the sort keys in order.
+	OrderBy []OrderBy
+	Top     int
+	Skip    int
+}
+
+// OrderBy is a sort key of $orderby.
+type OrderBy struct {
+	Field string
+	Desc  bool
+}
+
+// Config configures Decode.
+type Config struct {
+	// Fields lists the fields that can be used in $filter, $select and $orderby, mapped to the
+	// name they are translated to in Query, e.g. a column name. An empty value keeps the name.
+	// Any other field is rejected.
+	Fields map[string]string
+	// DefaultTop is used when $top is absent. Defaults to 20.
+	DefaultTop int
+	// MaxTop caps $top. Larger values are lowered to MaxTop. Defaults to 100.
+	MaxTop int
+}
+
+// Decode returns a RequestDecodeFunc that reads the OData query options of the request, e.g.
+// "?$filter=price gt 10 and contains(name,'pro')&$select=id,name&$orderby=price desc&$top=5".
+// Invalid options fail with an error wrapping httphandler.ErrInvalidParam.
+func Decode(cfg Config) httphandler.RequestDecodeFunc[Query] {
+	if cfg.DefaultTop <= 0 {
+		cfg.DefaultTop = 20
+	}
+	if cfg.MaxTop <= 0 {
+		cfg.MaxTop = 100
+	}
+
+	return func(r *http.Request) (Query, error) {
+		query := r.URL.Query()
+		q := Query{Top: cfg.DefaultTop}
+
+		if value := query.Get("$filter"); value != "" {
+			filter, err := parseFilter(value, cfg.field)
+			if err != nil {
+				return Query{}, fmt.Errorf("%w: $filter: %w", httphandler.ErrInvalidParam, err)
+			}
+			q.Filter = filter
+		}
+
+		if value := query.Get("$select"); value != "" {
+			for _, name := range strings.Split(value, ",") {
+				field, err := cfg.field(strings.TrimSpace(name))
+				if err != nil {
+					return Query{}, fmt.Errorf("%w: $select: %w", httphandler.ErrInvalidParam, err)
+				}
+				q.Select = append(q.Select, field)
+			}
+		}
+
+		if value := query.Get("$orderby"); value != "" {
+			for _, item := range strings.Split(value, ",") {
+				parts := strings.Fields(item)
+				if len(parts) == 0 || len(parts) > 2 || (len(parts) == 2 && parts[1] != "asc" && parts[1] != "desc") {
+					return Query{}, fmt.Errorf("%w: $orderby: %q", httphandler.ErrInvalidParam, item)
+				}
+				field, err := cfg.field(parts[0])
+				if err != nil {
+					return Query{}, fmt.Errorf("%w: $orderby: %w", httphandler.ErrInvalidParam, err)
+				}
+				q.OrderBy = append(q.OrderBy, OrderBy{Field: field, Desc: len(parts) == 2 && parts[1] == "desc"})
+			}
+		}
+
+		if value := query.Get("$top"); value != "" {
+			top, err := strconv.Atoi(value)
+			if err != nil || top < 0 {
+				return Query{}, fmt.Errorf("%w: $top: %q", httphandler.ErrInvalidParam, value)
+			}
+			q.Top = min(top, cfg.MaxTop)
+		}
+
+		if value := query.Get("$skip"); value != "" {
+			skip, err := strconv.Atoi(value)
+			if err != nil || skip < 0 {
+				return Query{}, fmt.Errorf("%w: $skip: %q", httphandler.ErrInvalidParam, value)
+			}
+			q.Skip = skip
+		}
+
+		return q, nil
+	}
+}
+
+// field returns the translated name of a whitelisted field.
+func (cfg Config) field(name string) (string, error) {
+	translated, ok := cfg.Fields[name]
+	if !ok {
+		return "", fmt.Errorf("unknown field %q", name)
+	}
+	if translated == "" {
+		return name, nil
+	}
+	return translated, nil
+}
@@ -0,0 +1,101 @@
+package odata_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/odata"
+)
+
+var testConfig = odata.Config{
+	Fields: map[string]string{
+		"id":    "",
+		"name":  "",
+		"price": "unit_price",
+	},
+	MaxTop: 50,
+}
+
+// newRequest returns a request with the given query options.
+func newRequest(options map[string]string) *http.Request {
+	query := url.Values{}
+	for key, value := range options {
+		query.Set(key, value)
+	}
+	return httptest.NewRequest(http.MethodGet, "/products?"+query.Encode(), nil)
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		given   map[string]string
+		want    odata.Query
+		wantErr error
+	}{
+		{
+			desc:  "defaults",
+			given: nil,
+			want:  odata.Query{Top: 20},
+		},
+		{
+			desc: "all options",
+			given: map[string]string{
+				"$select":  "id, name",
+				"$orderby": "price desc,name",
+				"$top":     "100",
+				"$skip":    "10",
+			},
+			want: odata.Query{
+				Select:  []string{"id", "name"},
+				OrderBy: []odata.OrderBy{{Field: "unit_price", Desc: true}, {Field: "name"}},
+				Top:     50,
+				Skip:    10,
+			},
+		},
+		{
+			desc:    "select | unknown field",
+			given:   map[string]string{"$select": "id,secret"},
+			wantErr: httphandler.ErrInvalidParam,
+		},
+		{
+			desc:    "orderby | invalid direction",
+			given:   map[string]string{"$orderby": "name up"},
+			wantErr: httphandler.ErrInvalidParam,
+		},
+		{
+			desc:    "top | negative",
+			given:   map[string]string{"$top": "-1"},
+			wantErr: httphandler.ErrInvalidParam,
+		},
+		{
+			desc:    "skip | not a number",
+			given:   map[string]string{"$skip": "ten"},
+			wantErr: httphandler.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got, err := odata.Decode(testConfig)(newRequest(tc.given))
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("query: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
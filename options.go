@@ -0,0 +1,197 @@
+package httphandler
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Ensure optionsResponder implements Responder.
+var _ Responder = (*optionsResponder)(nil)
+
+// RouteDescription describes the affordances of a resource route.
+type RouteDescription struct {
+	// Methods are the methods accepted by the route. OPTIONS is always added.
+	Methods []string `json:"methods"`
+	// Accept are the media types accepted in request bodies.
+	Accept []string `json:"accept,omitempty"`
+	// Schema describes the request body, e.g. as a JSON Schema document.
+	Schema any `json:"schema,omitempty"`
+	// Description is a human-readable description of the route.
+	Description string `json:"description,omitempty"`
+	// RateLimit is the rate-limit policy of the route, e.g. "100;w=60", also sent in the
+	// RateLimit-Policy header.
+	RateLimit string `json:"rateLimit,omitempty"`
+	// Scopes are the scopes the route requires.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// Options creates a response to an OPTIONS request that describes the route.
+// The accepted methods are listed in the Allow header, the accepted media types in the
+// Accept-Post header, the rate-limit policy in the RateLimit-Policy header, and the whole
+// description is rendered as a JSON body. See Catalog.MountOptions to describe every route of
+// a catalog without per-route code.
+func Options(desc RouteDescription) *optionsResponder {
+	if !slices.Contains(desc.Methods, http.MethodOptions) {
+		desc.Methods = append(slices.Clone(desc.Methods), http.MethodOptions)
+	}
+
+	return &optionsResponder{
+		desc: desc,
+	}
+}
+
+// MountOptions registers a handler on mux for OPTIONS requests to every path of the catalog,
+// e.g. "OPTIONS /users/{id}" for the routes "GET /users/{id}" and "DELETE /users/{id}", that
+// renders the description of the path, see Describe. Routes without a method, and paths that
+// have an OPTIONS route of their own, are left out.
+//
+// Documentation added later is described too, but paths added later are not registered.
+func (c *Catalog) MountOptions(mux *http.ServeMux) {
+	c.mu.RLock()
+	var paths []string
+	own := map[string]bool{}
+	for _, cr := range c.routes {
+		method, path, ok := strings.Cut(cr.route, " ")
+		if !ok {
+			continue
+		}
+		if method == http.MethodOptions {
+			own[path] = true
+		}
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, path := range paths {
+		if own[path] {
+			continue
+		}
+		mux.Handle(http.MethodOptions+" "+path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Options(c.Describe(path)).Respond(w, r)
+		}))
+	}
+}
+
+// Describe returns the description of path, e.g. "/users/{id}", built from the documentation
+// of the routes of the catalog for it: their methods, with HEAD for GET as http.ServeMux serves
+// it, the media types they accept and the scopes they require, and the first schema, rate-limit
+// policy and description, or else summary, that is set.
+func (c *Catalog) Describe(path string) RouteDescription {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var desc RouteDescription
+	for _, cr := range c.routes {
+		method, p, ok := strings.Cut(cr.route, " ")
+		if !ok || p != path {
+			continue
+		}
+
+		methods := []string{method}
+		if method == http.MethodGet {
+			methods = append(methods, http.MethodHead)
+		}
+		for _, m := range methods {
+			if !slices.Contains(desc.Methods, m) {
+				desc.Methods = append(desc.Methods, m)
+			}
+		}
+		for _, mediaType := range cr.doc.Accept {
+			if !slices.Contains(desc.Accept, mediaType) {
+				desc.Accept = append(desc.Accept, mediaType)
+			}
+		}
+		for _, scope := range cr.doc.Scopes {
+			if !slices.Contains(desc.Scopes, scope) {
+				desc.Scopes = append(desc.Scopes, scope)
+			}
+		}
+		if desc.Schema == nil {
+			desc.Schema = cr.doc.Schema
+		}
+		if desc.RateLimit == "" {
+			desc.RateLimit = cr.doc.RateLimit
+		}
+		if desc.Description == "" {
+			desc.Description = cmp.Or(cr.doc.Description, cr.doc.Summary)
+		}
+	}
+	return desc
+}
+
+// optionsResponder handles responses to OPTIONS requests.
+type optionsResponder struct {
+	logger   Logger
+	header   http.Header
+	override http.Header
+	cookies  []*http.Cookie
+	desc     RouteDescription
+}
+
+// Respond sends the route description with custom headers and cookies.
+func (res *optionsResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = deferHeaders(w, res.header, res.override, res.cookies)
+
+	// Advertise the affordances in headers.
+	w.Header().Set("Allow", strings.Join(res.desc.Methods, ", "))
+	if len(res.desc.Accept) > 0 {
+		w.Header().Set("Accept-Post", strings.Join(res.desc.Accept, ", "))
+	}
+	if res.desc.RateLimit != "" {
+		w.Header().Set("RateLimit-Policy", res.desc.RateLimit)
+	}
+
+	b, err := json.Marshal(res.desc)
+	if err != nil {
+		WriteInternalServerError(w, res.logger, err, "data", res.desc)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(b); err != nil {
+		WriteInternalServerError(w, res.logger, err, "response_body", string(b))
+		return
+	}
+
+	LogResponse(res.logger, http.StatusOK, "response_body", b)
+}
+
+// WithLogger sets the logger for the responder.
+func (res *optionsResponder) WithLogger(logger Logger) *optionsResponder {
+	res.logger = logger
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *optionsResponder) WithHeader(key, value string) *optionsResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *optionsResponder) SetHeader(key, value string) *optionsResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *optionsResponder) WithCookie(cookie *http.Cookie) *optionsResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
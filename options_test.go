@@ -0,0 +1,159 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestOptions_Respond(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			desc: "basic",
+			given: httphandler.Options(httphandler.RouteDescription{
+				Methods: []string{http.MethodGet},
+			}),
+			wantHeaders: map[string]string{
+				"Allow":        "GET, OPTIONS",
+				"Accept-Post":  "",
+				"Content-Type": "application/json",
+			},
+			wantBody: `{"methods":["GET","OPTIONS"]}`,
+		},
+		{
+			desc: "with everything",
+			given: httphandler.Options(httphandler.RouteDescription{
+				Methods:     []string{http.MethodGet, http.MethodPost, http.MethodOptions},
+				Accept:      []string{"application/json"},
+				Schema:      map[string]any{"type": "object"},
+				Description: "Users collection",
+				RateLimit:   "100;w=60",
+			}).WithHeader("X-Test-1", "test value 1"),
+			wantHeaders: map[string]string{
+				"Allow":            "GET, POST, OPTIONS",
+				"Accept-Post":      "application/json",
+				"RateLimit-Policy": "100;w=60",
+				"X-Test-1":         "test value 1",
+			},
+			wantBody: `{"methods":["GET","POST","OPTIONS"],"accept":["application/json"],` +
+				`"schema":{"type":"object"},"description":"Users collection","rateLimit":"100;w=60"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodOptions, "/users", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != http.StatusOK {
+				t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+			}
+
+			for key, wantValue := range tc.wantHeaders {
+				if gotValue := w.Header().Get(key); gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
+
+func TestCatalog_MountOptions(t *testing.T) {
+	t.Parallel()
+
+	// Given: routes documented with their handlers, and no OPTIONS handler
+	catalog := httphandler.NewCatalog()
+	nop := func(r *http.Request) httphandler.Responder { return nil }
+	httphandler.Handle(nop,
+		httphandler.WithCatalog(catalog, "GET /users"),
+		httphandler.WithSummary("List users"),
+		httphandler.WithRateLimit("100;w=60"),
+	)
+	httphandler.Handle(nop,
+		httphandler.WithCatalog(catalog, "POST /users"),
+		httphandler.WithDescription("Creates a user"),
+		httphandler.WithAccept("application/json"),
+		httphandler.WithSchema(map[string]any{"type": "object"}),
+	)
+	httphandler.Handle(nop, httphandler.WithCatalog(catalog, "DELETE /users/{id}"))
+	mux := http.NewServeMux()
+	catalog.MountOptions(mux)
+
+	testCases := []struct {
+		desc        string
+		givenPath   string
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			desc:      "documented routes",
+			givenPath: "/users",
+			wantHeaders: map[string]string{
+				"Allow":            "GET, HEAD, POST, OPTIONS",
+				"Accept-Post":      "application/json",
+				"RateLimit-Policy": "100;w=60",
+			},
+			wantBody: `{"methods":["GET","HEAD","POST","OPTIONS"],"accept":["application/json"],` +
+				`"schema":{"type":"object"},"description":"List users","rateLimit":"100;w=60"}`,
+		},
+		{
+			desc:      "undocumented route",
+			givenPath: "/users/42",
+			wantHeaders: map[string]string{
+				"Allow": "DELETE, OPTIONS",
+			},
+			wantBody: `{"methods":["DELETE","OPTIONS"]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodOptions, tc.givenPath, nil)
+
+			// When:
+			mux.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != http.StatusOK {
+				t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+			}
+
+			for key, wantValue := range tc.wantHeaders {
+				if gotValue := w.Header().Get(key); gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Pagination holds the pagination parameters of a list request.
+type Pagination struct {
+	Limit  int
+	Offset int
+	Cursor string
+	// Sort lists the sort fields in order; a "-" prefix means descending.
+	Sort []string
+}
+
+// PaginationConfig configures PaginationDecode.
+type PaginationConfig struct {
+	// DefaultLimit is used when the limit parameter is absent. Defaults to 20.
+	DefaultLimit int
+	// MaxLimit caps the limit parameter. Larger values are lowered to MaxLimit. Defaults to 100.
+	MaxLimit int
+	// DefaultSort is used when the sort parameter is absent.
+	DefaultSort []string
+	// SortFields lists the fields that can be sorted on. If empty, any field is allowed.
+	SortFields []string
+}
+
+// PaginationDecode returns a RequestDecodeFunc that reads the limit, offset, cursor and sort
+// query parameters, e.g. "?limit=50&offset=100&sort=name,-created_at".
+func PaginationDecode(cfg PaginationConfig) RequestDecodeFunc[Pagination] {
+	if cfg.DefaultLimit <= 0 {
+		cfg.DefaultLimit = 20
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = 100
+	}
+
+	return func(r *http.Request) (Pagination, error) {
+		query := r.URL.Query()
+		p := Pagination{
+			Limit:  cfg.DefaultLimit,
+			Cursor: query.Get("cursor"),
+			Sort:   cfg.DefaultSort,
+		}
+
+		if value := query.Get("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit <= 0 {
+				return Pagination{}, fmt.Errorf("%w: limit: %q", ErrInvalidParam, value)
+			}
+			p.Limit = min(limit, cfg.MaxLimit)
+		}
+
+		if value := query.Get("offset"); value != "" {
+			offset, err := strconv.Atoi(value)
+			if err != nil || offset < 0 {
+				return Pagination{}, fmt.Errorf("%w: offset: %q", ErrInvalidParam, value)
+			}
+			p.Offset = offset
+		}
+
+		if value := query.Get("sort"); value != "" {
+			p.Sort = nil
+			for _, field := range strings.Split(value, ",") {
+				field = strings.TrimSpace(field)
+				name := strings.TrimPrefix(field, "-")
+				if name == "" || (len(cfg.SortFields) > 0 && !slices.Contains(cfg.SortFields, name)) {
+					return Pagination{}, fmt.Errorf("%w: sort: %q", ErrInvalidParam, field)
+				}
+				p.Sort = append(p.Sort, field)
+			}
+		}
+
+		return p, nil
+	}
+}
@@ -0,0 +1,86 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestPaginationDecode(t *testing.T) {
+	t.Parallel()
+
+	cfg := httphandler.PaginationConfig{
+		DefaultLimit: 10,
+		MaxLimit:     50,
+		DefaultSort:  []string{"-created_at"},
+		SortFields:   []string{"name", "created_at"},
+	}
+
+	testCases := []struct {
+		desc     string
+		givenURL string
+		want     httphandler.Pagination
+		wantErr  error
+	}{
+		{
+			desc:     "defaults",
+			givenURL: "/",
+			want:     httphandler.Pagination{Limit: 10, Sort: []string{"-created_at"}},
+		},
+		{
+			desc:     "all parameters",
+			givenURL: "/?limit=25&offset=50&cursor=abc&sort=name,-created_at",
+			want: httphandler.Pagination{
+				Limit:  25,
+				Offset: 50,
+				Cursor: "abc",
+				Sort:   []string{"name", "-created_at"},
+			},
+		},
+		{
+			desc:     "limit over max",
+			givenURL: "/?limit=1000",
+			want:     httphandler.Pagination{Limit: 50, Sort: []string{"-created_at"}},
+		},
+		{
+			desc:     "invalid limit",
+			givenURL: "/?limit=0",
+			wantErr:  httphandler.ErrInvalidParam,
+		},
+		{
+			desc:     "invalid offset",
+			givenURL: "/?offset=-1",
+			wantErr:  httphandler.ErrInvalidParam,
+		},
+		{
+			desc:     "sort field not allowed",
+			givenURL: "/?sort=password",
+			wantErr:  httphandler.ErrInvalidParam,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, tc.givenURL, nil)
+
+			// When:
+			got, err := httphandler.PaginationDecode(cfg)(r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("value: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StageError is returned by the Combine, Extend and Parallel decoders when one of their
+// decoders fails.
+// Stage is the 1-based position of the failing decoder, matching the V1, V2, ... fields of the
+// tuple, so that error handlers can tell which decoder failed with errors.As.
+type StageError struct {
+	Stage int
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %d: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the error of the failing decoder, so that errors.Is and errors.As see it.
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// StageErrorHandler converts an error returned by a RequestDecodeFunc into a Responder, with
+// the 1-based position of the failing decoder if the error is a *StageError, or 0 otherwise.
+// The request allows for responses with request IDs, negotiated formats or localized messages.
+type StageErrorHandler func(r *http.Request, stage int, err error) Responder
+
+// WithStageErrorHandler is like WithDecodeErrorHandler, but the function also receives the
+// position of the failing decoder, see StageError.
+func WithStageErrorHandler(fn StageErrorHandler) HandlerOption {
+	return WithDecodeErrorHandler(func(r *http.Request, err error) Responder {
+		var stageErr *StageError
+		if errors.As(err, &stageErr) {
+			return fn(r, stageErr.Stage, stageErr.Err)
+		}
+		return fn(r, 0, err)
+	})
+}
+
+// Parallel2 is like Combine2, but runs the decoders concurrently with Gather, which cuts
+// latency when they are independent and each make network calls. The first error cancels the
+// context of the request passed to the other decoder and is returned as a *StageError.
+// At most one of the decoders may read the request body.
+func Parallel2[T1, T2 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2]) RequestDecodeFunc[Tuple2[T1, T2]] {
+	return ParallelWith2(d1, d2, func(v1 T1, v2 T2) Tuple2[T1, T2] {
+		return Tuple2[T1, T2]{V1: v1, V2: v2}
+	})
+}
+
+// ParallelWith2 is like Parallel2, but builds the input with the constructor function instead of a Tuple2.
+func ParallelWith2[T1, T2, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], build func(T1, T2) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		var (
+			v1 T1
+			v2 T2
+		)
+		err := Gather(r.Context(),
+			stage(r, 1, d1, &v1),
+			stage(r, 2, d2, &v2),
+		)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2), nil
+	}
+}
+
+// Parallel3 is like Parallel2 for three decoders.
+func Parallel3[T1, T2, T3 any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3]) RequestDecodeFunc[Tuple3[T1, T2, T3]] {
+	return ParallelWith3(d1, d2, d3, func(v1 T1, v2 T2, v3 T3) Tuple3[T1, T2, T3] {
+		return Tuple3[T1, T2, T3]{V1: v1, V2: v2, V3: v3}
+	})
+}
+
+// ParallelWith3 is like Parallel3, but builds the input with the constructor function instead of a Tuple3.
+func ParallelWith3[T1, T2, T3, R any](d1 RequestDecodeFunc[T1], d2 RequestDecodeFunc[T2], d3 RequestDecodeFunc[T3], build func(T1, T2, T3) R) RequestDecodeFunc[R] {
+	return func(r *http.Request) (R, error) {
+		var (
+			v1 T1
+			v2 T2
+			v3 T3
+		)
+		err := Gather(r.Context(),
+			stage(r, 1, d1, &v1),
+			stage(r, 2, d2, &v2),
+			stage(r, 3, d3, &v3),
+		)
+		if err != nil {
+			var v R
+			return v, err
+		}
+
+		return build(v1, v2, v3), nil
+	}
+}
+
+// stage returns a func for Gather that runs decode with the request context replaced by the
+// context of Gather and stores the value in dst.
+func stage[T any](r *http.Request, index int, decode RequestDecodeFunc[T], dst *T) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		v, err := runStage(r.WithContext(ctx), index, decode)
+		if err != nil {
+			return err
+		}
+		*dst = v
+		return nil
+	}
+}
@@ -0,0 +1,179 @@
+package httphandler_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// slowDecode returns a decoder that waits for d or the request context, whichever is first.
+func slowDecode(d time.Duration, v string) httphandler.RequestDecodeFunc[string] {
+	return func(r *http.Request) (string, error) {
+		select {
+		case <-time.After(d):
+			return v, nil
+		case <-r.Context().Done():
+			return "", r.Context().Err()
+		}
+	}
+}
+
+func TestParallel3(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc      string
+		given     *http.Request
+		want      httphandler.Tuple3[string, string, string]
+		wantStage int
+	}{
+		{
+			desc: "all decoded",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/?sort=name", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			}(),
+			want: httphandler.Tuple3[string, string, string]{V1: "acme", V2: "name", V3: "slow"},
+		},
+		{
+			desc:      "first decoder fails",
+			given:     httptest.NewRequest(http.MethodGet, "/?sort=name", nil),
+			wantStage: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			decode := httphandler.Parallel3(headerDecode("X-Tenant"), queryDecode("sort"), slowDecode(10*time.Millisecond, "slow"))
+
+			// When:
+			got, err := decode(tc.given)
+
+			// Then:
+			var stageErr *httphandler.StageError
+			if tc.wantStage == 0 {
+				if err != nil {
+					t.Errorf("error: want nil, got %v", err)
+				}
+			} else if !errors.As(err, &stageErr) || stageErr.Stage != tc.wantStage {
+				t.Errorf("error: want stage %d, got %v", tc.wantStage, err)
+			}
+
+			if got != tc.want {
+				t.Errorf("value: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParallelWith2_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	// Given: two decoders that each take 50ms
+	decode := httphandler.ParallelWith2(slowDecode(50*time.Millisecond, "a"), slowDecode(50*time.Millisecond, "b"), func(a, b string) string {
+		return a + b
+	})
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// When:
+	start := time.Now()
+	got, err := decode(r)
+	elapsed := time.Since(start)
+
+	// Then: they run concurrently
+	if err != nil {
+		t.Fatalf("error: want nil, got %v", err)
+	}
+	if got != "ab" {
+		t.Errorf("value: want %q, got %q", "ab", got)
+	}
+	if elapsed >= 100*time.Millisecond {
+		t.Errorf("elapsed: want < 100ms, got %v", elapsed)
+	}
+}
+
+func TestParallel2_CancelsOnError(t *testing.T) {
+	t.Parallel()
+
+	// Given: a failing decoder and a slow one
+	decode := httphandler.Parallel2(headerDecode("X-Tenant"), slowDecode(time.Minute, "slow"))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// When:
+	_, err := decode(r)
+
+	// Then: the failure is returned and the slow decoder is cancelled
+	var stageErr *httphandler.StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != 1 {
+		t.Errorf("error: want stage 1, got %v", err)
+	}
+}
+
+func TestWithStageErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		given    httphandler.RequestDecodeFunc[httphandler.Tuple2[string, string]]
+		wantBody string
+	}{
+		{
+			desc:     "stage error",
+			given:    httphandler.Parallel2(queryDecode("sort"), headerDecode("X-Tenant")),
+			wantBody: "req-1: stage 2: missing header X-Tenant",
+		},
+		{
+			desc:     "combine error | stage 2",
+			given:    httphandler.Combine2(queryDecode("sort"), headerDecode("X-Tenant")),
+			wantBody: "req-1: stage 2: missing header X-Tenant",
+		},
+		{
+			desc: "other error | stage 0",
+			given: func(r *http.Request) (httphandler.Tuple2[string, string], error) {
+				return httphandler.Tuple2[string, string]{}, errors.New("missing header X-Tenant")
+			},
+			wantBody: "req-1: stage 0: missing header X-Tenant",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			h := httphandler.HandleWithDecoder(tc.given, func(r *http.Request, input httphandler.Tuple2[string, string]) httphandler.Responder {
+				return nil
+			},
+				httphandler.WithStageErrorHandler(func(r *http.Request, stage int, err error) httphandler.Responder {
+					return httphandler.ResponderFunc(func(w http.ResponseWriter, _ *http.Request) {
+						w.WriteHeader(http.StatusBadRequest)
+						fmt.Fprintf(w, "%s: stage %d: %v", r.Header.Get("X-Request-ID"), stage, err)
+					})
+				}),
+			)
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("X-Request-ID", "req-1")
+			w := httptest.NewRecorder()
+
+			// When:
+			h(w, r)
+
+			// Then:
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status code: want %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			if w.Body.String() != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package httphandler
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// RoutePipeline describes how a route of a Catalog decodes its requests.
+type RoutePipeline struct {
+	Route string
+	// Input is the type of the decoded input, or empty if the handler has none.
+	Input string
+	// Stages lists the stages of the decoder: their names, set with WithStageNames, and their
+	// types if the input is a TupleN.
+	Stages []string
+	// DecodeErrorHandler is the name of the function that renders decoding errors.
+	DecodeErrorHandler string
+}
+
+// pipeline describes the decoding of a handler with these options.
+func (o handlerOptions) pipeline() *RoutePipeline {
+	p := &RoutePipeline{Route: o.route}
+	if o.input == nil {
+		return p
+	}
+	p.Input = o.input.String()
+	p.DecodeErrorHandler = funcName(o.decodeErrorHandler)
+
+	var types []string
+	if o.input.Kind() == reflect.Struct && o.input.PkgPath() == reflect.TypeFor[handlerOptions]().PkgPath() &&
+		strings.HasPrefix(o.input.Name(), "Tuple") {
+		for i := 0; i < o.input.NumField(); i++ {
+			types = append(types, o.input.Field(i).Type.String())
+		}
+	}
+	for i := 0; i < max(len(o.stageNames), len(types)); i++ {
+		var label []string
+		if i < len(o.stageNames) {
+			label = append(label, o.stageNames[i])
+		}
+		if i < len(types) {
+			label = append(label, types[i])
+		}
+		p.Stages = append(p.Stages, strings.Join(label, ": "))
+	}
+	return p
+}
+
+// funcName returns the name of fn with the last element of its import path only, e.g.
+// "go-httphandler.defaultDecodeErrorHandler".
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return ""
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return ""
+	}
+	name := f.Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// Pipelines returns how the routes of the catalog decode their requests, in the order they
+// were added. Only handlers created with WithCatalog are described.
+func (c *Catalog) Pipelines() []RoutePipeline {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	pipelines := make([]RoutePipeline, 0, len(c.routes))
+	for _, cr := range c.routes {
+		if cr.pipeline != nil {
+			pipelines = append(pipelines, *cr.pipeline)
+		}
+	}
+	return pipelines
+}
+
+// DOT renders the pipelines of the catalog as a Graphviz graph, e.g. for architecture reviews.
+// Stages and decode error handlers with the same name are drawn once, so that the routes that
+// share them stand out.
+func (c *Catalog) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph pipelines {\n\trankdir=LR;\n")
+	c.graph(
+		func(id, label string) { fmt.Fprintf(&b, "\t%s [label=%q, shape=box];\n", id, label) },
+		func(id, label string) { fmt.Fprintf(&b, "\t%s [label=%q];\n", id, label) },
+		func(id, label string) { fmt.Fprintf(&b, "\t%s [label=%q, shape=note];\n", id, label) },
+		func(from, to, label string) { fmt.Fprintf(&b, "\t%s -> %s [label=%q];\n", from, to, label) },
+	)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the pipelines of the catalog as a Mermaid flowchart, see DOT.
+func (c *Catalog) Mermaid() string {
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	c.graph(
+		func(id, label string) { fmt.Fprintf(&b, "\t%s[%s]\n", id, quote(label)) },
+		func(id, label string) { fmt.Fprintf(&b, "\t%s([%s])\n", id, quote(label)) },
+		func(id, label string) { fmt.Fprintf(&b, "\t%s>%s]\n", id, quote(label)) },
+		func(from, to, label string) { fmt.Fprintf(&b, "\t%s -->|%s| %s\n", from, quote(label), to) },
+	)
+	return b.String()
+}
+
+// graph walks the pipelines of the catalog, declaring each route, stage and decode error
+// handler node once, then the edges from the routes.
+func (c *Catalog) graph(route, stage, handler func(id, label string), edge func(from, to, label string)) {
+	pipelines := c.Pipelines()
+
+	stages := map[string]string{}
+	handlers := map[string]string{}
+	for i, p := range pipelines {
+		route(fmt.Sprintf("r%d", i), p.Route)
+		for _, s := range p.Stages {
+			if _, ok := stages[s]; !ok {
+				stages[s] = fmt.Sprintf("s%d", len(stages))
+				stage(stages[s], s)
+			}
+		}
+		if h := p.DecodeErrorHandler; h != "" {
+			if _, ok := handlers[h]; !ok {
+				handlers[h] = fmt.Sprintf("e%d", len(handlers))
+				handler(handlers[h], h)
+			}
+		}
+	}
+
+	for i, p := range pipelines {
+		from := fmt.Sprintf("r%d", i)
+		for j, s := range p.Stages {
+			edge(from, stages[s], fmt.Sprint(j+1))
+		}
+		if h := p.DecodeErrorHandler; h != "" {
+			edge(from, handlers[h], "decode error")
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func newPipelineCatalog() *httphandler.Catalog {
+	catalog := httphandler.NewCatalog()
+
+	httphandler.HandleWithDecoder(httphandler.Combine2(headerDecode("X-Tenant"), queryDecode("id")), func(r *http.Request, input httphandler.Tuple2[string, string]) httphandler.Responder {
+		return nil
+	},
+		httphandler.WithCatalog(catalog, "GET /users/{id}"),
+		httphandler.WithStageNames("tenant", "user"),
+	)
+	httphandler.HandleWithDecoder(httphandler.Combine2(headerDecode("X-Tenant"), queryDecode("id")), func(r *http.Request, input httphandler.Tuple2[string, string]) httphandler.Responder {
+		return nil
+	},
+		httphandler.WithCatalog(catalog, "GET /products/{id}"),
+		httphandler.WithStageNames("tenant", "product"),
+	)
+	httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return nil
+	}, httphandler.WithCatalog(catalog, "GET /health"))
+
+	return catalog
+}
+
+func TestCatalog_Pipelines(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	catalog := newPipelineCatalog()
+
+	// When:
+	got := catalog.Pipelines()
+
+	// Then:
+	want := []httphandler.RoutePipeline{
+		{
+			Route:              "GET /users/{id}",
+			Input:              "httphandler.Tuple2[string,string]",
+			Stages:             []string{"tenant: string", "user: string"},
+			DecodeErrorHandler: "go-httphandler.defaultDecodeErrorHandler",
+		},
+		{
+			Route:              "GET /products/{id}",
+			Input:              "httphandler.Tuple2[string,string]",
+			Stages:             []string{"tenant: string", "product: string"},
+			DecodeErrorHandler: "go-httphandler.defaultDecodeErrorHandler",
+		},
+		{
+			Route: "GET /health",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pipelines: want %+v, got %+v", want, got)
+	}
+}
+
+func TestCatalog_DOT(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	catalog := newPipelineCatalog()
+
+	// When:
+	got := catalog.DOT()
+
+	// Then: the tenant stage and the decode error handler are shared
+	want := `digraph pipelines {
+	rankdir=LR;
+	r0 [label="GET /users/{id}", shape=box];
+	s0 [label="tenant: string"];
+	s1 [label="user: string"];
+	e0 [label="go-httphandler.defaultDecodeErrorHandler", shape=note];
+	r1 [label="GET /products/{id}", shape=box];
+	s2 [label="product: string"];
+	r2 [label="GET /health", shape=box];
+	r0 -> s0 [label="1"];
+	r0 -> s1 [label="2"];
+	r0 -> e0 [label="decode error"];
+	r1 -> s0 [label="1"];
+	r1 -> s2 [label="2"];
+	r1 -> e0 [label="decode error"];
+}
+`
+	if got != want {
+		t.Errorf("dot: want\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestCatalog_Mermaid(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	catalog := newPipelineCatalog()
+
+	// When:
+	got := catalog.Mermaid()
+
+	// Then:
+	want := `flowchart LR
+	r0["GET /users/{id}"]
+	s0(["tenant: string"])
+	s1(["user: string"])
+	e0>"go-httphandler.defaultDecodeErrorHandler"]
+	r1["GET /products/{id}"]
+	s2(["product: string"])
+	r2["GET /health"]
+	r0 -->|"1"| s0
+	r0 -->|"2"| s1
+	r0 -->|"decode error"| e0
+	r1 -->|"1"| s0
+	r1 -->|"2"| s2
+	r1 -->|"decode error"| e0
+`
+	if got != want {
+		t.Errorf("mermaid: want\n%s\ngot\n%s", want, got)
+	}
+}
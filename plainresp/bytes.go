@@ -0,0 +1,94 @@
+package plainresp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// Ensure bytesResponder implements Responder.
+var _ httphandler.Responder = (*bytesResponder)(nil)
+
+// bytesResponder manages HTTP responses with a binary body.
+type bytesResponder struct {
+	logger      httphandler.Logger
+	header      http.Header
+	override    http.Header
+	statusCode  int
+	cookies     []*http.Cookie
+	body        []byte
+	contentType string
+}
+
+// Bytes creates a new bytesResponder that sends b as is with the given Content-Type, or
+// application/octet-stream if it is empty, and a 200 OK status. It is meant for small binary
+// bodies such as QR codes or tokens, which are sent inline rather than as a download.
+func Bytes(b []byte, contentType string) *bytesResponder {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &bytesResponder{
+		statusCode:  http.StatusOK,
+		body:        b,
+		contentType: contentType,
+	}
+}
+
+// Respond sends the response with custom headers, cookies and status code.
+func (res *bytesResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	w.Header().Set("Content-Type", res.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(res.body)))
+
+	// Set response body and status code.
+	w.WriteHeader(res.statusCode)
+	if _, err := w.Write(res.body); err != nil {
+		httphandler.LogRequestError(res.logger, err, "status_code", res.statusCode)
+		return
+	}
+
+	httphandler.LogResponse(res.logger, res.statusCode, "content_type", res.contentType, "bytes", len(res.body))
+}
+
+// WithLogger sets the logger for the responder.
+func (res *bytesResponder) WithLogger(logger httphandler.Logger) *bytesResponder {
+	res.logger = logger
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *bytesResponder) WithHeader(key, value string) *bytesResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *bytesResponder) SetHeader(key, value string) *bytesResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *bytesResponder) WithStatus(status int) *bytesResponder {
+	res.statusCode = status
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *bytesResponder) WithCookie(cookie *http.Cookie) *bytesResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
@@ -0,0 +1,93 @@
+package plainresp_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/plainresp"
+)
+
+func TestBytes_Respond(t *testing.T) {
+	t.Parallel()
+
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n', 0x00}
+	cookie := &http.Cookie{Name: "test-cookie-1", Value: "cookie-value-1"}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantCookies int
+		wantBody    []byte
+	}{
+		{
+			desc:     "binary",
+			given:    plainresp.Bytes(png, "image/png"),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":   "image/png",
+				"Content-Length": "9",
+			},
+			wantBody: png,
+		},
+		{
+			desc:     "default content type",
+			given:    plainresp.Bytes([]byte("token"), ""),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":   "application/octet-stream",
+				"Content-Length": "5",
+			},
+			wantBody: []byte("token"),
+		},
+		{
+			desc: "empty | with everything",
+			given: plainresp.Bytes(nil, "image/png").
+				WithStatus(http.StatusCreated).
+				WithHeader("X-Test-1", "test value 1").
+				SetHeader("Content-Type", "image/x-png").
+				WithCookie(cookie),
+			wantCode: http.StatusCreated,
+			wantHeaders: map[string]string{
+				"Content-Type":   "image/x-png",
+				"Content-Length": "0",
+				"X-Test-1":       "test value 1",
+			},
+			wantCookies: 1,
+			wantBody:    []byte{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			for key, want := range tc.wantHeaders {
+				if got := w.Header().Get(key); got != want {
+					t.Errorf("header %s: want %q, got %q", key, want, got)
+				}
+			}
+			if got := len(w.Result().Cookies()); got != tc.wantCookies {
+				t.Errorf("cookie count: want %d, got %d", tc.wantCookies, got)
+			}
+			if got := w.Body.Bytes(); !bytes.Equal(got, tc.wantBody) {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
 )
 
 // Ensure errorResponder implements Responder.
@@ -13,6 +14,7 @@ var _ httphandler.Responder = (*errorResponder)(nil)
 type errorResponder struct {
 	logger     httphandler.Logger
 	header     http.Header
+	override   http.Header
 	statusCode int
 	cookies    []*http.Cookie
 	errMessage string
@@ -40,18 +42,10 @@ func InternalServerError(err error) *errorResponder {
 }
 
 // Respond sends the response with custom headers, cookies and status code.
-func (res *errorResponder) Respond(w http.ResponseWriter, _ *http.Request) {
-	// Set cookies.
-	for _, cookie := range res.cookies {
-		http.SetCookie(w, cookie)
-	}
-
-	// Add custom headers.
-	for key, values := range res.header {
-		for _, value := range values {
-			w.Header().Add(key, value)
-		}
-	}
+func (res *errorResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+	httphandler.RecordError(r, res.err)
 
 	// Set response body and status code.
 	http.Error(w, res.errMessage, res.statusCode)
@@ -64,7 +58,8 @@ func (res *errorResponder) WithLogger(logger httphandler.Logger) *errorResponder
 	return res
 }
 
-// WithHeader adds a custom header to the response.
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
 func (res *errorResponder) WithHeader(key, value string) *errorResponder {
 	if res.header == nil {
 		res.header = http.Header{}
@@ -73,6 +68,17 @@ func (res *errorResponder) WithHeader(key, value string) *errorResponder {
 	return res
 }
 
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *errorResponder) SetHeader(key, value string) *errorResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
 // WithCookie adds a cookie to the response.
 func (res *errorResponder) WithCookie(cookie *http.Cookie) *errorResponder {
 	res.cookies = append(res.cookies, cookie)
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
 )
 
 // Ensure successResponder implements Responder.
@@ -13,9 +14,11 @@ var _ httphandler.Responder = (*successResponder)(nil)
 type successResponder struct {
 	logger     httphandler.Logger
 	header     http.Header
+	override   http.Header
 	statusCode int
 	cookies    []*http.Cookie
 	body       string
+	charsets   []responder.Charset
 }
 
 // Success creates a new successResponder with data and a 200 OK status.
@@ -28,21 +31,25 @@ func Success(data string) *successResponder {
 
 // Respond sends the response with custom headers, cookies and status code.
 func (res *successResponder) Respond(w http.ResponseWriter, r *http.Request) {
-	// Set cookies.
-	for _, cookie := range res.cookies {
-		http.SetCookie(w, cookie)
-	}
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
 
-	// Add custom headers.
-	for key, values := range res.header {
-		for _, value := range values {
-			w.Header().Add(key, value)
+	// Declare the charset only when one is chosen, keeping a Content-Type added with WithHeader.
+	charset := responder.NegotiateCharset(r, res.charsets...)
+	if len(res.charsets) > 1 {
+		w.Header().Add("Vary", "Accept-Charset")
+	}
+	if len(res.charsets) > 0 {
+		contentType := res.header.Get("Content-Type")
+		if contentType == "" {
+			contentType = "text/plain"
 		}
+		w.Header().Set("Content-Type", responder.ContentType(contentType, charset))
 	}
 
 	// Set response body and status code.
 	w.WriteHeader(res.statusCode)
-	if _, err := w.Write([]byte(res.body)); err != nil {
+	if _, err := responder.CharsetWriter(w, charset).Write([]byte(res.body)); err != nil {
 		httphandler.WriteInternalServerError(w, res.logger, err)
 		return
 	}
@@ -50,13 +57,22 @@ func (res *successResponder) Respond(w http.ResponseWriter, r *http.Request) {
 	httphandler.LogResponse(res.logger, res.statusCode, "response_body", res.body)
 }
 
+// WithCharset sets the charset of the response, UTF-8 by default. If several charsets are
+// given, the one preferred by the Accept-Charset header of the request is used. The charset
+// is declared in the Content-Type header, text/plain unless one is added with WithHeader.
+func (res *successResponder) WithCharset(charsets ...responder.Charset) *successResponder {
+	res.charsets = charsets
+	return res
+}
+
 // WithLogger sets the logger for the responder.
 func (res *successResponder) WithLogger(logger httphandler.Logger) *successResponder {
 	res.logger = logger
 	return res
 }
 
-// WithHeader adds a custom header to the response.
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
 func (res *successResponder) WithHeader(key, value string) *successResponder {
 	if res.header == nil {
 		res.header = http.Header{}
@@ -65,6 +81,17 @@ func (res *successResponder) WithHeader(key, value string) *successResponder {
 	return res
 }
 
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *successResponder) SetHeader(key, value string) *successResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
 // WithStatus sets a custom HTTP status code for the response.
 func (res *successResponder) WithStatus(status int) *successResponder {
 	res.statusCode = status
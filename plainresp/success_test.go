@@ -8,6 +8,7 @@ import (
 
 	"github.com/alvinchoong/go-httphandler"
 	"github.com/alvinchoong/go-httphandler/plainresp"
+	"github.com/alvinchoong/go-httphandler/responder"
 )
 
 func TestSuccess_Respond(t *testing.T) {
@@ -19,12 +20,13 @@ func TestSuccess_Respond(t *testing.T) {
 	}
 
 	testCases := []struct {
-		desc        string
-		given       httphandler.Responder
-		wantCode    int
-		wantHeaders map[string]string
-		wantCookies []*http.Cookie
-		wantBody    string
+		desc               string
+		given              httphandler.Responder
+		givenAcceptCharset string
+		wantCode           int
+		wantHeaders        map[string]string
+		wantCookies        []*http.Cookie
+		wantBody           string
 	}{
 		{
 			desc:        "basic",
@@ -34,6 +36,17 @@ func TestSuccess_Respond(t *testing.T) {
 			wantCookies: nil,
 			wantBody:    "Success",
 		},
+		{
+			desc:               "negotiated charset",
+			given:              plainresp.Success("hé").WithCharset(responder.UTF8, responder.UTF16LE),
+			givenAcceptCharset: "utf-16le, utf-8;q=0.5",
+			wantCode:           http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "text/plain; charset=utf-16le",
+				"Vary":         "Accept-Charset",
+			},
+			wantBody: "\xff\xfeh\x00\xe9\x00",
+		},
 		{
 			desc: "with extra headers | with status",
 			given: plainresp.Success("OK").
@@ -47,6 +60,24 @@ func TestSuccess_Respond(t *testing.T) {
 			wantCookies: []*http.Cookie{cookie},
 			wantBody:    "OK",
 		},
+		{
+			desc:     "with content type header",
+			given:    plainresp.Success("# Title").WithHeader("Content-Type", "text/markdown"),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "text/markdown",
+			},
+			wantBody: "# Title",
+		},
+		{
+			desc:     "with content type header | with charset",
+			given:    plainresp.Success("# Title").WithHeader("Content-Type", "text/markdown").WithCharset(responder.UTF8),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type": "text/markdown; charset=utf-8",
+			},
+			wantBody: "# Title",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -56,6 +87,9 @@ func TestSuccess_Respond(t *testing.T) {
 			// Given:
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.givenAcceptCharset != "" {
+				r.Header.Set("Accept-Charset", tc.givenAcceptCharset)
+			}
 
 			// When:
 			tc.given.Respond(w, r)
@@ -0,0 +1,90 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Preferences holds the client preferences sent in the Prefer header (RFC 7240).
+type Preferences struct {
+	// Return is the value of the "return" preference, either "minimal" or "representation".
+	Return string
+	// RespondAsync reports whether the client sent the "respond-async" preference.
+	RespondAsync bool
+	// Wait is the value of the "wait" preference.
+	Wait time.Duration
+	// Handling is the value of the "handling" preference, either "strict" or "lenient".
+	Handling string
+	// Extra holds any other preferences keyed by their lower-cased name.
+	Extra map[string]string
+}
+
+// ParsePrefer parses all Prefer headers of the request.
+// Unknown preferences are kept in Extra, and malformed values are ignored.
+func ParsePrefer(r *http.Request) Preferences {
+	var p Preferences
+	for _, header := range r.Header.Values("Prefer") {
+		for _, pref := range strings.Split(header, ",") {
+			// Preference parameters after ';' are not used by any registered preference.
+			pref, _, _ = strings.Cut(pref, ";")
+			name, value, _ := strings.Cut(pref, "=")
+			name = strings.ToLower(strings.TrimSpace(name))
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+
+			switch name {
+			case "":
+				continue
+			case "return":
+				p.Return = strings.ToLower(value)
+			case "respond-async":
+				p.RespondAsync = true
+			case "wait":
+				if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+					p.Wait = time.Duration(seconds) * time.Second
+				}
+			case "handling":
+				p.Handling = strings.ToLower(value)
+			default:
+				if p.Extra == nil {
+					p.Extra = map[string]string{}
+				}
+				p.Extra[name] = value
+			}
+		}
+	}
+
+	return p
+}
+
+// PreferDecode is a RequestDecodeFunc that parses the Prefer header.
+func PreferDecode(r *http.Request) (Preferences, error) {
+	return ParsePrefer(r), nil
+}
+
+// Ensure preferenceAppliedResponder implements Responder.
+var _ Responder = (*preferenceAppliedResponder)(nil)
+
+// WithPreferenceApplied wraps a Responder so that the response includes a Preference-Applied header
+// listing the preferences that were honored (e.g. "return=minimal").
+func WithPreferenceApplied(res Responder, prefs ...string) Responder {
+	return &preferenceAppliedResponder{
+		responder: res,
+		prefs:     prefs,
+	}
+}
+
+// preferenceAppliedResponder adds the Preference-Applied header before delegating to another Responder.
+type preferenceAppliedResponder struct {
+	responder Responder
+	prefs     []string
+}
+
+// Respond sets the Preference-Applied header and delegates to the wrapped Responder.
+func (res *preferenceAppliedResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	if len(res.prefs) > 0 {
+		w.Header().Set("Preference-Applied", strings.Join(res.prefs, ", "))
+	}
+	res.responder.Respond(w, r)
+}
@@ -0,0 +1,90 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestParsePrefer(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc  string
+		given []string
+		want  httphandler.Preferences
+	}{
+		{
+			desc:  "no header",
+			given: nil,
+			want:  httphandler.Preferences{},
+		},
+		{
+			desc:  "single header",
+			given: []string{"return=minimal"},
+			want:  httphandler.Preferences{Return: "minimal"},
+		},
+		{
+			desc:  "multiple preferences",
+			given: []string{`return="representation", respond-async, wait=10`},
+			want: httphandler.Preferences{
+				Return:       "representation",
+				RespondAsync: true,
+				Wait:         10 * time.Second,
+			},
+		},
+		{
+			desc:  "multiple headers | parameters | unknown",
+			given: []string{"handling=Strict; foo=bar", "Priority=5", "wait=abc"},
+			want: httphandler.Preferences{
+				Handling: "strict",
+				Extra:    map[string]string{"priority": "5"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, v := range tc.given {
+				r.Header.Add("Prefer", v)
+			}
+
+			// When:
+			got := httphandler.ParsePrefer(r)
+
+			// Then:
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("preferences: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestWithPreferenceApplied(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	given := httphandler.WithPreferenceApplied(&mockResponder{StatusCode: http.StatusOK, Body: "OK"}, "return=minimal")
+
+	// When:
+	given.Respond(w, r)
+
+	// Then:
+	if got := w.Header().Get("Preference-Applied"); got != "return=minimal" {
+		t.Errorf("Preference-Applied: want %q, got %q", "return=minimal", got)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, w.Code)
+	}
+}
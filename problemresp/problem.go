@@ -0,0 +1,247 @@
+package problemresp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// ContentType is the media type of Problem Details documents.
+const ContentType = "application/problem+json"
+
+// Ensure problemResponder implements Responder.
+var _ httphandler.Responder = (*problemResponder)(nil)
+
+// Problem is a Problem Details document as defined by RFC 9457.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// MarshalJSON encodes the problem with its extension members at the top level.
+// Extension members cannot override the standard members.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		m[key] = value
+	}
+	if p.Type != "" {
+		m["type"] = p.Type
+	} else {
+		delete(m, "type")
+	}
+	if p.Title != "" {
+		m["title"] = p.Title
+	} else {
+		delete(m, "title")
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	} else {
+		delete(m, "status")
+	}
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	} else {
+		delete(m, "detail")
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	} else {
+		delete(m, "instance")
+	}
+
+	return json.Marshal(m)
+}
+
+// New creates a problem response with the specified HTTP status code.
+// The title defaults to the status text of the code.
+func New(code int) *problemResponder {
+	return &problemResponder{
+		problem: Problem{
+			Title:  http.StatusText(code),
+			Status: code,
+		},
+	}
+}
+
+// Error creates a problem response with the specified detail message and HTTP status code.
+// The 'err' parameter can be used for internal logging.
+func Error(err error, detail string, code int) *problemResponder {
+	return New(code).WithDetail(detail).WithError(err)
+}
+
+// InternalServerError creates a standardized internal server error problem response.
+// The 'err' parameter can be used for internal logging.
+func InternalServerError(err error) *problemResponder {
+	return New(http.StatusInternalServerError).WithError(err)
+}
+
+// WriteInternalServerError writes a 500 Internal Server Error problem. It can be used with
+// httphandler.SetInternalServerErrorWriter so that failures are reported as problems too.
+func WriteInternalServerError(w http.ResponseWriter, _ error) {
+	b, _ := json.Marshal(Problem{
+		Title:  http.StatusText(http.StatusInternalServerError),
+		Status: http.StatusInternalServerError,
+	})
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write(b)
+}
+
+// DecodeErrorHandler renders decoding failures as a 400 Bad Request problem.
+// It can be used with httphandler.WithDecodeErrorHandler.
+func DecodeErrorHandler(_ *http.Request, err error) httphandler.Responder {
+	return Error(err, "Invalid request payload", http.StatusBadRequest)
+}
+
+// WithDecodeErrors returns a handler option that renders decoding failures as problems.
+func WithDecodeErrors() httphandler.HandlerOption {
+	return httphandler.WithDecodeErrorHandler(DecodeErrorHandler)
+}
+
+// Preset returns a handler option that renders every failure as a problem: decoding failures
+// with the status code and headers of httphandler.ClassifyDecodeError, and errors returned by
+// handlers and recovered panics as 500 Internal Server Error. Use it with
+// SetInternalServerErrorWriter and WriteInternalServerError to also cover encoding failures.
+func Preset() httphandler.HandlerOption {
+	return httphandler.Bundle(
+		httphandler.WithDecodeErrorHandler(presetDecodeErrorHandler),
+		httphandler.WithErrorMapper(func(_ *http.Request, err error) httphandler.Responder {
+			return InternalServerError(err)
+		}),
+		httphandler.WithPanicHandler(func(_ context.Context, recovered any, _ []byte) httphandler.Responder {
+			return InternalServerError(fmt.Errorf("panic: %v", recovered))
+		}),
+	)
+}
+
+// presetDecodeErrorHandler renders decoding failures with the status code and headers of
+// httphandler.ClassifyDecodeError.
+func presetDecodeErrorHandler(_ *http.Request, err error) httphandler.Responder {
+	class := httphandler.ClassifyDecodeError(err)
+	detail := http.StatusText(class.Status)
+	if class.Status == http.StatusBadRequest {
+		detail = "Invalid request payload"
+	}
+	res := Error(err, detail, class.Status)
+	for key, values := range class.Header {
+		for _, value := range values {
+			res.WithHeader(key, value)
+		}
+	}
+	return res
+}
+
+// problemResponder handles application/problem+json HTTP responses.
+type problemResponder struct {
+	logger   httphandler.Logger
+	header   http.Header
+	override http.Header
+	cookies  []*http.Cookie
+	problem  Problem
+	err      error
+}
+
+// Respond sends the problem document with custom headers, cookies and status code.
+func (res *problemResponder) Respond(w http.ResponseWriter, r *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+	httphandler.RecordError(r, res.err)
+
+	// Write the problem document.
+	b := responder.Encode(w, res.problem.Status, ContentType, res.problem, json.Marshal, res.logger)
+	if b == nil {
+		return
+	}
+	responder.Log(res.logger, res.problem.Status, res.err, "response_body", b)
+}
+
+// WithType sets the URI reference that identifies the problem type.
+func (res *problemResponder) WithType(uri string) *problemResponder {
+	res.problem.Type = uri
+	return res
+}
+
+// WithTitle sets the short, human-readable summary of the problem type.
+func (res *problemResponder) WithTitle(title string) *problemResponder {
+	res.problem.Title = title
+	return res
+}
+
+// WithDetail sets the human-readable explanation specific to this occurrence of the problem.
+func (res *problemResponder) WithDetail(detail string) *problemResponder {
+	res.problem.Detail = detail
+	return res
+}
+
+// WithInstance sets the URI reference that identifies this occurrence of the problem.
+func (res *problemResponder) WithInstance(uri string) *problemResponder {
+	res.problem.Instance = uri
+	return res
+}
+
+// WithExtension adds an extension member to the problem document.
+func (res *problemResponder) WithExtension(key string, value any) *problemResponder {
+	if res.problem.Extensions == nil {
+		res.problem.Extensions = map[string]any{}
+	}
+	res.problem.Extensions[key] = value
+	return res
+}
+
+// WithError sets the error used for internal logging.
+func (res *problemResponder) WithError(err error) *problemResponder {
+	res.err = err
+	return res
+}
+
+// WithLogger sets the logger for the responder.
+func (res *problemResponder) WithLogger(logger httphandler.Logger) *problemResponder {
+	res.logger = logger
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *problemResponder) WithHeader(key, value string) *problemResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *problemResponder) SetHeader(key, value string) *problemResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *problemResponder) WithCookie(cookie *http.Cookie) *problemResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
+
+// MapError returns an httphandler.ErrorMapper that renders any error as a problem with the
+// specified detail message and HTTP status code, e.g. for use with httphandler.ErrorRegistry.
+func MapError(detail string, code int) httphandler.ErrorMapper {
+	return func(_ *http.Request, err error) httphandler.Responder {
+		return Error(err, detail, code)
+	}
+}
@@ -0,0 +1,280 @@
+package problemresp_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/problemresp"
+)
+
+func TestProblem_Respond(t *testing.T) {
+	t.Parallel()
+
+	cookie := &http.Cookie{
+		Name:  "test-cookie",
+		Value: "test-value",
+	}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantCookies []*http.Cookie
+		wantBody    string
+	}{
+		{
+			desc:     "basic",
+			given:    problemresp.New(http.StatusNotFound),
+			wantCode: http.StatusNotFound,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/problem+json",
+			},
+			wantCookies: nil,
+			wantBody:    `{"status":404,"title":"Not Found"}`,
+		},
+		{
+			desc:        "error",
+			given:       problemresp.Error(errors.New("invalid id"), "Invalid ID provided", http.StatusBadRequest),
+			wantCode:    http.StatusBadRequest,
+			wantHeaders: nil,
+			wantCookies: nil,
+			wantBody:    `{"detail":"Invalid ID provided","status":400,"title":"Bad Request"}`,
+		},
+		{
+			desc:        "internal server error",
+			given:       problemresp.InternalServerError(errors.New("database failure")),
+			wantCode:    http.StatusInternalServerError,
+			wantHeaders: nil,
+			wantCookies: nil,
+			wantBody:    `{"status":500,"title":"Internal Server Error"}`,
+		},
+		{
+			desc: "with everything",
+			given: problemresp.New(http.StatusForbidden).
+				WithType("https://example.com/probs/out-of-credit").
+				WithTitle("You do not have enough credit.").
+				WithDetail("Your current balance is 30, but that costs 50.").
+				WithInstance("/account/12345/msgs/abc").
+				WithExtension("balance", 30).
+				WithExtension("status", 200).
+				WithHeader("X-Test-1", "test value 1").
+				WithCookie(cookie),
+			wantCode: http.StatusForbidden,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/problem+json",
+				"X-Test-1":     "test value 1",
+			},
+			wantCookies: []*http.Cookie{cookie},
+			wantBody: `{"balance":30,"detail":"Your current balance is 30, but that costs 50.",` +
+				`"instance":"/account/12345/msgs/abc","status":403,"title":"You do not have enough credit.",` +
+				`"type":"https://example.com/probs/out-of-credit"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			gotCode := w.Code
+			if gotCode != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, gotCode)
+			}
+
+			gotHeaders := w.Header()
+			for key, wantValue := range tc.wantHeaders {
+				gotValue := gotHeaders.Get(key)
+				if gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			gotCookies := w.Result().Cookies()
+			if len(gotCookies) != len(tc.wantCookies) {
+				t.Errorf("cookie count: want %d, got %d", len(tc.wantCookies), len(gotCookies))
+			}
+
+			for i, want := range tc.wantCookies {
+				got := gotCookies[i]
+				if got.Name != want.Name || got.Value != want.Value {
+					t.Errorf("cookie %d: want {%s=%s}, got {%s=%s}",
+						i, want.Name, want.Value, got.Name, got.Value)
+				}
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
+
+func TestWithDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{"))
+
+	given := httphandler.HandleWithInput(
+		func(r *http.Request, input map[string]any) httphandler.Responder {
+			t.Errorf("handler: should not be called on decoding failure")
+			return nil
+		},
+		problemresp.WithDecodeErrors(),
+	)
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status code: want %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != problemresp.ContentType {
+		t.Errorf("Content-Type: want %s, got %s", problemresp.ContentType, got)
+	}
+
+	wantBody := `{"detail":"Invalid request payload","status":400,"title":"Bad Request"}`
+	if gotBody := w.Body.String(); gotBody != wantBody {
+		t.Errorf("body: want '%s', got '%s'", wantBody, gotBody)
+	}
+}
+
+func TestWriteInternalServerError(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "application/json")
+
+	// When:
+	problemresp.WriteInternalServerError(w, errors.New("encode failure"))
+
+	// Then:
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status code: want %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if got := w.Header().Get("Content-Type"); got != problemresp.ContentType {
+		t.Errorf("Content-Type: want %s, got %s", problemresp.ContentType, got)
+	}
+
+	wantBody := `{"status":500,"title":"Internal Server Error"}`
+	if gotBody := w.Body.String(); gotBody != wantBody {
+		t.Errorf("body: want '%s', got '%s'", wantBody, gotBody)
+	}
+}
+
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc       string
+		given      http.HandlerFunc
+		body       string
+		wantCode   int
+		wantHeader http.Header
+		wantBody   string
+	}{
+		{
+			desc: "decode error",
+			given: httphandler.HandleWithInput(func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}, problemresp.Preset()),
+			body:     "{",
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"detail":"Invalid request payload","status":400,"title":"Bad Request"}`,
+		},
+		{
+			desc: "body too large",
+			given: httphandler.HandleWithInput(func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}, problemresp.Preset(), httphandler.WithMaxBodyBytes(2)),
+			body:     `{"a":1}`,
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: `{"detail":"Request Entity Too Large","status":413,"title":"Request Entity Too Large"}`,
+		},
+		{
+			desc: "insufficient scope",
+			given: httphandler.HandleWithDecoder(func(r *http.Request) (map[string]any, error) {
+				return nil, &httphandler.ScopeError{Missing: []string{"admin"}}
+			}, func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}, problemresp.Preset()),
+			wantCode:   http.StatusForbidden,
+			wantHeader: http.Header{"Www-Authenticate": {`Bearer error="insufficient_scope", scope="admin"`}},
+			wantBody:   `{"detail":"Forbidden","status":403,"title":"Forbidden"}`,
+		},
+		{
+			desc: "unsupported media type",
+			given: httphandler.HandleWithDecoder(func(r *http.Request) (map[string]any, error) {
+				return nil, &httphandler.MediaTypeError{ContentType: "text/plain", Supported: []string{"application/json"}}
+			}, func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}, problemresp.Preset()),
+			wantCode:   http.StatusUnsupportedMediaType,
+			wantHeader: http.Header{"Accept-Post": {"application/json"}},
+			wantBody:   `{"detail":"Unsupported Media Type","status":415,"title":"Unsupported Media Type"}`,
+		},
+		{
+			desc: "handler error",
+			given: httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+				return nil, errors.New("boom")
+			}, problemresp.Preset()),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"status":500,"title":"Internal Server Error"}`,
+		},
+		{
+			desc: "panic",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				panic("boom")
+			}, problemresp.Preset()),
+			wantCode: http.StatusInternalServerError,
+			wantBody: `{"status":500,"title":"Internal Server Error"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+
+			// When:
+			tc.given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			for key := range tc.wantHeader {
+				if got, want := w.Header().Get(key), tc.wantHeader.Get(key); got != want {
+					t.Errorf("%s: want %s, got %s", key, want, got)
+				}
+			}
+			if got := w.Header().Get("Content-Type"); got != problemresp.ContentType {
+				t.Errorf("Content-Type: want %s, got %s", problemresp.ContentType, got)
+			}
+			if gotBody := w.Body.String(); gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package propagation
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CorrelationPrefix is the prefix of the correlation headers propagated by Carrier.
+const CorrelationPrefix = "X-Correlation-"
+
+// TraceParent is a parsed W3C traceparent header.
+type TraceParent struct {
+	TraceID  string
+	ParentID string
+	Flags    byte
+}
+
+// Sampled reports whether the sampled flag is set.
+func (tp TraceParent) Sampled() bool {
+	return tp.Flags&0x01 != 0
+}
+
+// String formats the trace parent as a version 00 traceparent header value.
+func (tp TraceParent) String() string {
+	return fmt.Sprintf("00-%s-%s-%02x", tp.TraceID, tp.ParentID, tp.Flags)
+}
+
+// ParseTraceParent parses a traceparent header value.
+// It reports false for values that are malformed or use the invalid all-zero IDs.
+func ParseTraceParent(value string) (TraceParent, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return TraceParent{}, false
+	}
+	// Version 00 has exactly four fields; later versions may append more.
+	if parts[0] == "00" && len(parts) != 4 {
+		return TraceParent{}, false
+	}
+
+	for _, part := range parts[:4] {
+		if !isLowerHex(part) {
+			return TraceParent{}, false
+		}
+	}
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return TraceParent{}, false
+	}
+
+	flags, _ := hex.DecodeString(parts[3])
+	return TraceParent{
+		TraceID:  parts[1],
+		ParentID: parts[2],
+		Flags:    flags[0],
+	}, true
+}
+
+// Carrier holds the propagation headers of a request.
+type Carrier struct {
+	// TraceParent is the parsed traceparent header; nil if absent or malformed.
+	TraceParent *TraceParent
+	// TraceState is the raw tracestate header, only kept along with a valid TraceParent.
+	TraceState string
+	// Baggage holds the W3C baggage members with their values percent-decoded.
+	Baggage map[string]string
+	// Correlation holds the X-Correlation-* headers keyed by canonical header name.
+	Correlation map[string]string
+}
+
+// Decode is a RequestDecodeFunc that reads the propagation headers of the request.
+// Malformed headers are ignored as required by the W3C specifications, so it never fails.
+func Decode(r *http.Request) (Carrier, error) {
+	var c Carrier
+
+	if tp, ok := ParseTraceParent(r.Header.Get("Traceparent")); ok {
+		c.TraceParent = &tp
+		c.TraceState = r.Header.Get("Tracestate")
+	}
+
+	for _, header := range r.Header.Values("Baggage") {
+		for _, member := range strings.Split(header, ",") {
+			// Member properties after ';' are dropped.
+			member, _, _ = strings.Cut(member, ";")
+			key, value, ok := strings.Cut(member, "=")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" {
+				continue
+			}
+			value, err := url.PathUnescape(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+			if c.Baggage == nil {
+				c.Baggage = map[string]string{}
+			}
+			c.Baggage[key] = value
+		}
+	}
+
+	for key, values := range r.Header {
+		if !strings.HasPrefix(key, CorrelationPrefix) || len(values) == 0 {
+			continue
+		}
+		if c.Correlation == nil {
+			c.Correlation = map[string]string{}
+		}
+		c.Correlation[key] = values[0]
+	}
+
+	return c, nil
+}
+
+// Inject sets the propagation headers of the carrier on h.
+func (c Carrier) Inject(h http.Header) {
+	if c.TraceParent != nil {
+		h.Set("Traceparent", c.TraceParent.String())
+		if c.TraceState != "" {
+			h.Set("Tracestate", c.TraceState)
+		}
+	}
+
+	if len(c.Baggage) > 0 {
+		keys := make([]string, 0, len(c.Baggage))
+		for key := range c.Baggage {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		members := make([]string, 0, len(keys))
+		for _, key := range keys {
+			members = append(members, key+"="+url.PathEscape(c.Baggage[key]))
+		}
+		h.Set("Baggage", strings.Join(members, ","))
+	}
+
+	c.injectCorrelation(h)
+}
+
+// injectCorrelation sets the correlation headers of the carrier on h.
+func (c Carrier) injectCorrelation(h http.Header) {
+	for key, value := range c.Correlation {
+		h.Set(key, value)
+	}
+}
+
+// contextKey is the key under which the Carrier is stored in a context.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries c.
+func NewContext(ctx context.Context, c Carrier) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Carrier stored in ctx by NewContext or Propagate.
+func FromContext(ctx context.Context) (Carrier, bool) {
+	c, ok := ctx.Value(contextKey{}).(Carrier)
+	return c, ok
+}
+
+// Propagate wraps a handler so that the propagation headers of each request are decoded into the
+// request context, and the correlation headers are echoed on the response.
+func Propagate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, _ := Decode(r)
+		c.injectCorrelation(w.Header())
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), c)))
+	})
+}
+
+// Ensure Transport implements http.RoundTripper.
+var _ http.RoundTripper = (*Transport)(nil)
+
+// Transport is an http.RoundTripper that injects the Carrier found in the request context
+// into outbound requests made inside handlers.
+type Transport struct {
+	// Base is the underlying RoundTripper. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip injects the propagation headers and sends the request.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if c, ok := FromContext(req.Context()); ok {
+		req = req.Clone(req.Context())
+		c.Inject(req.Header)
+	}
+
+	return base.RoundTrip(req)
+}
+
+// isLowerHex reports whether s only contains lower-case hexadecimal digits.
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package propagation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler/propagation"
+)
+
+const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc  string
+		given map[string]string
+		want  propagation.Carrier
+	}{
+		{
+			desc:  "no headers",
+			given: nil,
+			want:  propagation.Carrier{},
+		},
+		{
+			desc: "all headers",
+			given: map[string]string{
+				"Traceparent":        traceParent,
+				"Tracestate":         "congo=t61rcWkgMzE",
+				"Baggage":            "userId=alice, serverNode=DF%2028;prop=1, invalid",
+				"X-Correlation-Id":   "abc-123",
+				"X-Correlation-Flow": "checkout",
+				"X-Other":            "ignored",
+			},
+			want: propagation.Carrier{
+				TraceParent: &propagation.TraceParent{
+					TraceID:  "4bf92f3577b34da6a3ce929d0e0e4736",
+					ParentID: "00f067aa0ba902b7",
+					Flags:    0x01,
+				},
+				TraceState: "congo=t61rcWkgMzE",
+				Baggage: map[string]string{
+					"userId":     "alice",
+					"serverNode": "DF 28",
+				},
+				Correlation: map[string]string{
+					"X-Correlation-Id":   "abc-123",
+					"X-Correlation-Flow": "checkout",
+				},
+			},
+		},
+		{
+			desc: "malformed traceparent | tracestate dropped",
+			given: map[string]string{
+				"Traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+				"Tracestate":  "congo=t61rcWkgMzE",
+			},
+			want: propagation.Carrier{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for key, value := range tc.given {
+				r.Header.Set(key, value)
+			}
+
+			// When:
+			got, err := propagation.Decode(r)
+
+			// Then:
+			if err != nil {
+				t.Errorf("error: want nil, got %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("carrier: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseTraceParent(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc   string
+		given  string
+		wantOK bool
+	}{
+		{desc: "valid", given: traceParent, wantOK: true},
+		{desc: "future version with extra field", given: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", wantOK: true},
+		{desc: "version 00 with extra field", given: traceParent + "-extra", wantOK: false},
+		{desc: "forbidden version", given: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantOK: false},
+		{desc: "upper case", given: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", wantOK: false},
+		{desc: "zero parent id", given: "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", wantOK: false},
+		{desc: "short", given: "00-4bf92f35-00f067aa0ba902b7-01", wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, gotOK := propagation.ParseTraceParent(tc.given)
+			if gotOK != tc.wantOK {
+				t.Errorf("ok: want %t, got %t", tc.wantOK, gotOK)
+			}
+		})
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	var gotOutbound http.Header
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotOutbound = r.Header.Clone()
+	}))
+	defer downstream.Close()
+
+	client := &http.Client{Transport: &propagation.Transport{}}
+	handler := propagation.Propagate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	}))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Traceparent", traceParent)
+	r.Header.Set("Baggage", "userId=alice")
+	r.Header.Set("X-Correlation-Id", "abc-123")
+
+	// When:
+	handler.ServeHTTP(w, r)
+
+	// Then:
+	if got := w.Header().Get("X-Correlation-Id"); got != "abc-123" {
+		t.Errorf("response X-Correlation-Id: want %s, got %s", "abc-123", got)
+	}
+
+	wantOutbound := map[string]string{
+		"Traceparent":      traceParent,
+		"Baggage":          "userId=alice",
+		"X-Correlation-Id": "abc-123",
+	}
+	for key, want := range wantOutbound {
+		if got := gotOutbound.Get(key); got != want {
+			t.Errorf("outbound %s: want %s, got %s", key, want, got)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package protoresp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrDecode = errors.New("fail to decode protobuf")
+
+// Body decodes a Protocol Buffers request body into a new message of type T, which is a
+// pointer to a generated message type such as *pb.Order. Use it with httphandler.HandleWithDecoder.
+func Body[T proto.Message](r *http.Request) (T, error) {
+	var zero T
+	msg := zero.ProtoReflect().New().Interface().(T)
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return msg, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return msg, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,69 @@
+package protoresp_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler/protoresp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestBody(t *testing.T) {
+	t.Parallel()
+
+	valid, err := proto.Marshal(wrapperspb.String("o-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		desc      string
+		givenBody []byte
+		want      string
+		wantErr   error
+	}{
+		{
+			desc:      "valid",
+			givenBody: valid,
+			want:      "o-1",
+		},
+		{
+			desc:      "empty",
+			givenBody: nil,
+			want:      "",
+		},
+		{
+			desc:      "invalid",
+			givenBody: []byte{0xff},
+			wantErr:   protoresp.ErrDecode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewReader(tc.givenBody))
+
+			// When:
+			got, err := protoresp.Body[*wrapperspb.StringValue](r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error: want %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr != nil {
+				return
+			}
+
+			if got == nil || got.GetValue() != tc.want {
+				t.Errorf("input: want %s, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/alvinchoong/go-httphandler/protoresp
+
+go 1.22
+
+require (
+	github.com/alvinchoong/go-httphandler v0.0.0
+	google.golang.org/protobuf v1.36.6
+)
+
+replace github.com/alvinchoong/go-httphandler => ../
@@ -0,0 +1,91 @@
+// Package protoresp provides a Protocol Buffers responder and request body decoder, for
+// binary endpoints in the style of gRPC-Gateway. It is a separate module so that the
+// protobuf dependency stays optional.
+package protoresp
+
+import (
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentType is the media type of Protocol Buffers messages.
+const ContentType = "application/x-protobuf"
+
+// Ensure successResponder implements Responder.
+var _ httphandler.Responder = (*successResponder)(nil)
+
+// Success creates a new successResponder with the provided message and a default status code of 200 OK.
+func Success(msg proto.Message) *successResponder {
+	return &successResponder{
+		statusCode: http.StatusOK,
+		msg:        msg,
+	}
+}
+
+// successResponder handles successful Protocol Buffers HTTP responses.
+type successResponder struct {
+	logger     httphandler.Logger
+	header     http.Header
+	override   http.Header
+	statusCode int
+	cookies    []*http.Cookie
+	msg        proto.Message
+}
+
+// Respond sends the message with custom headers, cookies and status code.
+func (res *successResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// Write the message.
+	if b := responder.Encode(w, res.statusCode, ContentType, res.msg, marshal, res.logger); b != nil {
+		httphandler.LogResponse(res.logger, res.statusCode, "response_bytes", len(b))
+	}
+}
+
+// WithLogger sets the logger for the responder.
+func (res *successResponder) WithLogger(logger httphandler.Logger) *successResponder {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *successResponder) WithStatus(status int) *successResponder {
+	res.statusCode = status
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *successResponder) WithHeader(key, value string) *successResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *successResponder) SetHeader(key, value string) *successResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *successResponder) WithCookie(cookie *http.Cookie) *successResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
+
+// marshal encodes v, a proto.Message, in the Protocol Buffers wire format.
+func marshal(v any) ([]byte, error) {
+	return proto.Marshal(v.(proto.Message))
+}
@@ -0,0 +1,113 @@
+package httphandler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Ensure rawResponder implements Responder.
+var _ Responder = (*rawResponder)(nil)
+
+// Raw creates a new rawResponder that sends body as is with the given Content-Type
+// and a default status code of 200 OK, e.g. for images or pre-encoded payloads.
+func Raw(contentType string, body []byte) *rawResponder {
+	return &rawResponder{
+		statusCode:  http.StatusOK,
+		contentType: contentType,
+		body:        body,
+	}
+}
+
+// RawReader is like Raw but copies the body from reader.
+// If reader is an io.Closer, it is closed after responding.
+func RawReader(contentType string, reader io.Reader) *rawResponder {
+	return &rawResponder{
+		statusCode:  http.StatusOK,
+		contentType: contentType,
+		reader:      reader,
+	}
+}
+
+// rawResponder handles HTTP responses with a body that is already encoded.
+type rawResponder struct {
+	logger      Logger
+	header      http.Header
+	override    http.Header
+	statusCode  int
+	cookies     []*http.Cookie
+	contentType string
+	body        []byte
+	reader      io.Reader
+}
+
+// Respond sends the body with custom headers, cookies and status code.
+func (res *rawResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	if closer, ok := res.reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// Apply cookies and custom headers when the status code is written.
+	w = deferHeaders(w, res.header, res.override, res.cookies)
+
+	w.Header().Set("Content-Type", res.contentType)
+
+	// Write the body.
+	if res.reader == nil {
+		w.Header().Set("Content-Length", strconv.Itoa(len(res.body)))
+		w.WriteHeader(res.statusCode)
+		if _, err := w.Write(res.body); err != nil {
+			LogRequestError(res.logger, err, "status_code", res.statusCode)
+			return
+		}
+		LogResponse(res.logger, res.statusCode, "content_type", res.contentType, "bytes", len(res.body))
+		return
+	}
+
+	w.WriteHeader(res.statusCode)
+	n, err := io.Copy(w, res.reader)
+	if err != nil {
+		LogRequestError(res.logger, err, "status_code", res.statusCode, "bytes", n)
+		return
+	}
+	LogResponse(res.logger, res.statusCode, "content_type", res.contentType, "bytes", n)
+}
+
+// WithLogger sets the logger for the responder.
+func (res *rawResponder) WithLogger(logger Logger) *rawResponder {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *rawResponder) WithStatus(status int) *rawResponder {
+	res.statusCode = status
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *rawResponder) WithHeader(key, value string) *rawResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *rawResponder) SetHeader(key, value string) *rawResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *rawResponder) WithCookie(cookie *http.Cookie) *rawResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
@@ -0,0 +1,132 @@
+package httphandler_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// closeTracker records whether it has been closed.
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (ct *closeTracker) Close() error {
+	ct.closed = true
+	return nil
+}
+
+func TestRaw_Respond(t *testing.T) {
+	t.Parallel()
+
+	cookie := &http.Cookie{
+		Name:  "test-cookie",
+		Value: "test-value",
+	}
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantCookies []*http.Cookie
+		wantBody    string
+	}{
+		{
+			desc:     "bytes",
+			given:    httphandler.Raw("image/png", []byte("\x89PNG")),
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Content-Type":   "image/png",
+				"Content-Length": "4",
+			},
+			wantBody: "\x89PNG",
+		},
+		{
+			desc: "reader | with everything",
+			given: httphandler.RawReader("application/pdf", strings.NewReader("%PDF")).
+				WithStatus(http.StatusCreated).
+				WithHeader("X-Test-1", "test value 1").
+				WithCookie(cookie),
+			wantCode: http.StatusCreated,
+			wantHeaders: map[string]string{
+				"Content-Type": "application/pdf",
+				"X-Test-1":     "test value 1",
+			},
+			wantCookies: []*http.Cookie{cookie},
+			wantBody:    "%PDF",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test-raw", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			for key, want := range tc.wantHeaders {
+				if got := w.Header().Get(key); got != want {
+					t.Errorf("header %s: want '%s', got '%s'", key, want, got)
+				}
+			}
+
+			gotCookies := w.Result().Cookies()
+			if len(gotCookies) != len(tc.wantCookies) {
+				t.Fatalf("cookies: want %d, got %d", len(tc.wantCookies), len(gotCookies))
+			}
+			for i, want := range tc.wantCookies {
+				if gotCookies[i].Name != want.Name || gotCookies[i].Value != want.Value {
+					t.Errorf("cookie %d: want %s=%s, got %s=%s", i, want.Name, want.Value, gotCookies[i].Name, gotCookies[i].Value)
+				}
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestRawReader_Close(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	body := &closeTracker{Reader: io.MultiReader(strings.NewReader("ok"), errReader{})}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/test-raw", nil)
+
+	// When:
+	httphandler.RawReader("text/plain", body).Respond(w, r)
+
+	// Then:
+	if !body.closed {
+		t.Errorf("closed: want %t, got %t", true, body.closed)
+	}
+
+	if got := w.Body.String(); got != "ok" {
+		t.Errorf("body: want '%s', got '%s'", "ok", got)
+	}
+}
+
+// errReader always fails.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
@@ -0,0 +1,51 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicHandler converts a value recovered from a panic into a Responder.
+type PanicHandler func(ctx context.Context, recovered any, stack []byte) Responder
+
+// Recover wraps a handler so that panics raised while decoding or handling a request are
+// recovered and rendered with the Responder returned by panicHandler.
+// If panicHandler returns nil, a 500 Internal Server Error is sent, see SetInternalServerErrorWriter.
+// http.ErrAbortHandler is re-panicked so that net/http can abort the connection as intended.
+func Recover(handler http.Handler, panicHandler PanicHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			stack := debug.Stack()
+			if p, ok := recovered.(*handlerPanic); ok {
+				recovered, stack = p.value, p.stack
+			}
+			recordRequestError(r, panicError(recovered))
+			res := panicHandler(r.Context(), recovered, stack)
+			if res == nil {
+				writeInternalServerError(w, panicError(recovered))
+				return
+			}
+			res.Respond(w, r)
+		}()
+
+		handler.ServeHTTP(w, r)
+	}
+}
+
+// WithPanicHandler recovers panics raised by the decoder or the handler and renders them
+// with the Responder returned by panicHandler. See Recover.
+func WithPanicHandler(panicHandler PanicHandler) HandlerOption {
+	return func(o *handlerOptions) {
+		o.panicHandler = panicHandler
+	}
+}
@@ -0,0 +1,124 @@
+package httphandler_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestRecover(t *testing.T) {
+	t.Parallel()
+
+	panicHandler := func(ctx context.Context, recovered any, stack []byte) httphandler.Responder {
+		if len(stack) == 0 {
+			t.Errorf("stack: want non-empty")
+		}
+		return &mockResponder{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       fmt.Sprint(recovered),
+		}
+	}
+
+	testCases := []struct {
+		desc     string
+		given    http.Handler
+		wantCode int
+		wantBody string
+	}{
+		{
+			desc: "no panic",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				return &mockResponder{StatusCode: http.StatusOK, Body: "Success"}
+			}),
+			wantCode: http.StatusOK,
+			wantBody: "Success",
+		},
+		{
+			desc: "panic in handler",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				panic("boom")
+			}),
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: "boom",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			httphandler.Recover(tc.given, panicHandler).ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if w.Body.String() != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRecover_NilResponder(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	given := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		panic("boom")
+	})
+
+	// When:
+	httphandler.Recover(given, func(context.Context, any, []byte) httphandler.Responder {
+		return nil
+	}).ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status code: want %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestWithPanicHandler(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	given := httphandler.HandleWithDecoder(
+		func(r *http.Request) (string, error) {
+			panic("decoder boom")
+		},
+		func(r *http.Request, input string) httphandler.Responder {
+			t.Errorf("handler: should not be called when decoder panics")
+			return nil
+		},
+		httphandler.WithPanicHandler(func(ctx context.Context, recovered any, stack []byte) httphandler.Responder {
+			return &mockResponder{StatusCode: http.StatusInternalServerError, Body: fmt.Sprint(recovered)}
+		}),
+	)
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status code: want %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	if w.Body.String() != "decoder boom" {
+		t.Errorf("body: want '%s', got '%s'", "decoder boom", w.Body.String())
+	}
+}
@@ -19,6 +19,7 @@ func Redirect(url string, code int) *redirectResponder {
 type redirectResponder struct {
 	logger     Logger
 	header     http.Header
+	override   http.Header
 	statusCode int
 	cookies    []*http.Cookie
 	url        string
@@ -26,17 +27,8 @@ type redirectResponder struct {
 
 // Respond sents an HTTP redirect with custom headers, cookies, and status code.
 func (res *redirectResponder) Respond(w http.ResponseWriter, r *http.Request) {
-	// Set cookies.
-	for _, cookie := range res.cookies {
-		http.SetCookie(w, cookie)
-	}
-
-	// Add custom headers.
-	for key, values := range res.header {
-		for _, value := range values {
-			w.Header().Add(key, value)
-		}
-	}
+	// Apply cookies and custom headers when the status code is written.
+	w = deferHeaders(w, res.header, res.override, res.cookies)
 
 	// Redirect to the specified URL.
 	http.Redirect(w, r, res.url, res.statusCode)
@@ -50,7 +42,8 @@ func (res *redirectResponder) WithLogger(logger Logger) *redirectResponder {
 	return res
 }
 
-// WithHeader adds a header to the response.
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
 func (res *redirectResponder) WithHeader(key, value string) *redirectResponder {
 	if res.header == nil {
 		res.header = http.Header{}
@@ -59,6 +52,17 @@ func (res *redirectResponder) WithHeader(key, value string) *redirectResponder {
 	return res
 }
 
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *redirectResponder) SetHeader(key, value string) *redirectResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
 // WithCookie adds a cookie to the response.
 func (res *redirectResponder) WithCookie(cookie *http.Cookie) *redirectResponder {
 	res.cookies = append(res.cookies, cookie)
@@ -0,0 +1,136 @@
+package responder
+
+import (
+	"encoding/binary"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charset is the character encoding of a text response, as sent in the charset parameter
+// of the Content-Type header.
+type Charset string
+
+const (
+	// UTF8 is the default charset of the text responders.
+	UTF8 Charset = "utf-8"
+	// UTF16LE is little-endian UTF-16. The body is prefixed with a byte order mark, which
+	// Excel needs to open a CSV file with non-ASCII characters correctly.
+	UTF16LE Charset = "utf-16le"
+)
+
+// ContentType returns mediaType with its charset parameter set to charset, replacing any
+// charset it already has and keeping its other parameters. An empty charset is UTF8.
+func ContentType(mediaType string, charset Charset) string {
+	if charset == "" {
+		charset = UTF8
+	}
+	base, params, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		base, params = strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0]), map[string]string{}
+	}
+	params["charset"] = string(charset)
+	if ct := mime.FormatMediaType(base, params); ct != "" {
+		return ct
+	}
+	return base + "; charset=" + string(charset)
+}
+
+// NegotiateCharset returns the charset among offered that is preferred by the Accept-Charset
+// header of r, the first one on a tie. It returns the first offered charset if the header is
+// absent or accepts none of them, as a server may disregard the header, and UTF8 if none is
+// offered.
+func NegotiateCharset(r *http.Request, offered ...Charset) Charset {
+	if len(offered) == 0 {
+		return UTF8
+	}
+	accept := r.Header.Values("Accept-Charset")
+	if len(accept) == 0 {
+		return offered[0]
+	}
+
+	best, bestQ := offered[0], 0.0
+	for _, charset := range offered {
+		if q := charsetQuality(accept, charset); q > bestQ {
+			best, bestQ = charset, q
+		}
+	}
+	return best
+}
+
+// charsetQuality returns the quality value that the Accept-Charset values give to charset.
+// An exact match takes precedence over the "*" wildcard.
+func charsetQuality(accept []string, charset Charset) float64 {
+	q, wildcard := -1.0, -1.0
+	for _, value := range accept {
+		for _, item := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(item, ";")
+			name = strings.TrimSpace(name)
+			itemQ := 1.0
+			for _, param := range strings.Split(params, ";") {
+				key, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if ok && strings.EqualFold(key, "q") {
+					if f, err := strconv.ParseFloat(v, 64); err == nil {
+						itemQ = f
+					}
+				}
+			}
+			switch {
+			case strings.EqualFold(name, string(charset)):
+				q = itemQ
+			case name == "*":
+				wildcard = itemQ
+			}
+		}
+	}
+	if q >= 0 {
+		return q
+	}
+	return max(wildcard, 0)
+}
+
+// CharsetWriter returns a writer that encodes the UTF-8 text written to it in charset before
+// writing it to w. For UTF16LE the byte order mark is written first, and a rune split across
+// writes is kept until it is complete. For UTF8 and an empty charset, w itself is returned.
+func CharsetWriter(w io.Writer, charset Charset) io.Writer {
+	if charset != UTF16LE {
+		return w
+	}
+	return &utf16leWriter{w: w}
+}
+
+// utf16leWriter encodes UTF-8 text as little-endian UTF-16.
+type utf16leWriter struct {
+	w       io.Writer
+	started bool
+	pending []byte
+}
+
+func (w *utf16leWriter) Write(p []byte) (int, error) {
+	var out []byte
+	if !w.started {
+		w.started = true
+		out = append(out, 0xff, 0xfe)
+	}
+
+	b := append(w.pending, p...)
+	var units []uint16
+	for len(b) > 0 && utf8.FullRune(b) {
+		r, size := utf8.DecodeRune(b)
+		units = utf16.AppendRune(units, r)
+		b = b[size:]
+	}
+	w.pending = append(w.pending[:0], b...)
+
+	for _, u := range units {
+		out = binary.LittleEndian.AppendUint16(out, u)
+	}
+	if _, err := w.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,107 @@
+package responder_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+func TestContentType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc         string
+		givenType    string
+		givenCharset responder.Charset
+		want         string
+	}{
+		{desc: "default", givenType: "text/plain", want: "text/plain; charset=utf-8"},
+		{desc: "utf-16le", givenType: "text/csv", givenCharset: responder.UTF16LE, want: "text/csv; charset=utf-16le"},
+		{desc: "replaces charset", givenType: "text/csv; charset=utf-8", givenCharset: responder.UTF16LE, want: "text/csv; charset=utf-16le"},
+		{desc: "keeps parameters", givenType: "text/csv; header=present", want: "text/csv; charset=utf-8; header=present"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got := responder.ContentType(tc.givenType, tc.givenCharset)
+
+			// Then:
+			if got != tc.want {
+				t.Errorf("content type: want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNegotiateCharset(t *testing.T) {
+	t.Parallel()
+
+	offered := []responder.Charset{responder.UTF8, responder.UTF16LE}
+
+	testCases := []struct {
+		desc         string
+		givenAccept  string
+		givenOffered []responder.Charset
+		want         responder.Charset
+	}{
+		{desc: "nothing offered", givenAccept: "utf-16le", want: responder.UTF8},
+		{desc: "no header", givenOffered: offered, want: responder.UTF8},
+		{desc: "preferred", givenAccept: "utf-16le, utf-8;q=0.5", givenOffered: offered, want: responder.UTF16LE},
+		{desc: "case insensitive", givenAccept: "UTF-16LE", givenOffered: offered, want: responder.UTF16LE},
+		{desc: "tie keeps the offered order", givenAccept: "utf-16le, utf-8", givenOffered: offered, want: responder.UTF8},
+		{desc: "wildcard", givenAccept: "utf-8;q=0.1, *", givenOffered: offered, want: responder.UTF16LE},
+		{desc: "refused", givenAccept: "utf-8;q=0, *;q=0", givenOffered: offered, want: responder.UTF8},
+		{desc: "none acceptable", givenAccept: "iso-8859-1", givenOffered: offered, want: responder.UTF8},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.givenAccept != "" {
+				r.Header.Set("Accept-Charset", tc.givenAccept)
+			}
+
+			// When:
+			got := responder.NegotiateCharset(r, tc.givenOffered...)
+
+			// Then:
+			if got != tc.want {
+				t.Errorf("charset: want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCharsetWriter(t *testing.T) {
+	t.Parallel()
+
+	// Given: a rune split across writes
+	var buf bytes.Buffer
+	w := responder.CharsetWriter(&buf, responder.UTF16LE)
+	text := []byte("a€😀")
+
+	// When:
+	for _, chunk := range [][]byte{text[:2], text[2:5], text[5:]} {
+		if n, err := w.Write(chunk); err != nil || n != len(chunk) {
+			t.Fatalf("write: want %d, got %d, %v", len(chunk), n, err)
+		}
+	}
+
+	// Then:
+	want := "\xff\xfea\x00\xac\x20\x3d\xd8\x00\xde"
+	if got := buf.String(); got != want {
+		t.Errorf("body: want %q, got %q", want, got)
+	}
+	if got := responder.CharsetWriter(&buf, responder.UTF8); got != &buf {
+		t.Errorf("utf-8 writer: want the underlying writer, got %T", got)
+	}
+}
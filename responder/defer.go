@@ -0,0 +1,24 @@
+package responder
+
+import (
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/internal/deferred"
+)
+
+// Defer returns a ResponseWriter that applies cookies and header to w once, right before the
+// status code is written, whichever path writes it: a successful write, http.Error or
+// httphandler.WriteInternalServerError. Custom headers are therefore sent with error responses
+// too, and cannot be dropped by being added after the status code.
+//
+// As with SetCookies and AddHeaders called first, a header that the responder sets itself,
+// e.g. Content-Type, takes precedence over the values in header with the same key. The values
+// in override replace any other value, including the responder's, e.g. for a SetHeader method.
+func Defer(w http.ResponseWriter, header, override http.Header, cookies []*http.Cookie) http.ResponseWriter {
+	policied := make([]*http.Cookie, len(cookies))
+	for i, cookie := range cookies {
+		policied[i] = httphandler.ApplyCookiePolicy(cookie)
+	}
+	return deferred.Writer(w, header, override, policied)
+}
@@ -0,0 +1,203 @@
+package responder_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/csvresp"
+	"github.com/alvinchoong/go-httphandler/downloadresp"
+	"github.com/alvinchoong/go-httphandler/jsonapiresp"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+	"github.com/alvinchoong/go-httphandler/problemresp"
+	"github.com/alvinchoong/go-httphandler/responder"
+	"github.com/alvinchoong/go-httphandler/xmlresp"
+)
+
+func TestDefer(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc          string
+		givenPreset   http.Header
+		givenHeader   http.Header
+		givenOverride http.Header
+		write         func(w http.ResponseWriter)
+		wantHeaders   map[string][]string
+	}{
+		{
+			desc:        "applied on write",
+			givenHeader: http.Header{"X-Custom": {"a", "b"}},
+			write: func(w http.ResponseWriter) {
+				_, _ = w.Write([]byte("ok"))
+			},
+			wantHeaders: map[string][]string{"X-Custom": {"a", "b"}},
+		},
+		{
+			desc:        "applied on error",
+			givenHeader: http.Header{"X-Custom": {"a"}},
+			write: func(w http.ResponseWriter) {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			},
+			wantHeaders: map[string][]string{
+				"X-Custom":     {"a"},
+				"Content-Type": {"text/plain; charset=utf-8"},
+			},
+		},
+		{
+			desc:        "responder header takes precedence",
+			givenHeader: http.Header{"Content-Type": {"text/csv"}},
+			write: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+			},
+			wantHeaders: map[string][]string{"Content-Type": {"application/json"}},
+		},
+		{
+			desc:          "override takes precedence",
+			givenHeader:   http.Header{"X-Custom": {"a"}},
+			givenOverride: http.Header{"Content-Type": {"application/vnd.api+json"}},
+			write: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+			},
+			wantHeaders: map[string][]string{
+				"X-Custom":     {"a"},
+				"Content-Type": {"application/vnd.api+json"},
+			},
+		},
+		{
+			desc:        "preset header is kept",
+			givenPreset: http.Header{"Server-Timing": {"handle;dur=1.0"}},
+			givenHeader: http.Header{"Server-Timing": {"db;dur=2.0"}},
+			write: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusOK)
+			},
+			wantHeaders: map[string][]string{"Server-Timing": {"handle;dur=1.0", "db;dur=2.0"}},
+		},
+		{
+			desc:        "committed once",
+			givenHeader: http.Header{"X-Custom": {"a"}},
+			write: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte("ok"))
+				w.Header().Set("X-Late", "dropped")
+				_, _ = w.Write([]byte("ok"))
+			},
+			wantHeaders: map[string][]string{"X-Custom": {"a"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			rec := httptest.NewRecorder()
+			for key, values := range tc.givenPreset {
+				rec.Header()[key] = values
+			}
+			w := responder.Defer(rec, tc.givenHeader, tc.givenOverride, []*http.Cookie{{Name: "session", Value: "123"}})
+
+			// When:
+			tc.write(w)
+
+			// Then:
+			got := rec.Result().Header
+			for key, want := range tc.wantHeaders {
+				if gotValues := got.Values(key); !slices.Equal(gotValues, want) {
+					t.Errorf("headers %s: want %v, got %v", key, want, gotValues)
+				}
+			}
+
+			if gotCookies := rec.Result().Cookies(); len(gotCookies) != 1 {
+				t.Errorf("cookie count: want %d, got %d", 1, len(gotCookies))
+			}
+		})
+	}
+}
+
+// TestDefer_ErrorPaths asserts that responders keep custom headers and cookies when they fail.
+func TestDefer_ErrorPaths(t *testing.T) {
+	t.Parallel()
+
+	unencodable := make(chan int)
+	cookie := &http.Cookie{Name: "session", Value: "123"}
+
+	testCases := []struct {
+		desc     string
+		given    httphandler.Responder
+		wantCode int
+	}{
+		{
+			desc:     "jsonresp | encoding error",
+			given:    jsonresp.Success(&unencodable).WithHeader("X-Custom", "a").WithCookie(cookie),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			desc: "jsonresp | lines error",
+			given: jsonresp.StreamLines(func(yield func(int) error) error {
+				return errors.New("query failure")
+			}).WithHeader("X-Custom", "a").WithCookie(cookie),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			desc:     "xmlresp | encoding error",
+			given:    xmlresp.Success(&unencodable).WithHeader("X-Custom", "a").WithCookie(cookie),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			desc: "problemresp | encoding error",
+			given: problemresp.New(http.StatusConflict).WithExtension("bad", unencodable).
+				WithHeader("X-Custom", "a").WithCookie(cookie),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			desc: "jsonapiresp | encoding error",
+			given: jsonapiresp.Data(jsonapiresp.NewResource("things", "1", unencodable)).
+				WithHeader("X-Custom", "a").WithCookie(cookie),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			desc: "csvresp | stream error",
+			given: csvresp.Stream(nil, func(yield func(row []string) error) error {
+				return errors.New("query failure")
+			}).WithHeader("X-Custom", "a").WithCookie(cookie),
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			desc:     "downloadresp | missing file",
+			given:    downloadresp.FromFile("testdata/missing.txt").WithHeader("X-Custom", "a").WithCookie(cookie),
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Result().Header.Get("X-Custom"); got != "a" {
+				t.Errorf("headers X-Custom: want %s, got %s", "a", got)
+			}
+
+			if got := w.Result().Cookies(); len(got) != 1 || got[0].Name != cookie.Name {
+				t.Errorf("cookies: want [%s], got %v", cookie.Name, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package responder provides the plumbing shared by the responders of this module,
+// so that custom responders (e.g. for other encodings) behave the same way without
+// copying it.
+package responder
+
+import (
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// SetCookies sets the cookies on the response after applying the package cookie policy.
+// See httphandler.SetCookiePolicy.
+func SetCookies(w http.ResponseWriter, cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		http.SetCookie(w, httphandler.ApplyCookiePolicy(cookie))
+	}
+}
+
+// AddHeaders adds every value of header to the response headers, keeping the values already set.
+func AddHeaders(w http.ResponseWriter, header http.Header) {
+	for key, values := range header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+}
+
+// Status returns status, or fallback if status is not set.
+func Status(status, fallback int) int {
+	if status == 0 {
+		return fallback
+	}
+	return status
+}
+
+// Encode encodes v with marshal and writes it with the status code and Content-Type.
+// The body is encoded in full before anything is written, so an encoding failure still
+// results in a clean 500 Internal Server Error. It returns the written body, or nil on failure.
+func Encode(w http.ResponseWriter, status int, contentType string, v any, marshal func(v any) ([]byte, error), logger httphandler.Logger) []byte {
+	w.Header().Set("Content-Type", contentType)
+
+	b, err := marshal(v)
+	if err != nil {
+		httphandler.WriteInternalServerError(w, logger, err, "data", v)
+		return nil
+	}
+
+	w.WriteHeader(status)
+	if _, err := w.Write(b); err != nil {
+		httphandler.WriteInternalServerError(w, logger, err, "response_body", string(b))
+		return nil
+	}
+
+	return b
+}
+
+// Log logs the outcome of a response: err as a request error if it is not nil,
+// and the status code otherwise. Nothing is logged if logger is nil.
+func Log(logger httphandler.Logger, status int, err error, args ...any) {
+	if err != nil {
+		httphandler.LogRequestError(logger, err, append([]any{"status_code", status}, args...)...)
+		return
+	}
+	httphandler.LogResponse(logger, status, args...)
+}
@@ -0,0 +1,157 @@
+package responder_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+func TestSetCookiesAndAddHeaders(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	w.Header().Set("X-Existing", "1")
+
+	// When:
+	responder.SetCookies(w, []*http.Cookie{{Name: "session", Value: "123"}})
+	responder.AddHeaders(w, http.Header{"X-Existing": {"2"}, "X-Custom": {"a", "b"}})
+
+	// Then:
+	if got := w.Header().Get("Set-Cookie"); got != "session=123" {
+		t.Errorf("cookie: want '%s', got '%s'", "session=123", got)
+	}
+
+	if got := w.Header().Values("X-Existing"); len(got) != 2 {
+		t.Errorf("existing header values: want %d, got %v", 2, got)
+	}
+
+	if got := w.Header().Values("X-Custom"); len(got) != 2 {
+		t.Errorf("custom header values: want %d, got %v", 2, got)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc  string
+		given int
+		want  int
+	}{
+		{desc: "unset", given: 0, want: http.StatusOK},
+		{desc: "set", given: http.StatusCreated, want: http.StatusCreated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got := responder.Status(tc.given, http.StatusOK)
+
+			// Then:
+			if got != tc.want {
+				t.Errorf("status code: want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc            string
+		givenMarshal    func(v any) ([]byte, error)
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			desc:            "success",
+			givenMarshal:    json.Marshal,
+			wantCode:        http.StatusCreated,
+			wantContentType: "application/json",
+			wantBody:        `{"id":1}`,
+		},
+		{
+			desc: "marshal error",
+			givenMarshal: func(v any) ([]byte, error) {
+				return nil, errors.New("boom")
+			},
+			wantCode:        http.StatusInternalServerError,
+			wantContentType: "text/plain; charset=utf-8",
+			wantBody:        "Internal Server Error\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+
+			// When:
+			responder.Encode(w, http.StatusCreated, "application/json", map[string]int{"id": 1}, tc.givenMarshal, nil)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != tc.wantContentType {
+				t.Errorf("content type: want '%s', got '%s'", tc.wantContentType, got)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+// recordingLogger records the messages it is asked to log.
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...any) { l.messages = append(l.messages, msg) }
+func (l *recordingLogger) Info(msg string, args ...any)  { l.messages = append(l.messages, msg) }
+func (l *recordingLogger) Warn(msg string, args ...any)  { l.messages = append(l.messages, msg) }
+func (l *recordingLogger) Error(msg string, args ...any) { l.messages = append(l.messages, msg) }
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		givenErr error
+		want     string
+	}{
+		{desc: "response", want: "Sent HTTP response"},
+		{desc: "error", givenErr: errors.New("boom"), want: "Error handling request"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			logger := &recordingLogger{}
+
+			// When:
+			responder.Log(logger, http.StatusOK, tc.givenErr)
+
+			// Then:
+			if len(logger.messages) != 1 || logger.messages[0] != tc.want {
+				t.Errorf("messages: want [%s], got %v", tc.want, logger.messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+// Package respondertest provides a conformance suite for Responder implementations, so that
+// in-house responder packages stay correct as this module evolves.
+package respondertest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// Config holds the settings that a Factory applies to the responder it creates, with the
+// responder's own builder methods, e.g. WithHeader, WithCookie and WithLogger.
+type Config struct {
+	// HeaderKey and HeaderValue are a custom header to add to the response.
+	HeaderKey   string
+	HeaderValue string
+	// Cookie is a cookie to add to the response.
+	Cookie *http.Cookie
+	// Logger is the logger of the responder. It is nil in the nil-safety check.
+	Logger httphandler.Logger
+}
+
+// Factory creates a new responder configured with cfg.
+type Factory func(cfg Config) httphandler.Responder
+
+// Conformance runs the conformance suite against the responders created by factory:
+//
+//   - the status code is written once;
+//   - headers and cookies are set before the status code is written, none after it;
+//   - the custom header and the cookie of the Config are sent;
+//   - a nil logger does not cause a panic;
+//   - a cancelled request context neither panics nor blocks.
+func Conformance(t *testing.T, factory Factory) {
+	t.Helper()
+
+	cfg := Config{
+		HeaderKey:   "X-Conformance",
+		HeaderValue: "conformance value",
+		Cookie:      &http.Cookie{Name: "conformance", Value: "cookie"},
+	}
+
+	t.Run("headers before body", func(t *testing.T) {
+		w := respond(t, context.Background(), factory(withLogger(cfg)))
+
+		if w.committed == nil {
+			t.Fatal("status code: want written, got none")
+		}
+		if diff := diffHeader(w.committed, w.ResponseRecorder.Header()); diff != "" {
+			t.Errorf("headers: changed after the status code was written: %s", diff)
+		}
+	})
+
+	t.Run("single WriteHeader", func(t *testing.T) {
+		w := respond(t, context.Background(), factory(withLogger(cfg)))
+
+		if w.writeHeaders > 1 {
+			t.Errorf("WriteHeader: want at most 1 call, got %d: %v", w.writeHeaders, w.statuses)
+		}
+	})
+
+	t.Run("custom header and cookie", func(t *testing.T) {
+		w := respond(t, context.Background(), factory(withLogger(cfg)))
+
+		header := w.committed
+		if header == nil {
+			t.Fatal("status code: want written, got none")
+		}
+		if got := header.Values(cfg.HeaderKey); !slices.Contains(got, cfg.HeaderValue) {
+			t.Errorf("header %s: want %q, got %q", cfg.HeaderKey, cfg.HeaderValue, got)
+		}
+		found := false
+		for _, c := range header.Values("Set-Cookie") {
+			if strings.HasPrefix(c, cfg.Cookie.Name+"="+cfg.Cookie.Value) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Set-Cookie: want %s=%s, got %q", cfg.Cookie.Name, cfg.Cookie.Value, header.Values("Set-Cookie"))
+		}
+	})
+
+	t.Run("nil logger", func(t *testing.T) {
+		nilLogger := cfg
+		nilLogger.Logger = nil
+		respond(t, context.Background(), factory(nilLogger))
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		respond(t, ctx, factory(withLogger(cfg)))
+	})
+}
+
+// withLogger returns cfg with a logger that discards everything.
+func withLogger(cfg Config) Config {
+	cfg.Logger = discardLogger{}
+	return cfg
+}
+
+// respond calls res.Respond with a recorder, failing the test if it panics or does not return
+// within a second.
+func respond(t *testing.T, ctx context.Context, res httphandler.Responder) *recorder {
+	t.Helper()
+
+	w := &recorder{ResponseRecorder: httptest.NewRecorder()}
+	r := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	done := make(chan any, 1)
+	go func() {
+		defer func() { done <- recover() }()
+		res.Respond(w, r)
+	}()
+
+	select {
+	case recovered := <-done:
+		if recovered != nil {
+			t.Fatalf("Respond: panicked: %v", recovered)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Respond: did not return within 1s")
+	}
+	return w
+}
+
+// recorder records the headers at the time the status code is written, and the calls to
+// WriteHeader.
+type recorder struct {
+	*httptest.ResponseRecorder
+
+	mu           sync.Mutex
+	committed    http.Header
+	writeHeaders int
+	statuses     []int
+}
+
+func (w *recorder) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	w.writeHeaders++
+	w.statuses = append(w.statuses, statusCode)
+	if w.committed == nil {
+		w.committed = w.ResponseRecorder.Header().Clone()
+	}
+	w.mu.Unlock()
+	w.ResponseRecorder.WriteHeader(statusCode)
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	if w.committed == nil {
+		w.committed = w.ResponseRecorder.Header().Clone()
+	}
+	w.mu.Unlock()
+	return w.ResponseRecorder.Write(b)
+}
+
+// diffHeader describes the differences between the headers at the time the status code was
+// written and at the end, ignoring the Content-Type that the recorder sniffs when the body is
+// written without one.
+func diffHeader(committed, final http.Header) string {
+	var diffs []string
+	for key, values := range final {
+		if key == "Content-Type" && len(committed.Values(key)) == 0 {
+			continue
+		}
+		if fmt.Sprint(committed[key]) != fmt.Sprint(values) {
+			diffs = append(diffs, fmt.Sprintf("%s: %q -> %q", key, committed[key], values))
+		}
+	}
+	for key, values := range committed {
+		if _, ok := final[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: %q removed", key, values))
+		}
+	}
+	return strings.Join(diffs, ", ")
+}
+
+// discardLogger is a Logger that discards everything.
+type discardLogger struct{}
+
+func (discardLogger) Debug(string, ...any) {}
+func (discardLogger) Info(string, ...any)  {}
+func (discardLogger) Warn(string, ...any)  {}
+func (discardLogger) Error(string, ...any) {}
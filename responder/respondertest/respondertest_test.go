@@ -0,0 +1,112 @@
+package respondertest_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/csvresp"
+	"github.com/alvinchoong/go-httphandler/downloadresp"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+	"github.com/alvinchoong/go-httphandler/multipartresp"
+	"github.com/alvinchoong/go-httphandler/plainresp"
+	"github.com/alvinchoong/go-httphandler/problemresp"
+	"github.com/alvinchoong/go-httphandler/responder/respondertest"
+	"github.com/alvinchoong/go-httphandler/xmlresp"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc  string
+		given respondertest.Factory
+	}{
+		{
+			desc: "jsonresp success",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				data := map[string]string{"id": "1"}
+				return jsonresp.Success(&data).WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "jsonresp encoding error",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				data := make(chan int)
+				return jsonresp.Success(&data).WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "problemresp",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return problemresp.Error(errors.New("boom"), "Boom", http.StatusConflict).WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "xmlresp",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return xmlresp.Error(nil, "Not found", http.StatusNotFound).WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "plainresp",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return plainresp.Success("hello").WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "plainresp bytes",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return plainresp.Bytes([]byte{0x89, 'P', 'N', 'G'}, "image/png").WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "csvresp",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return csvresp.Records([]string{"a"}, []string{"name"}, func(s string) []string {
+					return []string{s}
+				}).WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "downloadresp",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return downloadresp.Attachment(strings.NewReader("data"), "a.txt").WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "multipartresp",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return multipartresp.FormData(multipartresp.JSON("meta", "x")).WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "redirect",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return httphandler.Redirect("/next", http.StatusSeeOther).WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "multiple choices",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return httphandler.MultipleChoices(httphandler.Alternative{URL: "/a"}).WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+		{
+			desc: "not modified",
+			given: func(cfg respondertest.Config) httphandler.Responder {
+				return httphandler.NotModified().WithETag("v1").WithHeader(cfg.HeaderKey, cfg.HeaderValue).WithCookie(cfg.Cookie).WithLogger(cfg.Logger)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			respondertest.Conformance(t, tc.given)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package responder
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Ensure writeTimeoutWriter implements http.Flusher.
+var _ http.Flusher = (*writeTimeoutWriter)(nil)
+
+// WriteTimeout returns a ResponseWriter that extends the write deadline of the connection to d
+// from now before each write and flush, with http.ResponseController, so that a client that
+// reads too slowly makes the write fail instead of holding the connection, e.g. for download
+// endpoints. Streaming responders use it for their WithWriteTimeout method.
+//
+// The returned stop function must be called when the response is done: it restores the
+// deadline of the Server.WriteTimeout of r, measured from when WriteTimeout is called, or clears
+// it if there is none, so that the next request on the connection is not affected. The deadline
+// is never restored to less than d from now, so that the end of the response can still be
+// written. stop then calls onAbort, if not nil, with the first write error, if any. Writers that
+// do not support write deadlines are not limited, but write errors are still reported.
+func WriteTimeout(w http.ResponseWriter, r *http.Request, d time.Duration, onAbort func(err error)) (http.ResponseWriter, func()) {
+	var restore time.Time
+	if srv, ok := r.Context().Value(http.ServerContextKey).(*http.Server); ok && srv.WriteTimeout > 0 {
+		restore = time.Now().Add(srv.WriteTimeout)
+	}
+
+	tw := &writeTimeoutWriter{ResponseWriter: w, rc: http.NewResponseController(w), timeout: d}
+	stop := func() {
+		deadline := restore
+		if !deadline.IsZero() {
+			deadline = maxTime(deadline, time.Now().Add(d))
+		}
+		_ = tw.rc.SetWriteDeadline(deadline)
+		if err := tw.failure(); err != nil && onAbort != nil {
+			onAbort(err)
+		}
+	}
+	return tw, stop
+}
+
+// maxTime returns the later of a and b.
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// writeTimeoutWriter extends the write deadline before each write and records the first error.
+type writeTimeoutWriter struct {
+	http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+
+	mu  sync.Mutex
+	err error
+}
+
+func (w *writeTimeoutWriter) Write(b []byte) (int, error) {
+	_ = w.rc.SetWriteDeadline(time.Now().Add(w.timeout))
+	n, err := w.ResponseWriter.Write(b)
+	if err != nil {
+		w.fail(err)
+	}
+	return n, err
+}
+
+// Flush extends the write deadline before flushing.
+func (w *writeTimeoutWriter) Flush() {
+	_ = w.rc.SetWriteDeadline(time.Now().Add(w.timeout))
+	if err := w.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		w.fail(err)
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *writeTimeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// fail records err unless an error was already recorded.
+func (w *writeTimeoutWriter) fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// failure returns the first write error.
+func (w *writeTimeoutWriter) failure() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
@@ -0,0 +1,134 @@
+package responder_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+// deadlineRecorder is a ResponseRecorder that supports write deadlines and fails the writes
+// made after its deadline.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadlines []time.Time
+}
+
+func (w *deadlineRecorder) SetWriteDeadline(deadline time.Time) error {
+	w.deadlines = append(w.deadlines, deadline)
+	return nil
+}
+
+func (w *deadlineRecorder) Write(b []byte) (int, error) {
+	if deadline := w.deadlines[len(w.deadlines)-1]; !deadline.IsZero() && string(b) == "slow" {
+		return 0, os.ErrDeadlineExceeded
+	}
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestWriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc         string
+		givenBody    []string
+		givenTimeout time.Duration
+		wantBody     string
+		wantErr      error
+	}{
+		{
+			desc:      "fast client",
+			givenBody: []string{"a", "b"},
+			wantBody:  "ab",
+		},
+		{
+			desc:      "slow client | aborted",
+			givenBody: []string{"a", "slow", "b"},
+			wantBody:  "ab",
+			wantErr:   os.ErrDeadlineExceeded,
+		},
+		{
+			desc:         "server write timeout | restored",
+			givenBody:    []string{"a", "b"},
+			givenTimeout: time.Minute,
+			wantBody:     "ab",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			rec := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.givenTimeout > 0 {
+				srv := &http.Server{WriteTimeout: tc.givenTimeout}
+				r = r.WithContext(context.WithValue(r.Context(), http.ServerContextKey, srv))
+			}
+			start := time.Now()
+			var aborted error
+			w, stop := responder.WriteTimeout(rec, r, time.Second, func(err error) { aborted = err })
+
+			// When:
+			for _, b := range tc.givenBody {
+				_, _ = w.Write([]byte(b))
+			}
+			stop()
+
+			// Then: a deadline is set before each write, and the deadline of the server is
+			// restored at the end
+			if len(rec.deadlines) != len(tc.givenBody)+1 {
+				t.Fatalf("deadlines: want %d, got %d", len(tc.givenBody)+1, len(rec.deadlines))
+			}
+			for i, deadline := range rec.deadlines[:len(tc.givenBody)] {
+				if deadline.Before(start.Add(time.Second)) {
+					t.Errorf("deadline %d: want after %v, got %v", i, start.Add(time.Second), deadline)
+				}
+			}
+			last := rec.deadlines[len(rec.deadlines)-1]
+			if tc.givenTimeout == 0 && !last.IsZero() {
+				t.Errorf("last deadline: want zero, got %v", last)
+			}
+			if tc.givenTimeout > 0 && (last.Before(start.Add(tc.givenTimeout)) || last.After(time.Now().Add(tc.givenTimeout))) {
+				t.Errorf("last deadline: want %v from the start, got %v", tc.givenTimeout, last)
+			}
+			if got := rec.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+			if !errors.Is(aborted, tc.wantErr) {
+				t.Errorf("abort error: want %v, got %v", tc.wantErr, aborted)
+			}
+		})
+	}
+}
+
+func TestWriteTimeout_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	// Given: a writer without write deadlines
+	rec := httptest.NewRecorder()
+	called := false
+	w, stop := responder.WriteTimeout(rec, httptest.NewRequest(http.MethodGet, "/", nil), time.Second, func(error) { called = true })
+
+	// When:
+	_, _ = w.Write([]byte("ok"))
+	http.NewResponseController(w).Flush()
+	stop()
+
+	// Then: the writes are not limited
+	if got := rec.Body.String(); got != "ok" {
+		t.Errorf("body: want %q, got %q", "ok", got)
+	}
+	if !rec.Flushed {
+		t.Error("flushed: want true, got false")
+	}
+	if called {
+		t.Error("abort: want not called, got called")
+	}
+}
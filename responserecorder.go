@@ -0,0 +1,162 @@
+package httphandler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// ResponseRecorder is an http.ResponseWriter that records what is actually written through it:
+// the status code, the size of the body and the error that caused the response, whichever
+// Responder writes it. It is used by WithMeter, WithResponseObserver and LogRequests.
+type ResponseRecorder struct {
+	http.ResponseWriter
+
+	mu         sync.Mutex
+	statusCode int
+	bytes      int64
+	err        error
+	aborted    bool
+	// outer is the recorder of an enclosing handler, which errors are recorded in too.
+	outer *ResponseRecorder
+}
+
+// NewResponseRecorder returns a ResponseRecorder that writes to w.
+func NewResponseRecorder(w http.ResponseWriter) *ResponseRecorder {
+	return &ResponseRecorder{ResponseWriter: w}
+}
+
+func (w *ResponseRecorder) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.mu.Unlock()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *ResponseRecorder) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.mu.Lock()
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	w.bytes += int64(n)
+	w.mu.Unlock()
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status returns the status code written, or 200 OK if none was, as net/http sends it.
+// If the request was aborted before anything was written, StatusClientClosedRequest is returned.
+func (w *ResponseRecorder) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.statusCode == 0 {
+		if w.aborted {
+			return StatusClientClosedRequest
+		}
+		return http.StatusOK
+	}
+	return w.statusCode
+}
+
+// Written reports whether the status code or a part of the body has been written.
+func (w *ResponseRecorder) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.statusCode != 0
+}
+
+// Bytes returns the size of the body written.
+func (w *ResponseRecorder) Bytes() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.bytes
+}
+
+// Err returns the error that caused the response, if known, e.g. the error of a decoder,
+// a handler or an encoding failure, or the error of an error responder. See RecordError.
+func (w *ResponseRecorder) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Aborted reports whether the request was abandoned before a response was written, because
+// the client went away, e.g. when its context is cancelled while WithTimeout waits for the
+// handler. Nothing is sent for such requests.
+func (w *ResponseRecorder) Aborted() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.aborted && w.statusCode == 0
+}
+
+func (w *ResponseRecorder) recordAbort() {
+	w.mu.Lock()
+	w.aborted = true
+	w.mu.Unlock()
+	if w.outer != nil {
+		w.outer.recordAbort()
+	}
+}
+
+func (w *ResponseRecorder) recordError(err error) {
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.mu.Unlock()
+	if w.outer != nil {
+		w.outer.recordError(err)
+	}
+}
+
+// StatusClientClosedRequest is the non-standard status code reported by ResponseRecorder for
+// requests abandoned by the client before a response was written, as nginx logs them.
+const StatusClientClosedRequest = 499
+
+// recordAbort marks the recorders of r as aborted, see ResponseRecorder.Aborted.
+func recordAbort(r *http.Request) {
+	if rec, ok := r.Context().Value(responseRecorderKey{}).(*ResponseRecorder); ok {
+		rec.recordAbort()
+	}
+}
+
+// responseRecorderKey is the context key of the innermost *ResponseRecorder of a request.
+type responseRecorderKey struct{}
+
+// RecordResponse returns a ResponseRecorder that writes to w, and r with a context in which
+// the errors of the request are recorded in it, see RecordError. Middleware that wraps handlers
+// of this package uses it to learn the error that caused a response, as LogRequests does.
+func RecordResponse(w http.ResponseWriter, r *http.Request) (*ResponseRecorder, *http.Request) {
+	rec := NewResponseRecorder(w)
+	rec.outer, _ = r.Context().Value(responseRecorderKey{}).(*ResponseRecorder)
+	return rec, r.WithContext(context.WithValue(r.Context(), responseRecorderKey{}, rec))
+}
+
+// ResponseObserver is called with the ResponseRecorder of a request after its response is
+// written.
+type ResponseObserver func(r *http.Request, rec *ResponseRecorder)
+
+// WithResponseObserver calls observer after every response is written, including responses
+// for failed decoding and recovered panics, e.g. to record metrics of what was actually sent
+// by any Responder.
+func WithResponseObserver(observer ResponseObserver) HandlerOption {
+	return func(o *handlerOptions) {
+		o.responseObserver = observer
+	}
+}
+
+// observeHandler wraps h so that observer is called after the response is written.
+func observeHandler(h http.HandlerFunc, observer ResponseObserver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec, r := RecordResponse(w, r)
+		h(rec, r)
+		observer(r, rec)
+	}
+}
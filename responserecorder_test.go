@@ -0,0 +1,131 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/plainresp"
+)
+
+func TestWithResponseObserver(t *testing.T) {
+	t.Parallel()
+
+	errHandler := errors.New("handler failure")
+
+	testCases := []struct {
+		desc       string
+		given      func(opt httphandler.HandlerOption) http.HandlerFunc
+		wantStatus int
+		wantBytes  int64
+		wantErr    error
+	}{
+		{
+			desc: "third-party responder",
+			given: func(opt httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.Handle(func(r *http.Request) httphandler.Responder {
+					return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusAccepted)
+						w.Write([]byte("queued"))
+					})
+				}, opt)
+			},
+			wantStatus: http.StatusAccepted,
+			wantBytes:  int64(len("queued")),
+		},
+		{
+			desc: "nil responder",
+			given: func(opt httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.Handle(func(r *http.Request) httphandler.Responder {
+					return nil
+				}, opt)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			desc: "error responder",
+			given: func(opt httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.Handle(func(r *http.Request) httphandler.Responder {
+					return plainresp.Error(errHandler, "Conflict", http.StatusConflict)
+				}, opt)
+			},
+			wantStatus: http.StatusConflict,
+			wantBytes:  int64(len("Conflict\n")),
+			wantErr:    errHandler,
+		},
+		{
+			desc: "handler error",
+			given: func(opt httphandler.HandlerOption) http.HandlerFunc {
+				return httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+					return nil, errHandler
+				}, opt)
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBytes:  int64(len("Internal Server Error\n")),
+			wantErr:    errHandler,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var got *httphandler.ResponseRecorder
+			h := tc.given(httphandler.WithResponseObserver(func(r *http.Request, rec *httphandler.ResponseRecorder) {
+				got = rec
+			}))
+			w := httptest.NewRecorder()
+
+			// When:
+			h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			// Then:
+			if got == nil {
+				t.Fatal("observer: want called, got not called")
+			}
+			if got.Status() != tc.wantStatus {
+				t.Errorf("status code: want %d, got %d", tc.wantStatus, got.Status())
+			}
+			if got.Bytes() != tc.wantBytes || got.Bytes() != int64(w.Body.Len()) {
+				t.Errorf("bytes: want %d, got %d", tc.wantBytes, got.Bytes())
+			}
+			if !errors.Is(got.Err(), tc.wantErr) || (tc.wantErr == nil && got.Err() != nil) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, got.Err())
+			}
+		})
+	}
+}
+
+func TestResponseRecorder(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	rec := httphandler.NewResponseRecorder(w)
+	if rec.Written() {
+		t.Error("written: want false, got true")
+	}
+
+	// When:
+	rec.WriteHeader(http.StatusCreated)
+	rec.WriteHeader(http.StatusInternalServerError)
+	rec.Write([]byte("abc"))
+	rec.Write([]byte("de"))
+
+	// Then:
+	if !rec.Written() {
+		t.Error("written: want true, got false")
+	}
+	if rec.Status() != http.StatusCreated {
+		t.Errorf("status code: want %d, got %d", http.StatusCreated, rec.Status())
+	}
+	if rec.Bytes() != 5 {
+		t.Errorf("bytes: want 5, got %d", rec.Bytes())
+	}
+	if got := http.NewResponseController(rec).Flush(); got != nil {
+		t.Errorf("flush: want nil, got %v", got)
+	}
+}
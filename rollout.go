@@ -0,0 +1,33 @@
+package httphandler
+
+import "net/http"
+
+// RolloutGate reports whether a route is visible to the request. decoded is the input decoded
+// by the handler, e.g. a Tuple2 holding the user, or nil if the handler has no input or
+// decoding failed.
+type RolloutGate func(r *http.Request, decoded any) bool
+
+// WithRolloutGate dark-launches the route: requests for which gate returns false are sent
+// hidden, NotFound if it is nil, as if the route did not exist, and the handler is not called.
+// The gate is called after decoding, so that it can allow users by their decoded identity,
+// and is typically backed by a feature flag so that the route is opened without a redeploy.
+// A request that fails decoding is gated with a nil input, so that its error does not reveal
+// the route either. Prechecks, see WithPrecheck, still run first.
+func WithRolloutGate(gate RolloutGate, hidden Responder) HandlerOption {
+	return func(o *handlerOptions) {
+		o.rolloutGate = gate
+		o.hiddenResponder = hidden
+	}
+}
+
+// hidden returns the Responder to send if the rollout gate of the handler hides the route from
+// r, or nil if the route is visible.
+func (o handlerOptions) hidden(r *http.Request, decoded any) Responder {
+	if o.rolloutGate == nil || o.rolloutGate(r, decoded) {
+		return nil
+	}
+	if o.hiddenResponder == nil {
+		return NotFound()
+	}
+	return o.hiddenResponder
+}
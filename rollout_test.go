@@ -0,0 +1,104 @@
+package httphandler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestWithRolloutGate(t *testing.T) {
+	t.Parallel()
+
+	decodeUser := func(r *http.Request) (string, error) {
+		user := r.Header.Get("X-User")
+		if user == "" {
+			return "", errors.New("missing user")
+		}
+		return user, nil
+	}
+	allowlist := func(r *http.Request, decoded any) bool {
+		return decoded == "alice"
+	}
+	forbidden := httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	ok := func(r *http.Request, user string) httphandler.Responder {
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("hello " + user))
+		})
+	}
+
+	testCases := []struct {
+		desc      string
+		given     http.HandlerFunc
+		givenUser string
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			desc:      "allowed",
+			given:     httphandler.HandleWithDecoder(decodeUser, ok, httphandler.WithRolloutGate(allowlist, nil)),
+			givenUser: "alice",
+			wantCode:  http.StatusOK,
+			wantBody:  "hello alice",
+		},
+		{
+			desc:      "hidden",
+			given:     httphandler.HandleWithDecoder(decodeUser, ok, httphandler.WithRolloutGate(allowlist, nil)),
+			givenUser: "bob",
+			wantCode:  http.StatusNotFound,
+			wantBody:  "404 page not found",
+		},
+		{
+			desc:     "decode error hidden",
+			given:    httphandler.HandleWithDecoder(decodeUser, ok, httphandler.WithRolloutGate(allowlist, nil)),
+			wantCode: http.StatusNotFound,
+			wantBody: "404 page not found",
+		},
+		{
+			desc:      "custom hidden responder",
+			given:     httphandler.HandleWithDecoder(decodeUser, ok, httphandler.WithRolloutGate(allowlist, forbidden)),
+			givenUser: "bob",
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			desc: "handler without input",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				return nil
+			}, httphandler.WithRolloutGate(func(r *http.Request, decoded any) bool {
+				return r.Header.Get("X-User") == "alice"
+			}, nil)),
+			givenUser: "bob",
+			wantCode:  http.StatusNotFound,
+			wantBody:  "404 page not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/beta", nil)
+			if tc.givenUser != "" {
+				r.Header.Set("X-User", tc.givenUser)
+			}
+
+			// When:
+			tc.given(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package httphandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var ErrInsufficientScope = errors.New("insufficient scope")
+
+// ScopeError reports the scopes a principal lacks. It matches ErrInsufficientScope with errors.Is,
+// and the default decode error handler renders it with InsufficientScope.
+type ScopeError struct {
+	Missing []string
+}
+
+// Error implements the error interface.
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("%s: missing %s", ErrInsufficientScope, strings.Join(e.Missing, ", "))
+}
+
+// Is reports whether target is ErrInsufficientScope.
+func (e *ScopeError) Is(target error) bool {
+	return target == ErrInsufficientScope
+}
+
+// RequireScopes returns a RequestDecodeFunc that decodes the principal with decode and checks that
+// the scopes returned by extract include all of scopes. If any is missing, it fails with a
+// *ScopeError listing them. Errors from decode are returned as is.
+//
+// Decoders are opaque to the handler, so the scopes are recorded in the documentation of the
+// route by passing them to WithScopes too, e.g.
+//
+//	scopes := []string{"orders:write"}
+//	httphandler.HandleWithDecoder(httphandler.RequireScopes(auth, grantedScopes, scopes...), createOrder,
+//		httphandler.WithCatalog(catalog, "POST /orders"),
+//		httphandler.WithScopes(scopes...),
+//	)
+func RequireScopes[P any](decode RequestDecodeFunc[P], extract func(principal P) []string, scopes ...string) RequestDecodeFunc[P] {
+	return func(r *http.Request) (P, error) {
+		principal, err := decode(r)
+		if err != nil {
+			return principal, err
+		}
+
+		granted := extract(principal)
+		var missing []string
+		for _, scope := range scopes {
+			if !slices.Contains(granted, scope) {
+				missing = append(missing, scope)
+			}
+		}
+		if len(missing) > 0 {
+			return principal, &ScopeError{Missing: missing}
+		}
+
+		return principal, nil
+	}
+}
@@ -0,0 +1,114 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestRequireScopes(t *testing.T) {
+	t.Parallel()
+
+	type principal struct {
+		Name   string
+		Scopes []string
+	}
+
+	decode := func(r *http.Request) (principal, error) {
+		return principal{
+			Name:   "alice",
+			Scopes: strings.Fields(r.Header.Get("X-Scopes")),
+		}, nil
+	}
+	scopes := func(p principal) []string {
+		return p.Scopes
+	}
+
+	testCases := []struct {
+		desc             string
+		givenScopes      string
+		wantCode         int
+		wantAuthenticate string
+		wantBody         string
+	}{
+		{
+			desc:        "all scopes granted",
+			givenScopes: "orders:read orders:write",
+			wantCode:    http.StatusOK,
+			wantBody:    "alice",
+		},
+		{
+			desc:             "scope missing",
+			givenScopes:      "orders:read",
+			wantCode:         http.StatusForbidden,
+			wantAuthenticate: `Bearer error="insufficient_scope", scope="orders:write"`,
+			wantBody:         "Insufficient scope: missing orders:write\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+			r.Header.Set("X-Scopes", tc.givenScopes)
+
+			given := httphandler.HandleWithDecoder(
+				httphandler.RequireScopes(decode, scopes, "orders:read", "orders:write"),
+				func(r *http.Request, p principal) httphandler.Responder {
+					return httphandler.Raw("text/plain", []byte(p.Name))
+				},
+			)
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Header().Get("WWW-Authenticate"); got != tc.wantAuthenticate {
+				t.Errorf("www-authenticate: want '%s', got '%s'", tc.wantAuthenticate, got)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestWithScopes(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	catalog := httphandler.NewCatalog()
+	scopes := []string{"orders:read", "orders:write"}
+	httphandler.HandleWithDecoder(
+		httphandler.RequireScopes(func(r *http.Request) ([]string, error) {
+			return strings.Fields(r.Header.Get("X-Scopes")), nil
+		}, func(granted []string) []string { return granted }, scopes...),
+		func(r *http.Request, granted []string) httphandler.Responder { return nil },
+		httphandler.WithCatalog(catalog, "POST /orders"),
+		httphandler.WithScopes(scopes...),
+	)
+
+	// When:
+	entries := catalog.Entries()
+	desc := catalog.Describe("/orders")
+
+	// Then: the scopes are documented in the catalog and in the OPTIONS description
+	if len(entries) != 1 || !slices.Equal(entries[0].Scopes, scopes) {
+		t.Errorf("catalog scopes: want %v, got %+v", scopes, entries)
+	}
+	if !slices.Equal(desc.Scopes, scopes) {
+		t.Errorf("described scopes: want %v, got %v", scopes, desc.Scopes)
+	}
+}
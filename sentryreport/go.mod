@@ -0,0 +1,15 @@
+module github.com/alvinchoong/go-httphandler/sentryreport
+
+go 1.22
+
+require (
+	github.com/alvinchoong/go-httphandler v0.0.0
+	github.com/getsentry/sentry-go v0.35.3
+)
+
+require (
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/alvinchoong/go-httphandler => ../
@@ -0,0 +1,68 @@
+// Package sentryreport captures the errors of 5xx responses with Sentry.
+// It is a separate module so that the Sentry SDK stays an optional dependency.
+//
+//	httphandler.SetErrorReporter(sentryreport.New(nil))
+package sentryreport
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/getsentry/sentry-go"
+)
+
+// Ensure Reporter implements ErrorReporter.
+var _ httphandler.ErrorReporter = (*Reporter)(nil)
+
+// Reporter is an httphandler.ErrorReporter that captures errors with a Sentry hub.
+type Reporter struct {
+	hub *sentry.Hub
+}
+
+// New creates a Reporter that captures errors with hub, or with sentry.CurrentHub if hub is nil.
+// The hub in the request context, e.g. set by the sentryhttp middleware, takes precedence.
+func New(hub *sentry.Hub) *Reporter {
+	return &Reporter{hub: hub}
+}
+
+// CaptureException captures the error of the event with the request, the user and the tags
+// of the event, and the status code as the "status_code" tag.
+func (rep *Reporter) CaptureException(ctx context.Context, event httphandler.ErrorEvent) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = rep.hub
+		if hub == nil {
+			hub = sentry.CurrentHub()
+		}
+		hub = hub.Clone()
+	}
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		if event.Request != nil {
+			scope.SetRequest(event.Request)
+		}
+		scope.SetTag("status_code", strconv.Itoa(event.Status))
+		scope.SetTags(event.Tags)
+		if event.User != nil {
+			scope.SetUser(user(event.User))
+		}
+		hub.CaptureException(event.Err)
+	})
+}
+
+// user converts the value set with httphandler.SetErrorUser to a Sentry user.
+// A sentry.User is used as is, and any other value as the ID.
+func user(v any) sentry.User {
+	switch u := v.(type) {
+	case sentry.User:
+		return u
+	case *sentry.User:
+		return *u
+	case string:
+		return sentry.User{ID: u}
+	default:
+		return sentry.User{ID: fmt.Sprint(u)}
+	}
+}
@@ -0,0 +1,95 @@
+package sentryreport_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/sentryreport"
+	"github.com/getsentry/sentry-go"
+)
+
+func TestReporter_CaptureException(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		givenCtx func(hub *sentry.Hub) context.Context
+		givenHub bool
+	}{
+		{
+			desc:     "hub in context",
+			givenCtx: func(hub *sentry.Hub) context.Context { return sentry.SetHubOnContext(context.Background(), hub) },
+		},
+		{
+			desc:     "hub of reporter",
+			givenCtx: func(*sentry.Hub) context.Context { return context.Background() },
+			givenHub: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var got []*sentry.Event
+			client, err := sentry.NewClient(sentry.ClientOptions{
+				BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+					got = append(got, event)
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			hub := sentry.NewHub(client, sentry.NewScope())
+
+			var reporter *sentryreport.Reporter
+			if tc.givenHub {
+				reporter = sentryreport.New(hub)
+			} else {
+				reporter = sentryreport.New(sentry.NewHub(nil, sentry.NewScope()))
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+
+			// When:
+			reporter.CaptureException(tc.givenCtx(hub), httphandler.ErrorEvent{
+				Request: r,
+				Err:     errors.New("database failure"),
+				Status:  http.StatusInternalServerError,
+				User:    "alice",
+				Tags:    map[string]string{"tenant": "acme"},
+			})
+
+			// Then:
+			if len(got) != 1 {
+				t.Fatalf("event count: want 1, got %d", len(got))
+			}
+			event := got[0]
+
+			if len(event.Exception) == 0 || event.Exception[len(event.Exception)-1].Value != "database failure" {
+				t.Errorf("exception: want database failure, got %+v", event.Exception)
+			}
+
+			if event.User.ID != "alice" {
+				t.Errorf("user: want alice, got %s", event.User.ID)
+			}
+
+			wantTags := map[string]string{"tenant": "acme", "status_code": "500"}
+			for key, want := range wantTags {
+				if event.Tags[key] != want {
+					t.Errorf("tag %s: want %s, got %s", key, want, event.Tags[key])
+				}
+			}
+
+			if event.Request == nil || event.Request.URL != "http://example.com/orders" {
+				t.Errorf("request: want http://example.com/orders, got %+v", event.Request)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ServerErrorHook is called after a response with a 5xx status code is written.
+// route is the path of the request, and err is the error that caused the response if it is
+// known, e.g. the error returned by a handler, an encoding failure or a recovered panic.
+type ServerErrorHook func(ctx context.Context, route string, err error, status int)
+
+// serverErrorHook holds the hook set with OnServerError.
+var serverErrorHook atomic.Pointer[ServerErrorHook]
+
+// OnServerError sets the hook called whenever a handler created by Handle, HandleWithInput or
+// their variants writes a 5xx response, including for recovered panics and encoding failures,
+// so that alerting and error tracking are set up in one place. A panic that is not recovered
+// with WithPanicHandler is reported with status 500 before it is re-panicked.
+// Passing nil removes the hook.
+func OnServerError(hook ServerErrorHook) {
+	if hook == nil {
+		serverErrorHook.Store(nil)
+		return
+	}
+	serverErrorHook.Store(&hook)
+}
+
+// serverErrorKey is the context key of the *serverErrorWriter of a request.
+type serverErrorKey struct{}
+
+// serverErrorHandler wraps h so that 5xx responses are reported to the hook set with
+// OnServerError and the reporter set with SetErrorReporter. It does nothing if neither is set.
+func serverErrorHandler(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		hook, reporter := serverErrorHook.Load(), errorReporter.Load()
+		if hook == nil && reporter == nil {
+			h(w, r)
+			return
+		}
+
+		sw := &serverErrorWriter{ResponseWriter: w}
+		r = r.WithContext(context.WithValue(r.Context(), serverErrorKey{}, sw))
+
+		report := func(err error, status int) {
+			if hook != nil {
+				(*hook)(r.Context(), r.URL.Path, err, status)
+			}
+			if reporter != nil {
+				(*reporter).CaptureException(r.Context(), sw.event(r, err, status))
+			}
+		}
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if err, ok := recovered.(error); !ok || !errors.Is(err, http.ErrAbortHandler) {
+					report(panicError(recovered), http.StatusInternalServerError)
+				}
+				panic(recovered)
+			}
+			if sw.statusCode >= 500 {
+				report(sw.cause(), sw.statusCode)
+			}
+		}()
+
+		h(sw, r)
+	}
+}
+
+// serverErrorWriter records the status code of a response, the error that caused it and
+// the values set with SetErrorUser and SetErrorTag. The error and the values are locked since
+// a handler that timed out, see WithTimeout, may still record them from its own goroutine.
+type serverErrorWriter struct {
+	http.ResponseWriter
+	statusCode int
+
+	mu   sync.Mutex
+	err  error
+	user any
+	tags map[string]string
+}
+
+func (w *serverErrorWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *serverErrorWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter, for http.ResponseController.
+func (w *serverErrorWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// errorRecorder is implemented by the response writers that keep the error that caused the
+// response, e.g. to report or log it once the response is written.
+type errorRecorder interface {
+	recordError(err error)
+}
+
+func (w *serverErrorWriter) recordError(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// cause returns the error recorded as the cause of the response.
+func (w *serverErrorWriter) cause() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// recordServerError records err as the cause of the response written to w, in every
+// errorRecorder that w is or wraps. The first error recorded is kept.
+func recordServerError(w http.ResponseWriter, err error) {
+	for {
+		if rec, ok := w.(errorRecorder); ok {
+			rec.recordError(err)
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = u.Unwrap()
+	}
+}
+
+// RecordError records err as the cause of the response to r, for the hook set with
+// OnServerError, the reporter set with SetErrorReporter and the ResponseRecorder of the request.
+// Error responders call it with the error they were created with. The first error recorded
+// is kept, and a nil err is ignored.
+func RecordError(r *http.Request, err error) {
+	if err != nil {
+		recordRequestError(r, err)
+	}
+}
+
+// recordRequestError is like recordServerError for the writers of the request r.
+func recordRequestError(r *http.Request, err error) {
+	if sw, ok := r.Context().Value(serverErrorKey{}).(*serverErrorWriter); ok {
+		sw.recordError(err)
+	}
+	if rec, ok := r.Context().Value(responseRecorderKey{}).(*ResponseRecorder); ok {
+		rec.recordError(err)
+	}
+}
+
+// panicError returns the value recovered from a panic as an error.
+func panicError(recovered any) error {
+	if p, ok := recovered.(*handlerPanic); ok {
+		recovered = p.value
+	}
+	if err, ok := recovered.(error); ok {
+		return err
+	}
+	return fmt.Errorf("panic: %v", recovered)
+}
@@ -0,0 +1,149 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// TestOnServerError is not parallel because it changes the package-level hook.
+func TestOnServerError(t *testing.T) {
+	errHandler := errors.New("handler failure")
+	errEncode := errors.New("encode failure")
+
+	testCases := []struct {
+		desc        string
+		given       http.HandlerFunc
+		wantCalled  bool
+		wantErr     error
+		wantErrText string
+		wantStatus  int
+		wantPanic   bool
+	}{
+		{
+			desc: "success",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				return nil
+			}),
+			wantCalled: false,
+		},
+		{
+			desc: "client error",
+			given: httphandler.HandleWithInput(func(r *http.Request, input map[string]any) httphandler.Responder {
+				return nil
+			}),
+			wantCalled: false,
+		},
+		{
+			desc: "handler error",
+			given: httphandler.HandleE(func(r *http.Request) (httphandler.Responder, error) {
+				return nil, errHandler
+			}),
+			wantCalled: true,
+			wantErr:    errHandler,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			desc: "encode failure",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+					httphandler.WriteInternalServerError(w, nil, errEncode)
+				})
+			}),
+			wantCalled: true,
+			wantErr:    errEncode,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			desc: "5xx responder",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				})
+			}),
+			wantCalled: true,
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			desc: "recovered panic",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				panic("boom")
+			}, httphandler.WithPanicHandler(func(ctx context.Context, recovered any, stack []byte) httphandler.Responder {
+				return nil
+			})),
+			wantCalled:  true,
+			wantErrText: "panic: boom",
+			wantStatus:  http.StatusInternalServerError,
+		},
+		{
+			desc: "unrecovered panic",
+			given: httphandler.Handle(func(r *http.Request) httphandler.Responder {
+				panic("boom")
+			}),
+			wantCalled:  true,
+			wantErrText: "panic: boom",
+			wantStatus:  http.StatusInternalServerError,
+			wantPanic:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			var (
+				called    bool
+				gotRoute  string
+				gotErr    error
+				gotStatus int
+			)
+			httphandler.OnServerError(func(ctx context.Context, route string, err error, status int) {
+				called = true
+				gotRoute = route
+				gotErr = err
+				gotStatus = status
+			})
+			defer httphandler.OnServerError(nil)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+
+			// When:
+			func() {
+				defer func() {
+					if recovered := recover(); (recovered != nil) != tc.wantPanic {
+						t.Errorf("panic: want %t, got %v", tc.wantPanic, recovered)
+					}
+				}()
+				tc.given.ServeHTTP(w, r)
+			}()
+
+			// Then:
+			if called != tc.wantCalled {
+				t.Fatalf("called: want %t, got %t", tc.wantCalled, called)
+			}
+			if !called {
+				return
+			}
+
+			if gotRoute != "/orders" {
+				t.Errorf("route: want %s, got %s", "/orders", gotRoute)
+			}
+
+			if gotStatus != tc.wantStatus {
+				t.Errorf("status: want %d, got %d", tc.wantStatus, gotStatus)
+			}
+
+			if tc.wantErrText != "" {
+				if gotErr == nil || gotErr.Error() != tc.wantErrText {
+					t.Errorf("error: want %s, got %v", tc.wantErrText, gotErr)
+				}
+			} else if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, gotErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Package sigv4 verifies requests signed with AWS Signature Version 4, for services that use it
+// for service-to-service authentication. Only signatures in the Authorization header are
+// supported, not presigned URLs.
+package sigv4
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/canonical"
+)
+
+// Algorithm is the signing algorithm in the Authorization header.
+const Algorithm = "AWS4-HMAC-SHA256"
+
+// TimeFormat is the layout of the X-Amz-Date header.
+const TimeFormat = "20060102T150405Z"
+
+// UnsignedPayload is the X-Amz-Content-Sha256 value of a request whose body is not signed.
+const UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// defaultMaxBodyBytes is the default limit of the body read by Decode.
+const defaultMaxBodyBytes = 10 << 20
+
+var (
+	ErrMissingSignature = errors.New("missing sigv4 signature")
+	ErrInvalidSignature = errors.New("invalid sigv4 signature")
+	ErrExpiredSignature = errors.New("expired sigv4 signature")
+)
+
+// CredentialResolver returns the secret access key of an access key ID.
+// An error fails the verification and is returned wrapped.
+type CredentialResolver interface {
+	SecretKey(ctx context.Context, accessKeyID string) (string, error)
+}
+
+// CredentialResolverFunc is an adapter to allow the use of ordinary functions as CredentialResolvers.
+type CredentialResolverFunc func(ctx context.Context, accessKeyID string) (string, error)
+
+// SecretKey calls f(ctx, accessKeyID).
+func (f CredentialResolverFunc) SecretKey(ctx context.Context, accessKeyID string) (string, error) {
+	return f(ctx, accessKeyID)
+}
+
+// Options configures the verification of signatures.
+type Options struct {
+	// Region and Service, if not empty, must match the credential scope of the signature.
+	Region  string
+	Service string
+	// Tolerance is the accepted difference between the signing time and httphandler.Now.
+	// It defaults to 5 minutes.
+	Tolerance time.Duration
+	// S3 canonicalizes the path as Amazon S3 does, without normalizing or double-encoding it.
+	S3 bool
+	// AllowUnsignedPayload accepts requests with an X-Amz-Content-Sha256 header of
+	// UnsignedPayload, whose body is then not covered by the signature. Such requests are
+	// rejected by default. With Sign, the body is not hashed.
+	AllowUnsignedPayload bool
+	// MaxBodyBytes limits the body that Decode reads to compute its hash. It defaults to 10 MiB.
+	MaxBodyBytes int64
+}
+
+// Identity describes a request with a valid signature.
+type Identity struct {
+	AccessKeyID string
+	Region      string
+	Service     string
+	SignedAt    time.Time
+}
+
+// Verify checks the signature in the Authorization header of r, with body the content of the
+// request body. The X-Amz-Content-Sha256 header, if present, must be the hash of body, or
+// UnsignedPayload if opts.AllowUnsignedPayload is set.
+func Verify(r *http.Request, body []byte, resolver CredentialResolver, opts Options) (Identity, error) {
+	auth, err := parseAuthorization(r.Header.Get("Authorization"))
+	if err != nil {
+		return Identity{}, err
+	}
+	if opts.Region != "" && auth.region != opts.Region {
+		return Identity{}, fmt.Errorf("%w: region %q", ErrInvalidSignature, auth.region)
+	}
+	if opts.Service != "" && auth.service != opts.Service {
+		return Identity{}, fmt.Errorf("%w: service %q", ErrInvalidSignature, auth.service)
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	signedAt, err := time.Parse(TimeFormat, amzDate)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: malformed X-Amz-Date", ErrInvalidSignature)
+	}
+	if signedAt.Format("20060102") != auth.date {
+		return Identity{}, fmt.Errorf("%w: credential date does not match X-Amz-Date", ErrInvalidSignature)
+	}
+	tolerance := opts.Tolerance
+	if tolerance <= 0 {
+		tolerance = 5 * time.Minute
+	}
+	if age := httphandler.Now().Sub(signedAt); age > tolerance || age < -tolerance {
+		return Identity{}, fmt.Errorf("%w: signed %s ago", ErrExpiredSignature, age.Truncate(time.Second))
+	}
+
+	payloadHash := canonical.HashBody(body)
+	if h := r.Header.Get("X-Amz-Content-Sha256"); h != "" {
+		if h == UnsignedPayload && !opts.AllowUnsignedPayload {
+			return Identity{}, fmt.Errorf("%w: unsigned payload", ErrInvalidSignature)
+		}
+		if h != UnsignedPayload && h != payloadHash {
+			return Identity{}, fmt.Errorf("%w: body does not match X-Amz-Content-Sha256", ErrInvalidSignature)
+		}
+		payloadHash = h
+	}
+
+	secret, err := resolver.SecretKey(r.Context(), auth.accessKeyID)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	creq, err := canonical.Build(r, nil, canonical.Options{
+		Headers:          auth.signedHeaders,
+		NormalizePath:    !opts.S3,
+		DoubleEncodePath: !opts.S3,
+		PayloadHash:      payloadHash,
+	})
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	want := signature(secret, amzDate, auth.date, auth.region, auth.service, creq.String())
+	if !hmac.Equal(want, auth.signature) {
+		return Identity{}, ErrInvalidSignature
+	}
+
+	return Identity{
+		AccessKeyID: auth.accessKeyID,
+		Region:      auth.region,
+		Service:     auth.service,
+		SignedAt:    signedAt,
+	}, nil
+}
+
+// Decode returns a RequestDecodeFunc that verifies the signature of the request, see Verify.
+// The body is read to compute its hash and replaced, so that it can still be decoded. A body
+// over opts.MaxBodyBytes fails with an *http.MaxBytesError.
+func Decode(resolver CredentialResolver, opts Options) httphandler.RequestDecodeFunc[Identity] {
+	maxBytes := opts.MaxBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBodyBytes
+	}
+
+	return func(r *http.Request) (Identity, error) {
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = http.MaxBytesReader(nil, r.Body, maxBytes)
+		}
+		body, err := canonical.ReadBody(r)
+		if err != nil {
+			return Identity{}, err
+		}
+		return Verify(r, body, resolver, opts)
+	}
+}
+
+// Sign signs an outgoing request with body at httphandler.Now, setting the X-Amz-Date,
+// X-Amz-Content-Sha256 and Authorization headers. Host, X-Amz-* and Content-Type headers are
+// signed. It is meant for tests and internal clients; opts.Region and opts.Service must be set.
+// If opts.AllowUnsignedPayload is set, the body is not signed.
+func Sign(req *http.Request, body []byte, accessKeyID, secretKey string, opts Options) error {
+	now := httphandler.Now().UTC()
+	amzDate := now.Format(TimeFormat)
+	date := now.Format("20060102")
+	payloadHash := UnsignedPayload
+	if !opts.AllowUnsignedPayload {
+		payloadHash = canonical.HashBody(body)
+	}
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := []string{"host"}
+	for name := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "content-type" {
+			headers = append(headers, name)
+		}
+	}
+
+	creq, err := canonical.Build(req, nil, canonical.Options{
+		Headers:          headers,
+		NormalizePath:    !opts.S3,
+		DoubleEncodePath: !opts.S3,
+		PayloadHash:      payloadHash,
+	})
+	if err != nil {
+		return err
+	}
+
+	sig := signature(secretKey, amzDate, date, opts.Region, opts.Service, creq.String())
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s/%s/%s/aws4_request, SignedHeaders=%s, Signature=%s",
+		Algorithm, accessKeyID, date, opts.Region, opts.Service, creq.SignedHeaders, hex.EncodeToString(sig)))
+	return nil
+}
+
+// signature computes the signature of a canonical request.
+func signature(secret, amzDate, date, region, service, creq string) []byte {
+	sum := sha256.Sum256([]byte(creq))
+	stringToSign := strings.Join([]string{
+		Algorithm,
+		amzDate,
+		date + "/" + region + "/" + service + "/aws4_request",
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	return hmacSHA256(key, stringToSign)
+}
+
+// hmacSHA256 computes the HMAC-SHA256 of data with key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// authorization holds the parts of an Authorization header.
+type authorization struct {
+	accessKeyID   string
+	date          string
+	region        string
+	service       string
+	signedHeaders []string
+	signature     []byte
+}
+
+// parseAuthorization parses an Authorization header of the form
+// "AWS4-HMAC-SHA256 Credential=<id>/<date>/<region>/<service>/aws4_request,
+// SignedHeaders=<names>, Signature=<hex>".
+func parseAuthorization(header string) (authorization, error) {
+	if header == "" {
+		return authorization{}, ErrMissingSignature
+	}
+	algorithm, params, ok := strings.Cut(header, " ")
+	if !ok || algorithm != Algorithm {
+		return authorization{}, fmt.Errorf("%w: unsupported algorithm", ErrInvalidSignature)
+	}
+
+	var auth authorization
+	var credential string
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch key {
+		case "Credential":
+			credential = value
+		case "SignedHeaders":
+			auth.signedHeaders = strings.Split(value, ";")
+		case "Signature":
+			auth.signature, _ = hex.DecodeString(value)
+		}
+	}
+
+	scope := strings.Split(credential, "/")
+	if len(scope) != 5 || scope[4] != "aws4_request" || len(auth.signature) == 0 || len(auth.signedHeaders) == 0 {
+		return authorization{}, fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+	auth.accessKeyID, auth.date, auth.region, auth.service = scope[0], scope[1], scope[2], scope[3]
+
+	hasHost := false
+	for _, name := range auth.signedHeaders {
+		if name == "host" {
+			hasHost = true
+		}
+	}
+	if !hasHost {
+		return authorization{}, fmt.Errorf("%w: host is not signed", ErrInvalidSignature)
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,270 @@
+package sigv4_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/sigv4"
+)
+
+// The credentials and signature of the get-vanilla case of the AWS SigV4 test suite.
+const (
+	accessKeyID = "AKIDEXAMPLE"
+	secretKey   = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	vanillaAuth = "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+)
+
+var signedAt = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+// fixedClock is a Clock that always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var resolver = sigv4.CredentialResolverFunc(func(ctx context.Context, id string) (string, error) {
+	if id != accessKeyID {
+		return "", errors.New("unknown access key")
+	}
+	return secretKey, nil
+})
+
+// TestVerify is not parallel because it changes the package-level clock.
+func TestVerify(t *testing.T) {
+	httphandler.SetClock(fixedClock(signedAt.Add(time.Minute)))
+	defer httphandler.SetClock(nil)
+
+	vanilla := func(modify func(r *http.Request)) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "http://example.amazonaws.com/", nil)
+		r.Header.Set("X-Amz-Date", "20150830T123600Z")
+		r.Header.Set("Authorization", vanillaAuth)
+		if modify != nil {
+			modify(r)
+		}
+		return r
+	}
+
+	testCases := []struct {
+		desc      string
+		given     *http.Request
+		givenOpts sigv4.Options
+		wantErr   error
+	}{
+		{
+			desc:      "get vanilla",
+			given:     vanilla(nil),
+			givenOpts: sigv4.Options{Region: "us-east-1", Service: "service"},
+		},
+		{
+			desc:    "missing authorization",
+			given:   vanilla(func(r *http.Request) { r.Header.Del("Authorization") }),
+			wantErr: sigv4.ErrMissingSignature,
+		},
+		{
+			desc:    "tampered path",
+			given:   vanilla(func(r *http.Request) { r.URL.Path = "/admin" }),
+			wantErr: sigv4.ErrInvalidSignature,
+		},
+		{
+			desc:    "tampered host",
+			given:   vanilla(func(r *http.Request) { r.Host = "evil.example.com" }),
+			wantErr: sigv4.ErrInvalidSignature,
+		},
+		{
+			desc:      "wrong service",
+			given:     vanilla(nil),
+			givenOpts: sigv4.Options{Service: "other"},
+			wantErr:   sigv4.ErrInvalidSignature,
+		},
+		{
+			desc: "unknown access key",
+			given: vanilla(func(r *http.Request) {
+				r.Header.Set("Authorization", strings.Replace(vanillaAuth, accessKeyID, "AKIDOTHER", 1))
+			}),
+			wantErr: sigv4.ErrInvalidSignature,
+		},
+		{
+			desc: "host not signed",
+			given: vanilla(func(r *http.Request) {
+				r.Header.Set("Authorization", strings.Replace(vanillaAuth, "host;x-amz-date", "x-amz-date", 1))
+			}),
+			wantErr: sigv4.ErrInvalidSignature,
+		},
+		{
+			desc:    "unsupported algorithm",
+			given:   vanilla(func(r *http.Request) { r.Header.Set("Authorization", "AWS4-HMAC-SHA512 Credential=x") }),
+			wantErr: sigv4.ErrInvalidSignature,
+		},
+		{
+			desc:    "malformed date",
+			given:   vanilla(func(r *http.Request) { r.Header.Set("X-Amz-Date", "yesterday") }),
+			wantErr: sigv4.ErrInvalidSignature,
+		},
+		{
+			desc:      "expired",
+			given:     vanilla(nil),
+			givenOpts: sigv4.Options{Tolerance: 30 * time.Second},
+			wantErr:   sigv4.ErrExpiredSignature,
+		},
+		{
+			desc:    "content hash mismatch",
+			given:   vanilla(func(r *http.Request) { r.Header.Set("X-Amz-Content-Sha256", strings.Repeat("0", 64)) }),
+			wantErr: sigv4.ErrInvalidSignature,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// When:
+			id, err := sigv4.Verify(tc.given, nil, resolver, tc.givenOpts)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error: want %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr == nil {
+				want := sigv4.Identity{AccessKeyID: accessKeyID, Region: "us-east-1", Service: "service", SignedAt: signedAt}
+				if id != want {
+					t.Errorf("identity: want %+v, got %+v", want, id)
+				}
+			}
+		})
+	}
+}
+
+// TestDecode is not parallel because it changes the package-level clock.
+func TestDecode(t *testing.T) {
+	httphandler.SetClock(fixedClock(signedAt))
+	defer httphandler.SetClock(nil)
+
+	opts := sigv4.Options{Region: "eu-west-1", Service: "orders"}
+
+	testCases := []struct {
+		desc       string
+		givenBody  string
+		givenSent  string
+		givenQuery string
+		wantCode   int
+		wantBody   string
+	}{
+		{
+			desc:       "signed body",
+			givenBody:  `{"id":1}`,
+			givenSent:  `{"id":1}`,
+			givenQuery: "?a=1&b=x+y",
+			wantCode:   http.StatusOK,
+			wantBody:   `AKIDEXAMPLE:{"id":1}`,
+		},
+		{
+			desc:      "tampered body",
+			givenBody: `{"id":1}`,
+			givenSent: `{"id":2}`,
+			wantCode:  http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given: a request signed by a client
+			r := httptest.NewRequest(http.MethodPost, "http://orders.internal/orders/a%20b"+tc.givenQuery, strings.NewReader(tc.givenSent))
+			r.Header.Set("Content-Type", "application/json")
+			if err := sigv4.Sign(r, []byte(tc.givenBody), accessKeyID, secretKey, opts); err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+			h := httphandler.HandleWithDecoder(sigv4.Decode(resolver, opts), func(r *http.Request, id sigv4.Identity) httphandler.Responder {
+				return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+					body, _ := io.ReadAll(r.Body)
+					w.Write([]byte(id.AccessKeyID + ":" + string(body)))
+				})
+			},
+				httphandler.WithDecodeErrorHandler(func(r *http.Request, err error) httphandler.Responder {
+					return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusUnauthorized)
+					})
+				}),
+			)
+			w := httptest.NewRecorder()
+
+			// When:
+			h(w, r)
+
+			// Then: the body can still be read by the handler
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if w.Body.String() != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestDecode_Limits is not parallel because it changes the package-level clock.
+func TestDecode_Limits(t *testing.T) {
+	httphandler.SetClock(fixedClock(signedAt))
+	defer httphandler.SetClock(nil)
+
+	testCases := []struct {
+		desc          string
+		givenBody     string
+		givenSignOpts sigv4.Options
+		givenOpts     sigv4.Options
+		wantErr       error
+		wantTooLarge  bool
+	}{
+		{
+			desc:          "unsigned payload | rejected by default",
+			givenBody:     `{"id":1}`,
+			givenSignOpts: sigv4.Options{Region: "eu-west-1", Service: "orders", AllowUnsignedPayload: true},
+			givenOpts:     sigv4.Options{Region: "eu-west-1", Service: "orders"},
+			wantErr:       sigv4.ErrInvalidSignature,
+		},
+		{
+			desc:          "unsigned payload | allowed",
+			givenBody:     `{"id":1}`,
+			givenSignOpts: sigv4.Options{Region: "eu-west-1", Service: "orders", AllowUnsignedPayload: true},
+			givenOpts:     sigv4.Options{Region: "eu-west-1", Service: "orders", AllowUnsignedPayload: true},
+		},
+		{
+			desc:          "body over the limit",
+			givenBody:     `{"id":12345}`,
+			givenSignOpts: sigv4.Options{Region: "eu-west-1", Service: "orders"},
+			givenOpts:     sigv4.Options{Region: "eu-west-1", Service: "orders", MaxBodyBytes: 8},
+			wantTooLarge:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			r := httptest.NewRequest(http.MethodPost, "http://orders.internal/orders", strings.NewReader(tc.givenBody))
+			if err := sigv4.Sign(r, []byte(tc.givenBody), accessKeyID, secretKey, tc.givenSignOpts); err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+
+			// When:
+			_, err := sigv4.Decode(resolver, tc.givenOpts)(r)
+
+			// Then:
+			var maxErr *http.MaxBytesError
+			if tc.wantTooLarge {
+				if !errors.As(err, &maxErr) {
+					t.Errorf("error: want %T, got %v", maxErr, err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
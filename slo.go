@@ -0,0 +1,161 @@
+package httphandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timing is the time spent in the phases of handling a request.
+type Timing struct {
+	// Decode is the time spent decoding the input, zero for handlers without input.
+	Decode time.Duration
+	// Handle is the time spent in the handler.
+	Handle time.Duration
+	// Encode is the time spent by the Responder writing the response.
+	Encode time.Duration
+	// Total is the time spent from the start of the request to the end of the response.
+	Total time.Duration
+}
+
+// SLOViolationHandler is called for requests that take longer than their SLO target,
+// e.g. to log them or count them in a metric.
+type SLOViolationHandler func(r *http.Request, target time.Duration, timing Timing)
+
+// WithSLO declares a response time objective for the handler. Requests that take longer than
+// target are reported to onViolation after the response is written.
+func WithSLO(target time.Duration, onViolation SLOViolationHandler) HandlerOption {
+	return func(o *handlerOptions) {
+		o.sloTarget = target
+		o.sloViolationHandler = onViolation
+	}
+}
+
+// WithServerTiming sends the time spent decoding and handling the request in a Server-Timing
+// header, e.g. "decode;dur=1.2, handle;dur=35.7", for client-visible performance data.
+// Encoding is not included since the header is sent before the response is written.
+func WithServerTiming() HandlerOption {
+	return func(o *handlerOptions) {
+		o.serverTiming = true
+	}
+}
+
+// timingKey is the context key of the *requestTiming of a timed request.
+type timingKey struct{}
+
+// requestTiming collects the phase durations of a timed request.
+// Its methods do nothing on a nil receiver, so handlers can call them unconditionally.
+// It is locked since a handler that timed out, see WithTimeout, keeps running in its own
+// goroutine; the phases it observes after the request is done are dropped.
+type requestTiming struct {
+	serverTiming bool
+
+	mu        sync.Mutex
+	hasDecode bool
+	timing    Timing
+	done      bool
+}
+
+// timingFrom returns the requestTiming of the request, or nil if it is not timed.
+func timingFrom(r *http.Request) *requestTiming {
+	t, _ := r.Context().Value(timingKey{}).(*requestTiming)
+	return t
+}
+
+// start returns the start time of a phase. It avoids reading the clock for untimed requests.
+func (t *requestTiming) start() time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return Now()
+}
+
+// observeDecode records the time since start as the decode phase.
+func (t *requestTiming) observeDecode(start time.Time) {
+	if t == nil {
+		return
+	}
+	d := Now().Sub(start)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.done {
+		t.timing.Decode = d
+		t.hasDecode = true
+	}
+}
+
+// observeHandle records the time since start as the handle phase.
+func (t *requestTiming) observeHandle(start time.Time) {
+	if t == nil {
+		return
+	}
+	d := Now().Sub(start)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.done {
+		t.timing.Handle = d
+	}
+}
+
+// observeEncode records the time since start as the encode phase.
+func (t *requestTiming) observeEncode(start time.Time) {
+	if t == nil {
+		return
+	}
+	d := Now().Sub(start)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.done {
+		t.timing.Encode = d
+	}
+}
+
+// writeHeader sets the Server-Timing header if it was asked for with WithServerTiming.
+func (t *requestTiming) writeHeader(w http.ResponseWriter) {
+	if t == nil || !t.serverTiming {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var metrics []string
+	if t.hasDecode {
+		metrics = append(metrics, formatServerTiming("decode", t.timing.Decode))
+	}
+	metrics = append(metrics, formatServerTiming("handle", t.timing.Handle))
+	w.Header().Add("Server-Timing", strings.Join(metrics, ", "))
+}
+
+// formatServerTiming formats a Server-Timing metric with its duration in milliseconds.
+func formatServerTiming(name string, d time.Duration) string {
+	return fmt.Sprintf("%s;dur=%.1f", name, float64(d)/float64(time.Millisecond))
+}
+
+// timeHandler wraps h so that the phases of every request are timed.
+func timeHandler(h http.HandlerFunc, o handlerOptions) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t := &requestTiming{serverTiming: o.serverTiming}
+		r = r.WithContext(context.WithValue(r.Context(), timingKey{}, t))
+
+		start := Now()
+		h(w, r)
+		timing := t.finish(Now().Sub(start))
+
+		if o.sloTarget > 0 && o.sloViolationHandler != nil && timing.Total > o.sloTarget {
+			o.sloViolationHandler(r, o.sloTarget, timing)
+		}
+	}
+}
+
+// finish records total as the duration of the request and returns a copy of its timing.
+// Phases observed afterwards are dropped.
+func (t *requestTiming) finish(total time.Duration) Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timing.Total = total
+	t.done = true
+	return t.timing
+}
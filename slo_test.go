@@ -0,0 +1,134 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// steppingClock is a Clock that advances by step every time it is read.
+type steppingClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+// TestWithSLO is not parallel because it changes the package-level clock.
+func TestWithSLO(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		givenTarget      time.Duration
+		givenTiming      bool
+		wantViolation    bool
+		wantTiming       httphandler.Timing
+		wantServerTiming string
+	}{
+		{
+			desc:          "violated",
+			givenTarget:   50 * time.Millisecond,
+			wantViolation: true,
+			wantTiming: httphandler.Timing{
+				Decode: 10 * time.Millisecond,
+				Handle: 10 * time.Millisecond,
+				Encode: 10 * time.Millisecond,
+				Total:  70 * time.Millisecond,
+			},
+		},
+		{
+			desc:             "met | with server timing",
+			givenTarget:      100 * time.Millisecond,
+			givenTiming:      true,
+			wantViolation:    false,
+			wantServerTiming: "decode;dur=10.0, handle;dur=10.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			httphandler.SetClock(&steppingClock{step: 10 * time.Millisecond})
+			defer httphandler.SetClock(nil)
+
+			var gotViolation bool
+			var gotTiming httphandler.Timing
+			opts := []httphandler.HandlerOption{
+				httphandler.WithSLO(tc.givenTarget, func(r *http.Request, target time.Duration, timing httphandler.Timing) {
+					gotViolation = true
+					gotTiming = timing
+				}),
+			}
+			if tc.givenTiming {
+				opts = append(opts, httphandler.WithServerTiming())
+			}
+
+			given := httphandler.HandleWithInput(func(r *http.Request, input map[string]string) httphandler.Responder {
+				return &mockResponder{StatusCode: http.StatusOK}
+			}, opts...)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if gotViolation != tc.wantViolation {
+				t.Errorf("violation: want %t, got %t", tc.wantViolation, gotViolation)
+			}
+
+			if gotTiming != tc.wantTiming {
+				t.Errorf("timing: want %+v, got %+v", tc.wantTiming, gotTiming)
+			}
+
+			if got := w.Header().Get("Server-Timing"); got != tc.wantServerTiming {
+				t.Errorf("server timing: want '%s', got '%s'", tc.wantServerTiming, got)
+			}
+		})
+	}
+}
+
+func TestWithSLO_Timeout(t *testing.T) {
+	t.Parallel()
+
+	// Given: a handler that keeps running after it timed out
+	late := make(chan struct{})
+	var violated bool
+	h := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		<-r.Context().Done()
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer close(late)
+			w.WriteHeader(http.StatusOK)
+		})
+	},
+		httphandler.WithTimeout(10*time.Millisecond),
+		httphandler.WithServerTiming(),
+		httphandler.WithSLO(time.Nanosecond, func(r *http.Request, target time.Duration, timing httphandler.Timing) {
+			violated = true
+		}),
+	)
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	<-late
+
+	// Then: the 504 is timed, and the late handler does not race with it
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("status code: want %d, got %d", http.StatusGatewayTimeout, w.Code)
+	}
+	if !violated {
+		t.Error("SLO violation handler: want called, got not called")
+	}
+}
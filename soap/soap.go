@@ -0,0 +1,216 @@
+// Package soap serves SOAP 1.1 requests: it decodes the body of a request envelope into a typed
+// value and writes response envelopes and faults.
+package soap
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/responder"
+)
+
+const (
+	// ContentType is the media type of SOAP 1.1 messages.
+	ContentType = "text/xml; charset=utf-8"
+	// EnvelopeNamespace is the namespace of SOAP 1.1 envelopes.
+	EnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+)
+
+// Fault codes defined by SOAP 1.1.
+const (
+	FaultClient          = "soap:Client"
+	FaultServer          = "soap:Server"
+	FaultVersionMismatch = "soap:VersionMismatch"
+	FaultMustUnderstand  = "soap:MustUnderstand"
+)
+
+var ErrDecode = errors.New("fail to decode soap envelope")
+
+// requestEnvelope is a SOAP envelope whose body holds a T.
+type requestEnvelope[T any] struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
+	Body    struct {
+		Content T `xml:",any"`
+	} `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
+}
+
+// Body decodes the first element of the body of a SOAP envelope into a T, using its xml tags.
+// If T has an XMLName field with a name, an element with another name is rejected.
+func Body[T any](r *http.Request) (T, error) {
+	var env requestEnvelope[T]
+	if err := xml.NewDecoder(r.Body).Decode(&env); err != nil {
+		return env.Body.Content, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	return env.Body.Content, nil
+}
+
+// DecodeErrorHandler renders decoding failures as a soap:Client fault.
+// Use it with httphandler.WithDecodeErrorHandler.
+func DecodeErrorHandler(_ *http.Request, err error) httphandler.Responder {
+	return Fault(FaultClient, "Invalid request envelope").WithError(err)
+}
+
+// responseEnvelope is the SOAP envelope written by envelopeResponder.
+type responseEnvelope struct {
+	XMLName xml.Name     `xml:"soap:Envelope"`
+	NS      string       `xml:"xmlns:soap,attr"`
+	Body    responseBody `xml:"soap:Body"`
+}
+
+// responseBody holds either the encoded content or a fault.
+type responseBody struct {
+	Content []byte     `xml:",innerxml"`
+	Fault   *faultBody `xml:"soap:Fault,omitempty"`
+}
+
+// faultBody is the soap:Fault element.
+type faultBody struct {
+	Code   string    `xml:"faultcode"`
+	String string    `xml:"faultstring"`
+	Actor  string    `xml:"faultactor,omitempty"`
+	Detail *xmlInner `xml:"detail,omitempty"`
+}
+
+// xmlInner holds pre-encoded XML content.
+type xmlInner struct {
+	Content []byte `xml:",innerxml"`
+}
+
+// Ensure envelopeResponder implements Responder.
+var _ httphandler.Responder = (*envelopeResponder)(nil)
+
+// Success creates a responder that writes v, encoded with its xml tags, in the body of a
+// SOAP envelope with a default status code of 200 OK.
+func Success(v any) *envelopeResponder {
+	return &envelopeResponder{
+		statusCode: http.StatusOK,
+		content:    v,
+	}
+}
+
+// Fault creates a responder that writes a SOAP fault with the given code, e.g. FaultClient, and message.
+// Following the SOAP 1.1 HTTP binding, the default status code is 500 Internal Server Error.
+func Fault(code, message string) *envelopeResponder {
+	return &envelopeResponder{
+		statusCode: http.StatusInternalServerError,
+		fault:      &faultBody{Code: code, String: message},
+	}
+}
+
+// envelopeResponder handles SOAP HTTP responses.
+type envelopeResponder struct {
+	logger      httphandler.Logger
+	header      http.Header
+	override    http.Header
+	statusCode  int
+	cookies     []*http.Cookie
+	content     any
+	fault       *faultBody
+	faultDetail any
+	err         error
+}
+
+// Respond sends the SOAP envelope with custom headers, cookies and status code.
+func (res *envelopeResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = responder.Defer(w, res.header, res.override, res.cookies)
+
+	// Write the envelope.
+	b := responder.Encode(w, res.statusCode, ContentType, res, res.marshal, res.logger)
+	if b == nil {
+		return
+	}
+	responder.Log(res.logger, res.statusCode, res.err, "response_body", b)
+}
+
+// marshal encodes the envelope with its content or fault.
+func (res *envelopeResponder) marshal(any) ([]byte, error) {
+	env := responseEnvelope{NS: EnvelopeNamespace}
+
+	if res.fault != nil {
+		fault := *res.fault
+		if res.faultDetail != nil {
+			detail, err := xml.Marshal(res.faultDetail)
+			if err != nil {
+				return nil, err
+			}
+			fault.Detail = &xmlInner{Content: detail}
+		}
+		env.Body.Fault = &fault
+	} else if res.content != nil {
+		content, err := xml.Marshal(res.content)
+		if err != nil {
+			return nil, err
+		}
+		env.Body.Content = content
+	}
+
+	b, err := xml.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// WithDetail sets the application specific detail of a fault, encoded with its xml tags.
+func (res *envelopeResponder) WithDetail(detail any) *envelopeResponder {
+	res.faultDetail = detail
+	return res
+}
+
+// WithActor sets the URI of the node that caused a fault.
+func (res *envelopeResponder) WithActor(actor string) *envelopeResponder {
+	if res.fault != nil {
+		res.fault.Actor = actor
+	}
+	return res
+}
+
+// WithError sets the error that caused a fault. It is logged but not sent to the client.
+func (res *envelopeResponder) WithError(err error) *envelopeResponder {
+	res.err = err
+	return res
+}
+
+// WithLogger sets the logger for the responder.
+func (res *envelopeResponder) WithLogger(logger httphandler.Logger) *envelopeResponder {
+	res.logger = logger
+	return res
+}
+
+// WithStatus sets a custom HTTP status code for the response.
+func (res *envelopeResponder) WithStatus(status int) *envelopeResponder {
+	res.statusCode = status
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *envelopeResponder) WithHeader(key, value string) *envelopeResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *envelopeResponder) SetHeader(key, value string) *envelopeResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *envelopeResponder) WithCookie(cookie *http.Cookie) *envelopeResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
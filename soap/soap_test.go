@@ -0,0 +1,138 @@
+package soap_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/soap"
+)
+
+type GetPrice struct {
+	XMLName xml.Name `xml:"http://example.com/stock GetPrice"`
+	Symbol  string   `xml:"Symbol"`
+}
+
+type GetPriceResponse struct {
+	XMLName xml.Name `xml:"GetPriceResponse"`
+	Price   float64  `xml:"Price"`
+}
+
+func TestBody(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc    string
+		given   string
+		want    GetPrice
+		wantErr error
+	}{
+		{
+			desc: "valid envelope",
+			given: `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:m="http://example.com/stock">
+  <soap:Header><m:Trace>1</m:Trace></soap:Header>
+  <soap:Body><m:GetPrice><m:Symbol>ACME</m:Symbol></m:GetPrice></soap:Body>
+</soap:Envelope>`,
+			want: GetPrice{XMLName: xml.Name{Space: "http://example.com/stock", Local: "GetPrice"}, Symbol: "ACME"},
+		},
+		{
+			desc: "wrong operation",
+			given: `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body><GetStock xmlns="http://example.com/stock"/></soap:Body>
+</soap:Envelope>`,
+			wantErr: soap.ErrDecode,
+		},
+		{
+			desc:    "not an envelope",
+			given:   `<GetPrice xmlns="http://example.com/stock"/>`,
+			wantErr: soap.ErrDecode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodPost, "/soap", strings.NewReader(tc.given))
+
+			// When:
+			got, err := soap.Body[GetPrice](r)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+
+			if tc.wantErr == nil && got != tc.want {
+				t.Errorf("body: want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvelope_Respond(t *testing.T) {
+	t.Parallel()
+
+	type FaultDetail struct {
+		XMLName xml.Name `xml:"Reason"`
+		Text    string   `xml:",chardata"`
+	}
+
+	testCases := []struct {
+		desc     string
+		given    httphandler.Responder
+		wantCode int
+		wantBody string
+	}{
+		{
+			desc:     "success",
+			given:    soap.Success(&GetPriceResponse{Price: 34.5}),
+			wantCode: http.StatusOK,
+			wantBody: xml.Header + `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><GetPriceResponse><Price>34.5</Price></GetPriceResponse></soap:Body></soap:Envelope>`,
+		},
+		{
+			desc:     "fault | with detail",
+			given:    soap.Fault(soap.FaultClient, "Unknown symbol").WithDetail(FaultDetail{Text: "ACME is delisted"}).WithActor("http://example.com/stock"),
+			wantCode: http.StatusInternalServerError,
+			wantBody: xml.Header + `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>soap:Client</faultcode><faultstring>Unknown symbol</faultstring><faultactor>http://example.com/stock</faultactor><detail><Reason>ACME is delisted</Reason></detail></soap:Fault></soap:Body></soap:Envelope>`,
+		},
+		{
+			desc:     "decode error handler",
+			given:    soap.DecodeErrorHandler(nil, soap.ErrDecode),
+			wantCode: http.StatusInternalServerError,
+			wantBody: xml.Header + `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>soap:Client</faultcode><faultstring>Invalid request envelope</faultstring></soap:Fault></soap:Body></soap:Envelope>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/soap", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Header().Get("Content-Type"); got != soap.ContentType {
+				t.Errorf("content type: want '%s', got '%s'", soap.ContentType, got)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package httphandler
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"net/http"
+)
+
+// Variants of a handler created by Split.
+const (
+	VariantA = "a"
+	VariantB = "b"
+)
+
+// variantKey is the context key of the variant chosen by Split.
+type variantKey struct{}
+
+// Split returns a handler that serves each request with b if chooser returns true, and with a
+// otherwise, so that a new implementation of a route can be rolled out gradually, e.g. with
+// ChoosePercent or ChooseHeader. The chosen variant is available to a and b with Variant and
+// is recorded in Usage.Variant for per-variant metrics, see WithMeter.
+func Split(a, b http.HandlerFunc, chooser func(*http.Request) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h, variant := a, VariantA
+		if chooser(r) {
+			h, variant = b, VariantB
+		}
+		h(w, r.WithContext(context.WithValue(r.Context(), variantKey{}, variant)))
+	}
+}
+
+// Variant returns the variant chosen by Split for the request, VariantA or VariantB,
+// or an empty string if the request was not served by Split.
+func Variant(r *http.Request) string {
+	v, _ := r.Context().Value(variantKey{}).(string)
+	return v
+}
+
+// ChooseHeader returns a chooser for Split that chooses b for requests whose header key has
+// the given value, e.g. an opt-in header set by internal clients.
+func ChooseHeader(key, value string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		return r.Header.Get(key) == value
+	}
+}
+
+// ChoosePercent returns a chooser for Split that chooses b for percent of the requests.
+// If key is not nil and returns a non-empty string, e.g. a user ID, the choice is derived
+// from a hash of it, so that the same key always gets the same variant. Otherwise it is
+// random, see SetRand.
+func ChoosePercent(percent int, key func(*http.Request) string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		if percent <= 0 {
+			return false
+		}
+		if percent >= 100 {
+			return true
+		}
+
+		var n uint64
+		if k := keyOf(r, key); k != "" {
+			h := fnv.New64a()
+			h.Write([]byte(k))
+			n = h.Sum64()
+		} else {
+			var b [8]byte
+			if _, err := io.ReadFull(Rand(), b[:]); err != nil {
+				return false
+			}
+			n = binary.BigEndian.Uint64(b[:])
+		}
+		return n%100 < uint64(percent)
+	}
+}
+
+// keyOf returns key(r), or an empty string if key is nil.
+func keyOf(r *http.Request, key func(*http.Request) string) string {
+	if key == nil {
+		return ""
+	}
+	return key(r)
+}
@@ -0,0 +1,116 @@
+package httphandler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc        string
+		given       *http.Request
+		wantVariant string
+	}{
+		{
+			desc:        "header absent | variant a",
+			given:       httptest.NewRequest(http.MethodGet, "/", nil),
+			wantVariant: httphandler.VariantA,
+		},
+		{
+			desc: "header present | variant b",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.Header.Set("X-Canary", "1")
+				return r
+			}(),
+			wantVariant: httphandler.VariantB,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given: two metered variants
+			var usage httphandler.Usage
+			meter := httphandler.WithMeter(httphandler.MeterFunc(func(ctx context.Context, u httphandler.Usage) {
+				usage = u
+			}))
+			variant := func(name string) http.HandlerFunc {
+				return httphandler.Handle(func(r *http.Request) httphandler.Responder {
+					return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.Write([]byte(name + ":" + httphandler.Variant(r)))
+					})
+				}, meter)
+			}
+			h := httphandler.Split(variant("old"), variant("new"), httphandler.ChooseHeader("X-Canary", "1"))
+			w := httptest.NewRecorder()
+
+			// When:
+			h(w, tc.given)
+
+			// Then: the chosen variant serves the request and is recorded
+			wantBody := map[string]string{httphandler.VariantA: "old:a", httphandler.VariantB: "new:b"}[tc.wantVariant]
+			if w.Body.String() != wantBody {
+				t.Errorf("body: want %q, got %q", wantBody, w.Body.String())
+			}
+			if usage.Variant != tc.wantVariant {
+				t.Errorf("usage variant: want %q, got %q", tc.wantVariant, usage.Variant)
+			}
+		})
+	}
+}
+
+func TestChoosePercent(t *testing.T) {
+	t.Parallel()
+
+	userKey := func(r *http.Request) string {
+		return r.Header.Get("X-User")
+	}
+
+	testCases := []struct {
+		desc    string
+		percent int
+		wantMin int
+		wantMax int
+	}{
+		{desc: "none", percent: 0, wantMin: 0, wantMax: 0},
+		{desc: "all", percent: 100, wantMin: 1000, wantMax: 1000},
+		{desc: "half", percent: 50, wantMin: 400, wantMax: 600},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			choose := httphandler.ChoosePercent(tc.percent, userKey)
+
+			// When: 1000 users are assigned twice
+			var got int
+			for i := 0; i < 1000; i++ {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.Header.Set("X-User", strconv.Itoa(i))
+				first := choose(r)
+				if choose(r) != first {
+					t.Fatalf("user %d: want a sticky choice", i)
+				}
+				if first {
+					got++
+				}
+			}
+
+			// Then:
+			if got < tc.wantMin || got > tc.wantMax {
+				t.Errorf("chosen: want between %d and %d, got %d", tc.wantMin, tc.wantMax, got)
+			}
+		})
+	}
+}
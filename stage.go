@@ -0,0 +1,142 @@
+package httphandler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StageObserver is called after each decoder of a combined decoder runs, e.g. to export its
+// latency and outcome as metrics or trace spans. stage is the 1-based position of the decoder,
+// name is its name set with WithStageNames or an empty string, and err is its error, if any.
+type StageObserver func(ctx context.Context, stage int, name string, d time.Duration, err error)
+
+// WithStageObserver sets the observer of the decoders combined with CombineN, ParallelN and
+// ExtendNToM. A decoder that is not combined is not observed.
+func WithStageObserver(observer StageObserver) HandlerOption {
+	return func(o *handlerOptions) {
+		o.stageObserver = observer
+	}
+}
+
+// StageTracer is called before each decoder of a combined decoder runs, with the stage and name
+// passed to a StageObserver. It returns the context that the decoder runs with and a function
+// that is called with the decoder's error after it runs, e.g. to trace the stage as a span that
+// is the parent of the spans created by the decoder.
+type StageTracer func(ctx context.Context, stage int, name string) (context.Context, func(err error))
+
+// WithStageTracer sets the tracer of the decoders combined with CombineN, ParallelN and
+// ExtendNToM. A decoder that is not combined is not traced.
+func WithStageTracer(tracer StageTracer) HandlerOption {
+	return func(o *handlerOptions) {
+		o.stageTracer = tracer
+	}
+}
+
+// WithStageNames names the decoders of a combined decoder in order, e.g. "tenant", "user",
+// "product", for readable telemetry. See WithStageObserver.
+func WithStageNames(names ...string) HandlerOption {
+	return func(o *handlerOptions) {
+		o.stageNames = names
+	}
+}
+
+// ContextEnricher derives the context of a request from the values decoded by the stages of
+// its combined decoder, in stage order, or from the decoded input if the decoder is not
+// combined.
+type ContextEnricher func(ctx context.Context, stageValues ...any) context.Context
+
+// WithContextEnricher sets a function that places decoded values, e.g. the tenant and the user,
+// into the request context passed to the handler and its responder, for libraries such as
+// loggers and ORMs that read from the context. It is called only when decoding succeeds.
+func WithContextEnricher(enrich ContextEnricher) HandlerOption {
+	return func(o *handlerOptions) {
+		o.contextEnricher = enrich
+	}
+}
+
+// stageKey is the context key of the *stageRun of a request.
+type stageKey struct{}
+
+// stageRun holds the observer, the tracer and the stage names of a handler, and the values
+// decoded by the stages of a request.
+type stageRun struct {
+	observer StageObserver
+	tracer   StageTracer
+	names    []string
+
+	mu     sync.Mutex
+	values []any
+}
+
+// stageHandler wraps h so that the decoders run by runStage are observed and traced and their
+// values recorded.
+func stageHandler(h http.HandlerFunc, observer StageObserver, tracer StageTracer, names []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		run := &stageRun{observer: observer, tracer: tracer, names: names}
+		h(w, r.WithContext(context.WithValue(r.Context(), stageKey{}, run)))
+	}
+}
+
+// stageValues returns the values recorded by the stages of r, if any.
+func stageValues(r *http.Request) []any {
+	run, ok := r.Context().Value(stageKey{}).(*stageRun)
+	if !ok {
+		return nil
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	return run.values
+}
+
+// runStage runs decode as the given stage of a combined decoder, reporting it to the observer
+// and the tracer of the handler, if any, and recording its value. Its error is returned as a
+// *StageError.
+func runStage[T any](r *http.Request, stage int, decode RequestDecodeFunc[T]) (T, error) {
+	run, ok := r.Context().Value(stageKey{}).(*stageRun)
+	if !ok {
+		v, err := decode(r)
+		if err != nil {
+			return v, &StageError{Stage: stage, Err: err}
+		}
+		return v, nil
+	}
+
+	var name string
+	if stage <= len(run.names) {
+		name = run.names[stage-1]
+	}
+
+	ctx := r.Context()
+	var end func(err error)
+	if run.tracer != nil {
+		var stageCtx context.Context
+		stageCtx, end = run.tracer(ctx, stage, name)
+		r = r.WithContext(stageCtx)
+	}
+
+	start := Now()
+	v, err := decode(r)
+	if end != nil {
+		end(err)
+	}
+	if run.observer != nil {
+		run.observer(ctx, stage, name, Now().Sub(start), err)
+	}
+	if err != nil {
+		return v, &StageError{Stage: stage, Err: err}
+	}
+	run.record(stage, v)
+	return v, nil
+}
+
+// record stores the value of a stage.
+func (run *stageRun) record(stage int, v any) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	for len(run.values) < stage {
+		run.values = append(run.values, nil)
+	}
+	run.values[stage-1] = v
+}
@@ -0,0 +1,247 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestWithStageObserver(t *testing.T) {
+	t.Parallel()
+
+	type observation struct {
+		stage  int
+		name   string
+		failed bool
+	}
+
+	testCases := []struct {
+		desc       string
+		givenNames []string
+		givenReq   func() *http.Request
+		wantCode   int
+		want       []observation
+	}{
+		{
+			desc:       "all stages observed with names",
+			givenNames: []string{"tenant", "sort", "page"},
+			givenReq: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/?sort=name&page=2", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			},
+			wantCode: http.StatusOK,
+			want:     []observation{{1, "tenant", false}, {2, "sort", false}, {3, "page", false}},
+		},
+		{
+			desc:       "missing names | failing stage stops the pipeline",
+			givenNames: []string{"tenant"},
+			givenReq: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/", nil)
+			},
+			wantCode: http.StatusBadRequest,
+			want:     []observation{{1, "tenant", true}},
+		},
+		{
+			desc: "no names",
+			givenReq: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.Header.Set("X-Tenant", "acme")
+				return r
+			},
+			wantCode: http.StatusOK,
+			want:     []observation{{1, "", false}, {2, "", false}, {3, "", false}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			var mu sync.Mutex
+			var got []observation
+			observer := func(ctx context.Context, stage int, name string, d time.Duration, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, observation{stage: stage, name: name, failed: err != nil})
+			}
+			h := httphandler.HandleWithDecoder(
+				httphandler.Combine3(headerDecode("X-Tenant"), queryDecode("sort"), queryDecode("page")),
+				func(r *http.Request, in httphandler.Tuple3[string, string, string]) httphandler.Responder {
+					return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusOK)
+					})
+				},
+				httphandler.WithStageObserver(observer),
+				httphandler.WithStageNames(tc.givenNames...),
+			)
+			w := httptest.NewRecorder()
+
+			// When:
+			h(w, tc.givenReq())
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("observations: want %+v, got %+v", tc.want, got)
+			}
+			for i := range tc.want {
+				if got[i] != tc.want[i] {
+					t.Errorf("observation %d: want %+v, got %+v", i, tc.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithStageObserver_Parallel(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	var mu sync.Mutex
+	got := map[int]string{}
+	h := httphandler.HandleWithDecoder(
+		httphandler.Parallel2(queryDecode("a"), queryDecode("b")),
+		func(r *http.Request, in httphandler.Tuple2[string, string]) httphandler.Responder {
+			return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+		},
+		httphandler.WithStageObserver(func(ctx context.Context, stage int, name string, d time.Duration, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			got[stage] = name
+		}),
+		httphandler.WithStageNames("a", "b"),
+	)
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/?a=1&b=2", nil))
+
+	// Then:
+	if len(got) != 2 || got[1] != "a" || got[2] != "b" {
+		t.Errorf("observations: want map[1:a 2:b], got %v", got)
+	}
+}
+
+func TestWithStageTracer(t *testing.T) {
+	t.Parallel()
+
+	type stageKey struct{}
+
+	errUnknown := errors.New("unknown stage")
+
+	// Given: a tracer that places the stage into the context of its decoder
+	var got []string
+	decodeStage := func(r *http.Request) (string, error) {
+		stage, _ := r.Context().Value(stageKey{}).(string)
+		if stage == "b" {
+			return "", errUnknown
+		}
+		return stage, nil
+	}
+	h := httphandler.HandleWithDecoder(
+		httphandler.Combine2(decodeStage, decodeStage),
+		func(r *http.Request, in httphandler.Tuple2[string, string]) httphandler.Responder {
+			return nil
+		},
+		httphandler.WithStageTracer(func(ctx context.Context, stage int, name string) (context.Context, func(err error)) {
+			got = append(got, fmt.Sprintf("start %d %s", stage, name))
+			return context.WithValue(ctx, stageKey{}, name), func(err error) {
+				got = append(got, fmt.Sprintf("end %d %v", stage, err))
+			}
+		}),
+		httphandler.WithStageNames("a", "b"),
+	)
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Then: each stage runs between its start and its end, with the context of the tracer
+	want := []string{"start 1 a", "end 1 <nil>", "start 2 b", fmt.Sprintf("end 2 %v", errUnknown)}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("trace: want %v, got %v", want, got)
+	}
+}
+
+func TestWithContextEnricher(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	enricher := httphandler.WithContextEnricher(func(ctx context.Context, stageValues ...any) context.Context {
+		return context.WithValue(ctx, ctxKey{}, stageValues)
+	})
+	// respond writes the values placed into the context.
+	respond := func(r *http.Request) httphandler.Responder {
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, r.Context().Value(ctxKey{}))
+		})
+	}
+
+	testCases := []struct {
+		desc     string
+		given    http.HandlerFunc
+		wantCode int
+		wantBody string
+	}{
+		{
+			desc: "stage values of a combined decoder",
+			given: httphandler.HandleWithDecoder(httphandler.Combine2(headerDecode("X-Tenant"), queryDecode("user")), func(r *http.Request, _ httphandler.Tuple2[string, string]) httphandler.Responder {
+				return respond(r)
+			}, enricher),
+			wantCode: http.StatusOK,
+			wantBody: "[acme bob]",
+		},
+		{
+			desc: "input of a single decoder",
+			given: httphandler.HandleWithDecoder(queryDecode("user"), func(r *http.Request, _ string) httphandler.Responder {
+				return respond(r)
+			}, enricher),
+			wantCode: http.StatusOK,
+			wantBody: "[bob]",
+		},
+		{
+			desc: "decoding fails | not called",
+			given: httphandler.HandleWithDecoder(headerDecode("X-Missing"), func(r *http.Request, _ string) httphandler.Responder {
+				return respond(r)
+			}, enricher),
+			wantCode: http.StatusBadRequest,
+			wantBody: "Invalid request payload\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			r := httptest.NewRequest(http.MethodGet, "/?user=bob", nil)
+			r.Header.Set("X-Tenant", "acme")
+			w := httptest.NewRecorder()
+
+			// When:
+			tc.given(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+		})
+	}
+}
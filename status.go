@@ -0,0 +1,129 @@
+package httphandler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Ensure statusResponder implements Responder.
+var _ Responder = (*statusResponder)(nil)
+
+// PayloadTooLarge creates a 413 Payload Too Large response for a request body over limit bytes.
+// It is the default response when decoding fails because of WithMaxBodyBytes or http.MaxBytesReader.
+func PayloadTooLarge(limit int64) *statusResponder {
+	return &statusResponder{
+		statusCode: http.StatusRequestEntityTooLarge,
+		message:    fmt.Sprintf("Request body too large: limit is %d bytes", limit),
+	}
+}
+
+// UnsupportedMediaType creates a 415 Unsupported Media Type response.
+// The supported media types are advertised in the Accept-Post header.
+func UnsupportedMediaType(supported ...string) *statusResponder {
+	res := &statusResponder{
+		statusCode: http.StatusUnsupportedMediaType,
+		message:    http.StatusText(http.StatusUnsupportedMediaType),
+	}
+	if len(supported) > 0 {
+		res.WithHeader("Accept-Post", strings.Join(supported, ", "))
+	}
+	return res
+}
+
+// NotAcceptable creates a 406 Not Acceptable response listing the offered media types in the body.
+func NotAcceptable(offered ...string) *statusResponder {
+	message := http.StatusText(http.StatusNotAcceptable)
+	if len(offered) > 0 {
+		message += ": available representations are " + strings.Join(offered, ", ")
+	}
+
+	return &statusResponder{
+		statusCode: http.StatusNotAcceptable,
+		message:    message,
+	}
+}
+
+// InsufficientScope creates a 403 Forbidden response for a principal that lacks the missing scopes.
+// It sets WWW-Authenticate with error="insufficient_scope" (RFC 6750) and lists them in the body.
+func InsufficientScope(missing ...string) *statusResponder {
+	scope := strings.Join(missing, " ")
+
+	res := &statusResponder{
+		statusCode: http.StatusForbidden,
+		message:    "Insufficient scope: missing " + strings.Join(missing, ", "),
+	}
+	res.WithHeader("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, scope))
+	return res
+}
+
+// NotFound creates a 404 Not Found response with the same body as http.NotFound, so that it
+// cannot be told apart from a route that does not exist. See WithRolloutGate.
+func NotFound() *statusResponder {
+	return &statusResponder{
+		statusCode: http.StatusNotFound,
+		message:    "404 page not found",
+	}
+}
+
+// GatewayTimeout creates a 504 Gateway Timeout response.
+// It is the default response of a handler that times out, see WithTimeout.
+func GatewayTimeout() *statusResponder {
+	return &statusResponder{
+		statusCode: http.StatusGatewayTimeout,
+		message:    http.StatusText(http.StatusGatewayTimeout),
+	}
+}
+
+// statusResponder handles plain text responses for standard error statuses.
+type statusResponder struct {
+	logger     Logger
+	header     http.Header
+	override   http.Header
+	statusCode int
+	cookies    []*http.Cookie
+	message    string
+}
+
+// Respond sends the status code and message with custom headers and cookies.
+func (res *statusResponder) Respond(w http.ResponseWriter, _ *http.Request) {
+	// Apply cookies and custom headers when the status code is written.
+	w = deferHeaders(w, res.header, res.override, res.cookies)
+
+	// Set response body and status code.
+	http.Error(w, res.message, res.statusCode)
+	LogResponse(res.logger, res.statusCode, "response_body", res.message)
+}
+
+// WithLogger sets the logger for the responder.
+func (res *statusResponder) WithLogger(logger Logger) *statusResponder {
+	res.logger = logger
+	return res
+}
+
+// WithHeader adds a custom header to the response. A header that the responder sets itself,
+// e.g. Content-Type, takes precedence; use SetHeader to replace it.
+func (res *statusResponder) WithHeader(key, value string) *statusResponder {
+	if res.header == nil {
+		res.header = http.Header{}
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// SetHeader sets a custom header on the response, replacing the values added with WithHeader
+// and the value the responder sets itself, e.g. Content-Type.
+func (res *statusResponder) SetHeader(key, value string) *statusResponder {
+	if res.override == nil {
+		res.override = http.Header{}
+	}
+	res.override.Set(key, value)
+	res.header.Del(key)
+	return res
+}
+
+// WithCookie adds a cookie to the response.
+func (res *statusResponder) WithCookie(cookie *http.Cookie) *statusResponder {
+	res.cookies = append(res.cookies, cookie)
+	return res
+}
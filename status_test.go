@@ -0,0 +1,113 @@
+package httphandler_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestStatus_Respond(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc        string
+		given       httphandler.Responder
+		wantCode    int
+		wantHeaders map[string]string
+		wantBody    string
+	}{
+		{
+			desc:     "payload too large",
+			given:    httphandler.PayloadTooLarge(1024),
+			wantCode: http.StatusRequestEntityTooLarge,
+			wantBody: "Request body too large: limit is 1024 bytes",
+		},
+		{
+			desc:     "unsupported media type",
+			given:    httphandler.UnsupportedMediaType("application/json", "application/xml"),
+			wantCode: http.StatusUnsupportedMediaType,
+			wantHeaders: map[string]string{
+				"Accept-Post": "application/json, application/xml",
+			},
+			wantBody: "Unsupported Media Type",
+		},
+		{
+			desc:     "not acceptable",
+			given:    httphandler.NotAcceptable("application/json").WithHeader("X-Test-1", "test value 1"),
+			wantCode: http.StatusNotAcceptable,
+			wantHeaders: map[string]string{
+				"X-Test-1": "test value 1",
+			},
+			wantBody: "Not Acceptable: available representations are application/json",
+		},
+		{
+			desc:     "insufficient scope",
+			given:    httphandler.InsufficientScope("orders:write", "orders:delete"),
+			wantCode: http.StatusForbidden,
+			wantHeaders: map[string]string{
+				"WWW-Authenticate": `Bearer error="insufficient_scope", scope="orders:write orders:delete"`,
+			},
+			wantBody: "Insufficient scope: missing orders:write, orders:delete",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+			// When:
+			tc.given.Respond(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			for key, wantValue := range tc.wantHeaders {
+				if gotValue := w.Header().Get(key); gotValue != wantValue {
+					t.Errorf("headers %s: want %s, got %s", key, wantValue, gotValue)
+				}
+			}
+
+			gotBody := strings.TrimSpace(w.Body.String())
+			if gotBody != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, gotBody)
+			}
+		})
+	}
+}
+
+func TestHandleWithInput_MaxBytesError(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"a long name"}`))
+
+	given := httphandler.HandleWithDecoder(
+		func(r *http.Request) (map[string]string, error) {
+			_, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, 8))
+			return nil, err
+		},
+		func(r *http.Request, input map[string]string) httphandler.Responder {
+			t.Errorf("handler: should not be called on decoding failure")
+			return nil
+		},
+	)
+
+	// When:
+	given.ServeHTTP(w, r)
+
+	// Then:
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status code: want %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
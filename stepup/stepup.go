@@ -0,0 +1,104 @@
+// Package stepup decodes a one-time code sent with a request (e.g. a TOTP or an emailed code)
+// and verifies it for the already authenticated principal, so that handlers of sensitive
+// actions can require recent re-authentication.
+package stepup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/plainresp"
+)
+
+var (
+	ErrCodeRequired = errors.New("one-time code required")
+	ErrCodeInvalid  = errors.New("invalid one-time code")
+)
+
+// Verifier checks a one-time code for a principal.
+type Verifier[P any] interface {
+	VerifyCode(ctx context.Context, principal P, code string) error
+}
+
+// VerifierFunc is an adapter to allow the use of ordinary functions as Verifiers.
+type VerifierFunc[P any] func(ctx context.Context, principal P, code string) error
+
+// VerifyCode calls f(ctx, principal, code).
+func (f VerifierFunc[P]) VerifyCode(ctx context.Context, principal P, code string) error {
+	return f(ctx, principal, code)
+}
+
+// Proof is the input of handlers that require step-up authentication. Holding one means the
+// principal presented a valid one-time code with the request.
+type Proof[P any] struct {
+	Principal  P
+	Method     string
+	VerifiedAt time.Time
+}
+
+// Config configures Decode.
+type Config struct {
+	// Header is the request header holding the code. Defaults to "X-One-Time-Code".
+	Header string
+	// FormField is the form field holding the code when the header is absent. Defaults to "one_time_code".
+	FormField string
+	// Method names the kind of code in the Proof, e.g. "totp" or "email". Defaults to "otp".
+	Method string
+}
+
+// Decode returns a RequestDecodeFunc that decodes the principal with principal, reads the
+// one-time code from the request and checks it with verifier.
+// A missing code fails with ErrCodeRequired, and a code rejected by verifier with an error
+// wrapping ErrCodeInvalid and the verifier's error. Errors from principal are returned as is.
+func Decode[P any](principal httphandler.RequestDecodeFunc[P], verifier Verifier[P], cfg Config) httphandler.RequestDecodeFunc[Proof[P]] {
+	if cfg.Header == "" {
+		cfg.Header = "X-One-Time-Code"
+	}
+	if cfg.FormField == "" {
+		cfg.FormField = "one_time_code"
+	}
+	if cfg.Method == "" {
+		cfg.Method = "otp"
+	}
+
+	return func(r *http.Request) (Proof[P], error) {
+		p, err := principal(r)
+		if err != nil {
+			return Proof[P]{}, err
+		}
+
+		code := r.Header.Get(cfg.Header)
+		if code == "" {
+			code = r.PostFormValue(cfg.FormField)
+		}
+		if code == "" {
+			return Proof[P]{}, ErrCodeRequired
+		}
+
+		if err := verifier.VerifyCode(r.Context(), p, code); err != nil {
+			return Proof[P]{}, fmt.Errorf("%w: %w", ErrCodeInvalid, err)
+		}
+
+		return Proof[P]{
+			Principal:  p,
+			Method:     cfg.Method,
+			VerifiedAt: httphandler.Now(),
+		}, nil
+	}
+}
+
+// DecodeErrorHandler responds with 401 Unauthorized when the one-time code is missing or invalid,
+// and otherwise falls back to 400 Bad Request. Use it with httphandler.WithDecodeErrorHandler.
+func DecodeErrorHandler(_ *http.Request, err error) httphandler.Responder {
+	switch {
+	case errors.Is(err, ErrCodeRequired):
+		return plainresp.Error(err, "Step-up authentication required", http.StatusUnauthorized)
+	case errors.Is(err, ErrCodeInvalid):
+		return plainresp.Error(err, "Invalid one-time code", http.StatusUnauthorized)
+	}
+	return plainresp.Error(err, "Invalid request payload", http.StatusBadRequest)
+}
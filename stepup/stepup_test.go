@@ -0,0 +1,113 @@
+package stepup_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/stepup"
+)
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	errUnauthenticated := errors.New("unauthenticated")
+
+	principal := func(r *http.Request) (string, error) {
+		user := r.Header.Get("X-User")
+		if user == "" {
+			return "", errUnauthenticated
+		}
+		return user, nil
+	}
+	verifier := stepup.VerifierFunc[string](func(ctx context.Context, principal string, code string) error {
+		if code != "123456" {
+			return errors.New("code does not match")
+		}
+		return nil
+	})
+
+	testCases := []struct {
+		desc        string
+		givenUser   string
+		givenHeader string
+		givenForm   string
+		wantCode    int
+		wantBody    string
+	}{
+		{
+			desc:        "header code",
+			givenUser:   "alice",
+			givenHeader: "123456",
+			wantCode:    http.StatusOK,
+			wantBody:    "alice totp",
+		},
+		{
+			desc:      "form code",
+			givenUser: "alice",
+			givenForm: "one_time_code=123456",
+			wantCode:  http.StatusOK,
+			wantBody:  "alice totp",
+		},
+		{
+			desc:      "missing code",
+			givenUser: "alice",
+			wantCode:  http.StatusUnauthorized,
+			wantBody:  "Step-up authentication required\n",
+		},
+		{
+			desc:        "invalid code",
+			givenUser:   "alice",
+			givenHeader: "000000",
+			wantCode:    http.StatusUnauthorized,
+			wantBody:    "Invalid one-time code\n",
+		},
+		{
+			desc:        "principal error",
+			givenHeader: "123456",
+			wantCode:    http.StatusBadRequest,
+			wantBody:    "Invalid request payload\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/transfers", strings.NewReader(tc.givenForm))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tc.givenUser != "" {
+				r.Header.Set("X-User", tc.givenUser)
+			}
+			if tc.givenHeader != "" {
+				r.Header.Set("X-One-Time-Code", tc.givenHeader)
+			}
+
+			given := httphandler.HandleWithDecoder(
+				stepup.Decode(principal, verifier, stepup.Config{Method: "totp"}),
+				func(r *http.Request, proof stepup.Proof[string]) httphandler.Responder {
+					return httphandler.Raw("text/plain", []byte(proof.Principal+" "+proof.Method))
+				},
+				httphandler.WithDecodeErrorHandler(stepup.DecodeErrorHandler),
+			)
+
+			// When:
+			given.ServeHTTP(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want '%s', got '%s'", tc.wantBody, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package stepup
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// ErrCodeReused is the error of a TOTP code that was already accepted, see TOTP.Steps.
+var ErrCodeReused = errors.New("code already used")
+
+// TOTP is a Verifier for time-based one-time passwords (RFC 6238) with HMAC-SHA1.
+//
+// Without Steps there is NO replay protection: an accepted code is accepted again, by anyone
+// who observed it, until its time window ends. Set Steps to refuse reused codes.
+type TOTP[P any] struct {
+	// Secret returns the shared secret of the principal.
+	Secret func(ctx context.Context, principal P) ([]byte, error)
+	// Digits is the length of the codes, from 6 to 8. Defaults to 6.
+	Digits int
+	// Period is the time step, at least one second. Defaults to 30 seconds.
+	Period time.Duration
+	// Skew is the number of steps before and after the current one that are also accepted.
+	// Defaults to 0, and must not be negative.
+	Skew int
+	// Steps records the last accepted time step of each principal, so that a code, or a code
+	// of an earlier step, is refused once one was accepted. Nil disables replay protection.
+	Steps StepStore[P]
+}
+
+// VerifyCode implements Verifier. The current time is read from httphandler.Now.
+// An invalid Digits, Period or Skew is reported as an error instead of refusing every code.
+func (v TOTP[P]) VerifyCode(ctx context.Context, principal P, code string) error {
+	digits, period, err := v.settings()
+	if err != nil {
+		return err
+	}
+
+	secret, err := v.Secret(ctx, principal)
+	if err != nil {
+		return err
+	}
+
+	now := httphandler.Now()
+	for step := -v.Skew; step <= v.Skew; step++ {
+		t := now.Add(time.Duration(step) * period)
+		want := GenerateTOTP(secret, t, digits, period)
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) != 1 {
+			continue
+		}
+		if v.Steps == nil {
+			return nil
+		}
+		ok, err := v.Steps.Accept(ctx, principal, counter(t, period))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrCodeReused
+		}
+		return nil
+	}
+
+	return fmt.Errorf("code does not match")
+}
+
+// settings returns Digits and Period with their defaults applied, or an error if Digits,
+// Period or Skew is invalid.
+func (v TOTP[P]) settings() (int, time.Duration, error) {
+	digits, period := v.Digits, v.Period
+	switch {
+	case digits == 0:
+		digits = 6
+	case digits < 6 || digits > 8:
+		return 0, 0, fmt.Errorf("stepup: TOTP digits %d is not from 6 to 8", digits)
+	}
+	switch {
+	case period == 0:
+		period = 30 * time.Second
+	case period < time.Second:
+		return 0, 0, fmt.Errorf("stepup: TOTP period %v is under one second", period)
+	}
+	if v.Skew < 0 {
+		return 0, 0, fmt.Errorf("stepup: TOTP skew %d is negative", v.Skew)
+	}
+	return digits, period, nil
+}
+
+// StepStore records the last accepted TOTP time step of each principal. Implementations must
+// be safe for concurrent use; they may be backed by a shared store such as Redis.
+type StepStore[P any] interface {
+	// Accept records step for principal if it is later than the last recorded step, and
+	// reports whether it was.
+	Accept(ctx context.Context, principal P, step uint64) (bool, error)
+}
+
+// Ensure MemoryStepStore implements StepStore.
+var _ StepStore[string] = (*MemoryStepStore[string])(nil)
+
+// MemoryStepStore is an in-memory StepStore for a single instance. It keeps one entry per
+// principal that ever verified a code.
+type MemoryStepStore[P comparable] struct {
+	mu    sync.Mutex
+	steps map[P]uint64
+}
+
+// Accept implements StepStore.
+func (s *MemoryStepStore[P]) Accept(_ context.Context, principal P, step uint64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.steps[principal]; ok && step <= last {
+		return false, nil
+	}
+	if s.steps == nil {
+		s.steps = map[P]uint64{}
+	}
+	s.steps[principal] = step
+	return true, nil
+}
+
+// counter returns the TOTP counter of time t, with a period of at least one second.
+func counter(t time.Time, period time.Duration) uint64 {
+	return uint64(t.Unix() / int64(period/time.Second))
+}
+
+// GenerateTOTP returns the code for secret at time t, with the given number of digits and period.
+// A period under one second is replaced with the default of 30 seconds.
+func GenerateTOTP(secret []byte, t time.Time, digits int, period time.Duration) string {
+	if period < time.Second {
+		period = 30 * time.Second
+	}
+
+	var c [8]byte
+	binary.BigEndian.PutUint64(c[:], counter(t, period))
+
+	h := hmac.New(sha1.New, secret)
+	h.Write(c[:])
+	sum := h.Sum(nil)
+
+	// Dynamic truncation, RFC 4226 section 5.3.
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	// The modulus is a uint64 so that it does not overflow for more than 9 digits.
+	mod := uint64(1)
+	for range digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, uint64(value)%mod)
+}
@@ -0,0 +1,175 @@
+package stepup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/stepup"
+)
+
+func TestGenerateTOTP(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors from RFC 6238, appendix B (SHA1).
+	secret := []byte("12345678901234567890")
+
+	testCases := []struct {
+		desc  string
+		given int64
+		want  string
+	}{
+		{desc: "59", given: 59, want: "94287082"},
+		{desc: "1111111109", given: 1111111109, want: "07081804"},
+		{desc: "1234567890", given: 1234567890, want: "89005924"},
+		{desc: "2000000000", given: 2000000000, want: "69279037"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// When:
+			got := stepup.GenerateTOTP(secret, time.Unix(tc.given, 0), 8, 30*time.Second)
+
+			// Then:
+			if got != tc.want {
+				t.Errorf("code: want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+// fixedClock is a Clock that always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// TestTOTP_VerifyCode is not parallel because it changes the package-level clock.
+func TestTOTP_VerifyCode(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	httphandler.SetClock(fixedClock(now))
+	defer httphandler.SetClock(nil)
+
+	secret := []byte("12345678901234567890")
+	verifier := stepup.TOTP[string]{
+		Secret: func(ctx context.Context, principal string) ([]byte, error) {
+			return secret, nil
+		},
+		Skew: 1,
+	}
+
+	testCases := []struct {
+		desc    string
+		given   string
+		wantErr bool
+	}{
+		{desc: "current step", given: stepup.GenerateTOTP(secret, now, 6, 30*time.Second)},
+		{desc: "previous step within skew", given: stepup.GenerateTOTP(secret, now.Add(-30*time.Second), 6, 30*time.Second)},
+		{desc: "outside skew", given: stepup.GenerateTOTP(secret, now.Add(-90*time.Second), 6, 30*time.Second), wantErr: true},
+		{desc: "wrong length", given: "1234", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// When:
+			err := verifier.VerifyCode(context.Background(), "alice", tc.given)
+
+			// Then:
+			if (err != nil) != tc.wantErr {
+				t.Errorf("error: want error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestTOTP_InvalidConfig is not parallel because it changes the package-level clock.
+func TestTOTP_InvalidConfig(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	httphandler.SetClock(fixedClock(now))
+	defer httphandler.SetClock(nil)
+
+	secret := []byte("12345678901234567890")
+	code := stepup.GenerateTOTP(secret, now, 6, 30*time.Second)
+
+	testCases := []struct {
+		desc   string
+		digits int
+		period time.Duration
+		skew   int
+	}{
+		{desc: "too few digits", digits: 4},
+		{desc: "too many digits", digits: 10},
+		{desc: "period under one second", period: time.Millisecond},
+		{desc: "negative skew", skew: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// Given:
+			verifier := stepup.TOTP[string]{
+				Secret: func(ctx context.Context, principal string) ([]byte, error) {
+					return secret, nil
+				},
+				Digits: tc.digits,
+				Period: tc.period,
+				Skew:   tc.skew,
+			}
+
+			// When:
+			err := verifier.VerifyCode(context.Background(), "alice", code)
+
+			// Then:
+			if err == nil {
+				t.Error("error: want invalid config, got nil")
+			}
+		})
+	}
+
+	// A period under one second does not panic.
+	if got := stepup.GenerateTOTP(secret, now, 6, time.Millisecond); got != code {
+		t.Errorf("code: want %s, got %s", code, got)
+	}
+	// More than 9 digits do not overflow the modulus, the code is the truncated value padded.
+	if got := stepup.GenerateTOTP(secret, now, 10, 30*time.Second); len(got) != 10 || got[len(got)-6:] != code {
+		t.Errorf("code: want 10 digits ending in %s, got %s", code, got)
+	}
+}
+
+// TestTOTP_Replay is not parallel because it changes the package-level clock.
+func TestTOTP_Replay(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	httphandler.SetClock(fixedClock(now))
+	defer httphandler.SetClock(nil)
+
+	secret := []byte("12345678901234567890")
+	verifier := stepup.TOTP[string]{
+		Secret: func(ctx context.Context, principal string) ([]byte, error) {
+			return secret, nil
+		},
+		Skew:  1,
+		Steps: &stepup.MemoryStepStore[string]{},
+	}
+	current := stepup.GenerateTOTP(secret, now, 6, 30*time.Second)
+	previous := stepup.GenerateTOTP(secret, now.Add(-30*time.Second), 6, 30*time.Second)
+
+	// When: the current code is accepted
+	if err := verifier.VerifyCode(context.Background(), "alice", current); err != nil {
+		t.Fatalf("first use: want nil, got %v", err)
+	}
+
+	// Then: it and the code of an earlier step are refused, for that principal only
+	if err := verifier.VerifyCode(context.Background(), "alice", current); !errors.Is(err, stepup.ErrCodeReused) {
+		t.Errorf("reuse: want %v, got %v", stepup.ErrCodeReused, err)
+	}
+	if err := verifier.VerifyCode(context.Background(), "alice", previous); !errors.Is(err, stepup.ErrCodeReused) {
+		t.Errorf("earlier step: want %v, got %v", stepup.ErrCodeReused, err)
+	}
+	if err := verifier.VerifyCode(context.Background(), "bob", current); err != nil {
+		t.Errorf("other principal: want nil, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package httphandler
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Mount registers a stub handler on mux for every route of the catalog with examples, so that
+// frontend development can start before the real handlers exist. Routes that only have
+// documentation are left for their real handlers. Routes are registered as patterns,
+// e.g. "GET /users/{id}", and each stub renders an example of its route: the one named by a
+// "Prefer: example=name" header, or else the first one. An unknown example name results in
+// 404 Not Found.
+//
+// Examples added later are served too, but routes added later are not registered.
+func (c *Catalog) Mount(mux *http.ServeMux) {
+	for _, entry := range c.Entries() {
+		if len(entry.Examples) == 0 {
+			continue
+		}
+		mux.Handle(entry.Route, c.Stub(entry.Route))
+	}
+}
+
+// Stub returns a handler that renders an example of route, see Mount.
+func (c *Catalog) Stub(route string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := ParsePrefer(r).Extra["example"]
+		if !ok {
+			name = c.first(route)
+		}
+
+		res, ok := c.lookup(route, name)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		res.Respond(w, r)
+	})
+}
+
+// first returns the name of the first example of route.
+func (c *Catalog) first(route string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, cr := range c.routes {
+		if cr.route == route && len(cr.examples) > 0 {
+			return cr.examples[0].name
+		}
+	}
+	return ""
+}
+
+// fakeTime is the value of time.Time fields in values created by Fake.
+var fakeTime = time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+// Fake returns a value of type T filled with placeholder data derived from the type, for stub
+// responses, e.g.
+//
+//	user := httphandler.Fake[User]()
+//	catalog.Add("GET /users/{id}", "fake", jsonresp.Success(&user))
+//
+// Strings are set to the lower-cased field name, numbers to 1, booleans to true, time.Time to a
+// fixed date, and slices and maps get one element. Recursive types stop at the first repetition.
+func Fake[T any]() T {
+	var v T
+	fake(reflect.ValueOf(&v).Elem(), "string", map[reflect.Type]bool{})
+	return v
+}
+
+// fake fills v with placeholder data. name is used for strings, and seen holds the struct
+// types being filled to stop recursion.
+func fake(v reflect.Value, name string, seen map[reflect.Type]bool) {
+	if v.Type() == reflect.TypeOf(fakeTime) {
+		v.Set(reflect.ValueOf(fakeTime))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.Pointer:
+		if seen[v.Type().Elem()] {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		fake(v.Elem(), name, seen)
+	case reflect.Slice:
+		if seen[v.Type().Elem()] {
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fake(v.Index(0), name, seen)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fake(v.Index(i), name, seen)
+		}
+	case reflect.Map:
+		if seen[v.Type().Elem()] {
+			return
+		}
+		key := reflect.New(v.Type().Key()).Elem()
+		fake(key, "key", seen)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fake(elem, name, seen)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, elem)
+	case reflect.Struct:
+		seen[v.Type()] = true
+		defer delete(seen, v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fake(v.Field(i), strings.ToLower(field.Name), seen)
+		}
+	}
+}
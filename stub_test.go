@@ -0,0 +1,163 @@
+package httphandler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/jsonresp"
+)
+
+func TestCatalog_Mount(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc     string
+		given    *http.Request
+		wantCode int
+		wantBody string
+	}{
+		{
+			desc:     "first example",
+			given:    httptest.NewRequest(http.MethodGet, "/users/1", nil),
+			wantCode: http.StatusOK,
+			wantBody: `{"id":"1"}`,
+		},
+		{
+			desc: "preferred example",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+				r.Header.Set("Prefer", `example="not found"`)
+				return r
+			}(),
+			wantCode: http.StatusNotFound,
+			wantBody: `{"error":"Not found"}`,
+		},
+		{
+			desc: "unknown example",
+			given: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+				r.Header.Set("Prefer", "example=gone")
+				return r
+			}(),
+			wantCode: http.StatusNotFound,
+			wantBody: "404 page not found",
+		},
+		{
+			desc:     "added route without handler",
+			given:    httptest.NewRequest(http.MethodDelete, "/users/1", nil),
+			wantCode: http.StatusNoContent,
+		},
+		{
+			desc:     "route without examples is not mounted",
+			given:    httptest.NewRequest(http.MethodGet, "/health", nil),
+			wantCode: http.StatusNotFound,
+			wantBody: "404 page not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			catalog := newTestCatalog().Add("DELETE /users/{id}", "deleted", httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			mux := http.NewServeMux()
+			catalog.Mount(mux)
+			w := httptest.NewRecorder()
+
+			// When:
+			mux.ServeHTTP(w, tc.given)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tc.wantBody {
+				t.Errorf("body: want %s, got %s", tc.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestCatalog_Mount_DocumentedOnly(t *testing.T) {
+	t.Parallel()
+
+	// Given: a route with documentation but no examples
+	catalog := httphandler.NewCatalog()
+	httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		return nil
+	},
+		httphandler.WithCatalog(catalog, "PUT /users/{id}"),
+		httphandler.WithSummary("Replace a user"),
+	)
+	mux := http.NewServeMux()
+
+	// When:
+	catalog.Mount(mux)
+
+	// Then: no stub is registered, so the real handler can be
+	mux.HandleFunc("PUT /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/users/1", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status code: want %d, got %d", http.StatusNoContent, w.Code)
+	}
+}
+
+func TestFake(t *testing.T) {
+	t.Parallel()
+
+	type Node struct {
+		Name     string
+		Children []Node
+		Parent   *Node
+	}
+	type User struct {
+		ID        int64
+		Name      string
+		Admin     bool
+		Score     float64
+		Tags      []string
+		Labels    map[string]string
+		CreatedAt time.Time
+		Manager   *User
+		Tree      Node
+		secret    string
+	}
+
+	// When:
+	got := httphandler.Fake[User]()
+
+	// Then:
+	want := User{
+		ID:        1,
+		Name:      "name",
+		Admin:     true,
+		Score:     1,
+		Tags:      []string{"tags"},
+		Labels:    map[string]string{"key": "labels"},
+		CreatedAt: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+		Tree:      Node{Name: "name"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("value: want %+v, got %+v", want, got)
+	}
+
+	// Then: it can be used with responders
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	tags := httphandler.Fake[[]string]()
+	jsonresp.Success(&tags).Respond(w, r)
+	if got := strings.TrimSpace(w.Body.String()); got != `["string"]` {
+		t.Errorf("body: want %s, got %s", `["string"]`, got)
+	}
+}
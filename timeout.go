@@ -0,0 +1,162 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler/internal/buffered"
+)
+
+// WithTimeout limits the time the handler has to decode the request and build and send its
+// response to d. The request context passed to the decoders and the handler gets a deadline, so
+// that they can stop early, and if the handler has not returned in time, a 504 Gateway Timeout
+// is sent instead of its response, see WithTimeoutResponder.
+// The response is buffered until the handler returns, so the option is not suited to streaming
+// responses.
+func WithTimeout(d time.Duration) HandlerOption {
+	return func(o *handlerOptions) {
+		o.timeout = d
+	}
+}
+
+// WithTimeoutResponder sets the Responder sent when the handler times out, see WithTimeout.
+// By default a 504 Gateway Timeout with a plain text body is sent.
+func WithTimeoutResponder(res Responder) HandlerOption {
+	return func(o *handlerOptions) {
+		o.timeoutResponder = res
+	}
+}
+
+// timeoutHandler wraps h so that it is cancelled after d, sending res if it does not return in
+// time. If the request context is cancelled first, nothing is sent and the request is recorded
+// as aborted, see ResponseRecorder.Aborted. A panic in h is raised again in the calling
+// goroutine with the stack of h, so that Recover can handle it.
+func timeoutHandler(h http.HandlerFunc, d time.Duration, res Responder) http.HandlerFunc {
+	if res == nil {
+		res = GatewayTimeout()
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		tw := &timeoutWriter{buf: buffered.NewWriter()}
+		done := make(chan struct{})
+		panicked := make(chan *handlerPanic, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- newHandlerPanic(p)
+				}
+			}()
+			h(tw, r)
+			tw.mu.Lock()
+			tw.finished = ctx.Err() == nil
+			tw.mu.Unlock()
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			p.raise()
+		case <-done:
+		case <-ctx.Done():
+			// The handler may have returned or panicked just before the context was done.
+			tw.mu.Lock()
+			select {
+			case p := <-panicked:
+				tw.mu.Unlock()
+				p.raise()
+			default:
+			}
+			tw.timedOut = !tw.finished
+			tw.mu.Unlock()
+		}
+
+		switch {
+		case !tw.timedOut:
+			tw.buf.Respond(w, r)
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			res.Respond(w, r)
+		default:
+			// The client went away, so nothing is sent.
+			recordAbort(r)
+		}
+	}
+}
+
+// handlerPanic is a panic raised by a handler in another goroutine, with the stack of that
+// goroutine, which Recover passes to the PanicHandler instead of its own.
+type handlerPanic struct {
+	value any
+	stack []byte
+}
+
+// newHandlerPanic returns the handlerPanic of a value recovered in a worker goroutine, with the
+// stack of that goroutine. A handlerPanic raised by a nested Gather or WithTimeout is kept as
+// it is.
+func newHandlerPanic(recovered any) *handlerPanic {
+	if p, ok := recovered.(*handlerPanic); ok {
+		return p
+	}
+	return &handlerPanic{value: recovered, stack: debug.Stack()}
+}
+
+// raise panics again with p, or with its value for http.ErrAbortHandler, which net/http
+// compares by identity.
+func (p *handlerPanic) raise() {
+	if p.value == http.ErrAbortHandler {
+		panic(p.value)
+	}
+	panic(p)
+}
+
+func (p *handlerPanic) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+// Unwrap returns the value of the panic if it is an error.
+func (p *handlerPanic) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+// timeoutWriter buffers the response of a handler until it returns, and discards what it
+// writes after it timed out.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	buf      *buffered.Writer
+	timedOut bool
+	// finished is set if the handler returned before the context was done.
+	finished bool
+}
+
+// Header returns the header map.
+func (w *timeoutWriter) Header() http.Header {
+	return w.buf.Header()
+}
+
+// WriteHeader records the status code unless the handler timed out.
+func (w *timeoutWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.timedOut {
+		w.buf.WriteHeader(statusCode)
+	}
+}
+
+// Write buffers the body, or fails with http.ErrHandlerTimeout if the handler timed out.
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.buf.Write(b)
+}
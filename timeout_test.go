@@ -0,0 +1,221 @@
+package httphandler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	// slow waits for the request context to be done.
+	slow := func(r *http.Request) httphandler.Responder {
+		<-r.Context().Done()
+		return &mockResponder{StatusCode: http.StatusOK, Body: "late"}
+	}
+	fast := func(r *http.Request) httphandler.Responder {
+		return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Test", "1")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("on time"))
+		})
+	}
+
+	testCases := []struct {
+		desc         string
+		givenHandler httphandler.RequestHandler
+		givenOpts    []httphandler.HandlerOption
+		wantCode     int
+		wantBody     string
+		wantHeaders  map[string]string
+	}{
+		{
+			desc:         "on time | buffered response sent",
+			givenHandler: fast,
+			wantCode:     http.StatusCreated,
+			wantBody:     "on time",
+			wantHeaders:  map[string]string{"X-Test": "1"},
+		},
+		{
+			desc:         "timed out | 504",
+			givenHandler: slow,
+			wantCode:     http.StatusGatewayTimeout,
+			wantBody:     "Gateway Timeout\n",
+		},
+		{
+			desc:         "timed out | custom responder",
+			givenHandler: slow,
+			givenOpts: []httphandler.HandlerOption{
+				httphandler.WithTimeoutResponder(&mockResponder{StatusCode: http.StatusServiceUnavailable, Body: "busy"}),
+			},
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: "busy",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			h := httphandler.Handle(tc.givenHandler, append(tc.givenOpts, httphandler.WithTimeout(20*time.Millisecond))...)
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			// When:
+			h(w, r)
+
+			// Then:
+			if w.Code != tc.wantCode {
+				t.Errorf("status code: want %d, got %d", tc.wantCode, w.Code)
+			}
+			if got := w.Body.String(); got != tc.wantBody {
+				t.Errorf("body: want %q, got %q", tc.wantBody, got)
+			}
+			for k, v := range tc.wantHeaders {
+				if got := w.Header().Get(k); got != v {
+					t.Errorf("header %q: want %q, got %q", k, v, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTimeout_Decoder(t *testing.T) {
+	t.Parallel()
+
+	// Given: a decoder that waits for the deadline
+	deadline := make(chan bool, 1)
+	h := httphandler.HandleWithDecoder(func(r *http.Request) (string, error) {
+		_, ok := r.Context().Deadline()
+		deadline <- ok
+		<-r.Context().Done()
+		return "", r.Context().Err()
+	}, func(r *http.Request, input string) httphandler.Responder {
+		t.Error("handler: should not be called when decoding times out")
+		return nil
+	},
+		httphandler.WithTimeout(20*time.Millisecond),
+	)
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Then:
+	if !<-deadline {
+		t.Error("deadline: want set, got none")
+	}
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("status code: want %d, got %d", http.StatusGatewayTimeout, w.Code)
+	}
+}
+
+func TestWithTimeout_Panic(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	var gotStack []byte
+	h := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		panic("boom")
+	},
+		httphandler.WithTimeout(time.Second),
+		httphandler.WithPanicHandler(func(ctx context.Context, recovered any, stack []byte) httphandler.Responder {
+			gotStack = stack
+			return &mockResponder{StatusCode: http.StatusInternalServerError, Body: fmt.Sprint(recovered)}
+		}),
+	)
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// Then: the panic is recovered by the panic handler
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status code: want %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if got := w.Body.String(); got != "boom" {
+		t.Errorf("body: want %q, got %q", "boom", got)
+	}
+	// The stack is the one of the handler goroutine, where the panic happened.
+	if !strings.Contains(string(gotStack), "TestWithTimeout_Panic.func1") {
+		t.Errorf("stack: want the handler frame, got\n%s", gotStack)
+	}
+}
+
+func TestWithTimeout_ClientGone(t *testing.T) {
+	t.Parallel()
+
+	// Given: a handler still running when the client cancels the request
+	var metered bool
+	var gotStatus int
+	var gotAborted bool
+	h := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		<-r.Context().Done()
+		return &mockResponder{StatusCode: http.StatusOK, Body: "late"}
+	},
+		httphandler.WithTimeout(time.Second),
+		httphandler.WithMeter(httphandler.MeterFunc(func(ctx context.Context, usage httphandler.Usage) {
+			metered = true
+		})),
+		httphandler.WithResponseObserver(func(r *http.Request, rec *httphandler.ResponseRecorder) {
+			gotStatus, gotAborted = rec.Status(), rec.Aborted()
+		}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	// Then: nothing is sent, the request is recorded as aborted and not metered
+	if w.Body.Len() != 0 {
+		t.Errorf("body: want empty, got %q", w.Body.String())
+	}
+	if !gotAborted {
+		t.Error("aborted: want true, got false")
+	}
+	if gotStatus != httphandler.StatusClientClosedRequest {
+		t.Errorf("status code: want %d, got %d", httphandler.StatusClientClosedRequest, gotStatus)
+	}
+	if metered {
+		t.Error("meter: want not called for an aborted request")
+	}
+}
+
+// TestWithTimeout_OnServerError is not parallel because it changes the package-level hook.
+func TestWithTimeout_OnServerError(t *testing.T) {
+	// Given: a handler that records an error after it timed out
+	var gotStatus int
+	httphandler.OnServerError(func(ctx context.Context, route string, err error, status int) {
+		gotStatus = status
+	})
+	defer httphandler.OnServerError(nil)
+
+	late := make(chan struct{})
+	h := httphandler.Handle(func(r *http.Request) httphandler.Responder {
+		defer close(late)
+		<-r.Context().Done()
+		httphandler.RecordError(r, errors.New("late failure"))
+		return nil
+	}, httphandler.WithTimeout(10*time.Millisecond))
+	w := httptest.NewRecorder()
+
+	// When:
+	h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	<-late
+
+	// Then:
+	if gotStatus != http.StatusGatewayTimeout {
+		t.Errorf("hook status: want %d, got %d", http.StatusGatewayTimeout, gotStatus)
+	}
+}
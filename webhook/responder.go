@@ -0,0 +1,20 @@
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/internal/buffered"
+)
+
+// Signed wraps res so that its body is signed with secret at httphandler.Now, with the signature
+// sent in SignatureHeader. The response is buffered in memory to compute the signature.
+func Signed(res httphandler.Responder, secret []byte) httphandler.Responder {
+	return httphandler.ResponderFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := buffered.NewWriter()
+		res.Respond(buf, r)
+
+		buf.SentHeader().Set(SignatureHeader, Sign(secret, httphandler.Now(), buf.Body()))
+		buf.Respond(w, r)
+	})
+}
@@ -0,0 +1,40 @@
+package webhook_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/webhook"
+)
+
+func TestSigned_Respond(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	given := webhook.Signed(
+		httphandler.Raw("application/json", []byte(`{"event":"paid"}`)).
+			WithStatus(http.StatusAccepted).
+			WithHeader("X-Test-1", "test value 1"),
+		secret,
+	)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/relay", nil)
+
+	// When:
+	given.Respond(w, r)
+
+	// Then:
+	if w.Code != http.StatusAccepted {
+		t.Errorf("status code: want %d, got %d", http.StatusAccepted, w.Code)
+	}
+
+	if got := w.Header().Get("X-Test-1"); got != "test value 1" {
+		t.Errorf("header X-Test-1: want '%s', got '%s'", "test value 1", got)
+	}
+
+	if err := webhook.Verify(secret, w.Header().Get(webhook.SignatureHeader), w.Body.Bytes(), 0); err != nil {
+		t.Errorf("verify: want nil, got %v", err)
+	}
+}
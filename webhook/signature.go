@@ -0,0 +1,156 @@
+// Package webhook signs and verifies webhook payloads with an HMAC-SHA256 signature bound to a
+// timestamp, sent in a single header of the form "t=<unix seconds>,v1=<hex signature>".
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+)
+
+// SignatureHeader is the header that carries the signature.
+const SignatureHeader = "Webhook-Signature"
+
+// defaultMaxBodyBytes is the default limit of the body read by VerifiedBody.
+const defaultMaxBodyBytes = 10 << 20
+
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrExpiredSignature = errors.New("expired webhook signature")
+)
+
+// Sign returns the signature header value for body signed with secret at the given time.
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac(secret, t, body))
+}
+
+// Verify checks that header is a valid signature of body with secret.
+// If tolerance is positive, signatures older or newer than tolerance, according to
+// httphandler.Now, fail with ErrExpiredSignature. Any of several v1 signatures may match,
+// which allows rotating secrets.
+func Verify(secret []byte, header string, body []byte, tolerance time.Duration) error {
+	if header == "" {
+		return ErrMissingSignature
+	}
+
+	var t string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed header", ErrInvalidSignature)
+	}
+
+	if tolerance > 0 {
+		if age := httphandler.Now().Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: signed %s ago", ErrExpiredSignature, age.Truncate(time.Second))
+		}
+	}
+
+	want := mac(secret, t, body)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, want) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+// mac computes the HMAC-SHA256 of "<timestamp>.<body>".
+func mac(secret []byte, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// VerifiedBody returns a RequestDecodeFunc that reads the request body and verifies its signature
+// in SignatureHeader, see Verify. It returns the raw body for the handler to decode. A body over
+// maxBytes, or 10 MiB if maxBytes is 0 or less, fails with an *http.MaxBytesError.
+func VerifiedBody(secret []byte, tolerance time.Duration, maxBytes int64) httphandler.RequestDecodeFunc[[]byte] {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBodyBytes
+	}
+
+	return func(r *http.Request) ([]byte, error) {
+		body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBytes))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := Verify(secret, r.Header.Get(SignatureHeader), body, tolerance); err != nil {
+			return nil, err
+		}
+
+		return body, nil
+	}
+}
+
+// SignRequest signs the body of an outgoing request with secret at httphandler.Now and sets
+// SignatureHeader. The body is read and replaced so that it can still be sent.
+func SignRequest(req *http.Request, secret []byte) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	req.Header.Set(SignatureHeader, Sign(secret, httphandler.Now(), body))
+	return nil
+}
+
+// Transport is an http.RoundTripper that signs every request with SignRequest, for handlers that
+// relay webhooks to subscribers.
+type Transport struct {
+	Secret []byte
+	// Base is the underlying RoundTripper. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrip must not modify the request.
+	req = req.Clone(req.Context())
+	if err := SignRequest(req, t.Secret); err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,173 @@
+package webhook_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alvinchoong/go-httphandler"
+	"github.com/alvinchoong/go-httphandler/webhook"
+)
+
+var (
+	secret   = []byte("whsec_test")
+	signedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+)
+
+// fixedClock is a Clock that always returns the same time.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// TestVerify is not parallel because it changes the package-level clock.
+func TestVerify(t *testing.T) {
+	httphandler.SetClock(fixedClock(signedAt.Add(time.Minute)))
+	defer httphandler.SetClock(nil)
+
+	body := []byte(`{"event":"paid"}`)
+	valid := webhook.Sign(secret, signedAt, body)
+
+	testCases := []struct {
+		desc           string
+		givenHeader    string
+		givenBody      []byte
+		givenTolerance time.Duration
+		wantErr        error
+	}{
+		{
+			desc:           "valid",
+			givenHeader:    valid,
+			givenBody:      body,
+			givenTolerance: 5 * time.Minute,
+		},
+		{
+			desc:        "valid | rotated secret",
+			givenHeader: valid + ",v1=" + strings.Repeat("00", 32),
+			givenBody:   body,
+		},
+		{
+			desc:        "missing",
+			givenHeader: "",
+			givenBody:   body,
+			wantErr:     webhook.ErrMissingSignature,
+		},
+		{
+			desc:        "tampered body",
+			givenHeader: valid,
+			givenBody:   []byte(`{"event":"refunded"}`),
+			wantErr:     webhook.ErrInvalidSignature,
+		},
+		{
+			desc:        "malformed",
+			givenHeader: "v1=abc",
+			givenBody:   body,
+			wantErr:     webhook.ErrInvalidSignature,
+		},
+		{
+			desc:           "expired",
+			givenHeader:    valid,
+			givenBody:      body,
+			givenTolerance: 30 * time.Second,
+			wantErr:        webhook.ErrExpiredSignature,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			// When:
+			err := webhook.Verify(secret, tc.givenHeader, tc.givenBody, tc.givenTolerance)
+
+			// Then:
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: want %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifiedBody(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		desc          string
+		givenMaxBytes int64
+		wantTooLarge  bool
+	}{
+		{
+			desc: "default limit",
+		},
+		{
+			desc:          "body over the limit",
+			givenMaxBytes: 8,
+			wantTooLarge:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// Given:
+			body := `{"event":"paid"}`
+			r := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+			r.Header.Set(webhook.SignatureHeader, webhook.Sign(secret, signedAt, []byte(body)))
+
+			// When:
+			got, err := webhook.VerifiedBody(secret, 0, tc.givenMaxBytes)(r)
+
+			// Then:
+			var maxErr *http.MaxBytesError
+			if tc.wantTooLarge {
+				if !errors.As(err, &maxErr) {
+					t.Errorf("error: want %T, got %v", maxErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error: want nil, got %v", err)
+			}
+
+			if string(got) != body {
+				t.Errorf("body: want '%s', got '%s'", body, got)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	// Given:
+	server := httptest.NewServer(httphandler.HandleWithDecoder(
+		webhook.VerifiedBody(secret, time.Minute, 0),
+		func(r *http.Request, body []byte) httphandler.Responder {
+			return httphandler.Raw("text/plain", body)
+		},
+	))
+	defer server.Close()
+
+	client := &http.Client{Transport: &webhook.Transport{Secret: secret}}
+
+	// When:
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"event":"paid"}`))
+	if err != nil {
+		t.Fatalf("error: want nil, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Then:
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status code: want %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != `{"event":"paid"}` {
+		t.Errorf("body: want '%s', got '%s'", `{"event":"paid"}`, got)
+	}
+}